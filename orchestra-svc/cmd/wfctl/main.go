@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/sqlc"
+	"orchestra-svc/internal/usecase"
+	"orchestra-svc/pkg"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const usage = `wfctl manages workflow definitions of orchestra-svc.
+
+Usage:
+  wfctl validate -f <file> [-topics a,b]
+  wfctl import   -f <file> [-topics a,b]
+  wfctl export   -type <workflow type> [-o <file>] [-format yaml|json]
+  wfctl export   -all -dir <directory> [-format yaml|json]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "validate":
+		err = runValidate(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	file := fs.String("f", "", "definition file (.yaml, .yml or .json)")
+	topics := fs.String("topics", "", "comma separated list of known topics, defaults to WORKFLOW_TOPICS")
+	fs.Parse(args)
+
+	def, err := readDefinition(*file)
+	if err != nil {
+		return err
+	}
+
+	err = usecase.ValidateDefinition(def, knownTopics(*topics))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%s is valid", *file)
+	return nil
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("f", "", "definition file (.yaml, .yml or .json)")
+	topics := fs.String("topics", "", "comma separated list of known topics, defaults to WORKFLOW_TOPICS")
+	fs.Parse(args)
+
+	def, err := readDefinition(*file)
+	if err != nil {
+		return err
+	}
+
+	dc := newDefinitionUsecase()
+
+	err = dc.Import(context.Background(), def, knownTopics(*topics))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("imported workflow %s", def.Type)
+	return nil
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	workflowType := fs.String("type", "", "workflow type to export")
+	all := fs.Bool("all", false, "export every workflow")
+	dir := fs.String("dir", ".", "output directory used with -all")
+	output := fs.String("o", "", "output file, defaults to stdout")
+	format := fs.String("format", "yaml", "output format: yaml or json")
+	fs.Parse(args)
+
+	dc := newDefinitionUsecase()
+	ctx := context.Background()
+
+	if *all {
+		defs, err := dc.ExportAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, def := range defs {
+			path := filepath.Join(*dir, fmt.Sprintf("%s.%s", def.Type, *format))
+			if err := writeDefinition(path, &def, *format); err != nil {
+				return err
+			}
+			log.Printf("exported workflow %s to %s", def.Type, path)
+		}
+		return nil
+	}
+
+	if *workflowType == "" {
+		return fmt.Errorf("either -type or -all is required")
+	}
+
+	def, err := dc.Export(ctx, *workflowType)
+	if err != nil {
+		return err
+	}
+
+	return writeDefinition(*output, def, *format)
+}
+
+func newDefinitionUsecase() *usecase.DefinitionUsecase {
+	config := pkg.LoadConfig()
+	db := pkg.NewDBConn(config.DBDriver, config.DBSource)
+	return usecase.NewDefinitionUsecase(sqlc.NewStore(db))
+}
+
+func knownTopics(flagValue string) []string {
+	if flagValue != "" {
+		return strings.Split(flagValue, ",")
+	}
+	return pkg.LoadConfig().WorkflowTopics
+}
+
+func readDefinition(path string) (*dto.WorkflowDefinition, error) {
+	if path == "" {
+		return nil, fmt.Errorf("definition file is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var def dto.WorkflowDefinition
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&def)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&def)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return &def, nil
+}
+
+func writeDefinition(path string, def *dto.WorkflowDefinition, format string) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch format {
+	case "json":
+		data, err = json.MarshalIndent(def, "", "  ")
+		data = append(data, '\n')
+	case "yaml":
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		err = encoder.Encode(def)
+		data = buf.Bytes()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,2 @@
+-- Instance Payloads
+DROP TABLE IF EXISTS instance_payloads;
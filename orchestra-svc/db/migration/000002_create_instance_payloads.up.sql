@@ -0,0 +1,13 @@
+-- Instance Payloads
+CREATE TABLE instance_payloads (
+    id SERIAL PRIMARY KEY,
+    workflow_instance_id VARCHAR NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (workflow_instance_id, source)
+);
+
+CREATE INDEX idx_instance_payloads_expires_at ON instance_payloads (expires_at);
@@ -0,0 +1,2 @@
+-- Steps
+ALTER TABLE steps ADD COLUMN compensation_step_id INTEGER REFERENCES steps(id);
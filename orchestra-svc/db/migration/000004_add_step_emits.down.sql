@@ -0,0 +1,2 @@
+-- Steps
+ALTER TABLE steps DROP COLUMN IF EXISTS emits;
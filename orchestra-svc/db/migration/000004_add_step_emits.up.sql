@@ -0,0 +1,2 @@
+-- Steps
+ALTER TABLE steps ADD COLUMN emits VARCHAR(255)[] NOT NULL DEFAULT '{}';
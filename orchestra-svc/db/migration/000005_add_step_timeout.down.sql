@@ -0,0 +1,3 @@
+-- Steps
+ALTER TABLE steps DROP COLUMN IF EXISTS timeout_action;
+ALTER TABLE steps DROP COLUMN IF EXISTS timeout_seconds;
@@ -0,0 +1,3 @@
+-- Steps
+ALTER TABLE steps ADD COLUMN timeout_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE steps ADD COLUMN timeout_action VARCHAR(20) NOT NULL DEFAULT 'fail';
@@ -0,0 +1,9 @@
+-- Workflow Instance Steps
+DROP INDEX IF EXISTS idx_workflow_instance_steps_next_retry_at;
+ALTER TABLE workflow_instance_steps DROP COLUMN IF EXISTS next_retry_at;
+ALTER TABLE workflow_instance_steps DROP COLUMN IF EXISTS attempts;
+
+-- Steps
+ALTER TABLE steps DROP COLUMN IF EXISTS retryable_status_codes;
+ALTER TABLE steps DROP COLUMN IF EXISTS retry_base_delay_ms;
+ALTER TABLE steps DROP COLUMN IF EXISTS max_attempts;
@@ -0,0 +1,11 @@
+-- Steps
+ALTER TABLE steps ADD COLUMN max_attempts INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE steps ADD COLUMN retry_base_delay_ms INTEGER NOT NULL DEFAULT 1000;
+ALTER TABLE steps ADD COLUMN retryable_status_codes INTEGER[] NOT NULL DEFAULT '{429,500,502,503,504}';
+
+-- Workflow Instance Steps
+ALTER TABLE workflow_instance_steps ADD COLUMN attempts INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE workflow_instance_steps ADD COLUMN next_retry_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_workflow_instance_steps_next_retry_at ON workflow_instance_steps (next_retry_at)
+    WHERE status = 'retry_scheduled';
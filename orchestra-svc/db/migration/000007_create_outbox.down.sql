@@ -0,0 +1,2 @@
+-- Outbox
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,2 @@
+-- Processed events
+DROP TABLE IF EXISTS processed_events;
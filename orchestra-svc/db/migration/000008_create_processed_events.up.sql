@@ -0,0 +1,8 @@
+-- Processed events
+CREATE TABLE processed_events (
+    event_id VARCHAR(255) NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    state VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, source)
+);
@@ -0,0 +1,2 @@
+-- Dead letters
+DROP TABLE IF EXISTS dead_letters;
@@ -0,0 +1,21 @@
+-- Dead letters
+CREATE TABLE dead_letters (
+    id SERIAL PRIMARY KEY,
+    service VARCHAR(100) NOT NULL,
+    topic VARCHAR(255) NOT NULL,
+    kafka_partition INTEGER NOT NULL,
+    kafka_offset BIGINT NOT NULL,
+    message_key TEXT NOT NULL DEFAULT '',
+    payload BYTEA NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    replay_count INTEGER NOT NULL DEFAULT 0,
+    failed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    replayed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (service, topic, kafka_partition, kafka_offset)
+);
+
+CREATE INDEX idx_dead_letters_status ON dead_letters (status);
@@ -0,0 +1,2 @@
+-- Step dependencies
+DROP TABLE IF EXISTS step_dependencies;
@@ -0,0 +1,8 @@
+-- Step dependencies
+CREATE TABLE step_dependencies (
+    id SERIAL PRIMARY KEY,
+    step_id INTEGER NOT NULL REFERENCES steps(id),
+    state VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (step_id, state)
+);
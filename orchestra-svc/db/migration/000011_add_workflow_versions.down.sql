@@ -0,0 +1,7 @@
+-- Workflow versions
+DROP INDEX IF EXISTS idx_state_actions_type_version_state;
+
+ALTER TABLE workflow_instances DROP COLUMN IF EXISTS workflow_version;
+ALTER TABLE state_actions DROP COLUMN IF EXISTS version;
+ALTER TABLE steps DROP COLUMN IF EXISTS version;
+ALTER TABLE workflows DROP COLUMN IF EXISTS version;
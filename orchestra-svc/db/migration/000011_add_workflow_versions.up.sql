@@ -0,0 +1,7 @@
+-- Workflow versions
+ALTER TABLE workflows ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE steps ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE state_actions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE workflow_instances ADD COLUMN workflow_version INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX idx_state_actions_type_version_state ON state_actions (type, version, state);
@@ -0,0 +1,2 @@
+-- Payload mappings
+DROP TABLE IF EXISTS payload_mappings;
@@ -0,0 +1,11 @@
+-- Payload mappings
+CREATE TABLE payload_mappings (
+    id SERIAL PRIMARY KEY,
+    step_id INTEGER NOT NULL REFERENCES steps(id),
+    target VARCHAR(255) NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    default_value JSONB NOT NULL DEFAULT 'null',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (step_id, target)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE workflow_instance_steps DROP COLUMN guard_result;
+ALTER TABLE workflow_instance_steps DROP COLUMN guard;
+
+ALTER TABLE state_actions DROP COLUMN guard;
@@ -0,0 +1,5 @@
+-- State action guards
+ALTER TABLE state_actions ADD COLUMN guard TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE workflow_instance_steps ADD COLUMN guard TEXT NOT NULL DEFAULT '';
+ALTER TABLE workflow_instance_steps ADD COLUMN guard_result BOOLEAN;
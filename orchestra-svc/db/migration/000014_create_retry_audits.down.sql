@@ -0,0 +1,2 @@
+-- Retry audits
+DROP TABLE IF EXISTS retry_audits;
@@ -0,0 +1,13 @@
+-- Retry audits
+CREATE TABLE retry_audits (
+    id SERIAL PRIMARY KEY,
+    workflow_instance_id VARCHAR NOT NULL REFERENCES workflow_instances(id),
+    event_id VARCHAR NOT NULL,
+    patch_type VARCHAR(20) NOT NULL,
+    patch JSONB NOT NULL,
+    original_message TEXT NOT NULL,
+    retried_message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_retry_audits_instance ON retry_audits (workflow_instance_id);
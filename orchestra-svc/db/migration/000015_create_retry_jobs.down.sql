@@ -0,0 +1,3 @@
+-- Bulk retry jobs
+DROP TABLE IF EXISTS retry_job_items;
+DROP TABLE IF EXISTS retry_jobs;
@@ -0,0 +1,26 @@
+-- Bulk retry jobs
+CREATE TABLE retry_jobs (
+    id VARCHAR PRIMARY KEY,
+    filter JSONB NOT NULL DEFAULT '{}',
+    rate INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    total INTEGER NOT NULL,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE retry_job_items (
+    id SERIAL PRIMARY KEY,
+    job_id VARCHAR NOT NULL REFERENCES retry_jobs(id),
+    event_id VARCHAR NOT NULL,
+    workflow_instance_id VARCHAR NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error TEXT NOT NULL DEFAULT '',
+    processed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (job_id, event_id)
+);
+
+CREATE INDEX idx_retry_jobs_status ON retry_jobs (status);
+CREATE INDEX idx_retry_job_items_status ON retry_job_items (job_id, status);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS timers;
+
+ALTER TABLE steps DROP COLUMN delay_seconds;
+ALTER TABLE steps DROP COLUMN type;
@@ -0,0 +1,18 @@
+-- Step types run by the orchestrator
+ALTER TABLE steps ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'service';
+ALTER TABLE steps ADD COLUMN delay_seconds INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE timers (
+    id SERIAL PRIMARY KEY,
+    workflow_instance_id VARCHAR NOT NULL REFERENCES workflow_instances(id),
+    event_id VARCHAR NOT NULL UNIQUE,
+    event_type VARCHAR(255) NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    state VARCHAR(255) NOT NULL,
+    fire_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    fired_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_timers_due ON timers (status, fire_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS approvals;
+
+ALTER TABLE steps DROP COLUMN expire_seconds;
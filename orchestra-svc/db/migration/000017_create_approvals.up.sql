@@ -0,0 +1,21 @@
+-- Approval steps and the decisions taken on them
+ALTER TABLE steps ADD COLUMN expire_seconds INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE approvals (
+    id SERIAL PRIMARY KEY,
+    workflow_instance_id VARCHAR NOT NULL REFERENCES workflow_instances(id),
+    event_id VARCHAR NOT NULL UNIQUE,
+    step_id INTEGER NOT NULL REFERENCES steps(id),
+    event_type VARCHAR(255) NOT NULL,
+    approved_state VARCHAR(255) NOT NULL,
+    rejected_state VARCHAR(255) NOT NULL,
+    request JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    decided_by VARCHAR(255) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_approvals_status ON approvals (status, expires_at);
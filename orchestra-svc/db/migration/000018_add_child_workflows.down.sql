@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_workflow_instances_parent;
+
+ALTER TABLE workflow_instances DROP COLUMN parent_event_id;
+ALTER TABLE workflow_instances DROP COLUMN parent_instance_id;
+
+ALTER TABLE steps DROP COLUMN child_start_state;
+ALTER TABLE steps DROP COLUMN child_workflow;
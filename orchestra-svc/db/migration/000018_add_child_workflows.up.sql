@@ -0,0 +1,8 @@
+-- Steps that start a child workflow and the link from a child back to its parent
+ALTER TABLE steps ADD COLUMN child_workflow VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE steps ADD COLUMN child_start_state VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE workflow_instances ADD COLUMN parent_instance_id VARCHAR REFERENCES workflow_instances(id);
+ALTER TABLE workflow_instances ADD COLUMN parent_event_id VARCHAR;
+
+CREATE INDEX idx_workflow_instances_parent ON workflow_instances (parent_instance_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_workflow_instance_steps_started_at;
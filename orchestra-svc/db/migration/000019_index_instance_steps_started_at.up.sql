@@ -0,0 +1,2 @@
+-- Step statistics scan the steps started within a time window
+CREATE INDEX idx_workflow_instance_steps_started_at ON workflow_instance_steps (started_at);
@@ -0,0 +1 @@
+ALTER TABLE retry_job_items DROP COLUMN claimed_at;
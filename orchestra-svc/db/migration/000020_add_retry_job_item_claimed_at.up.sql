@@ -0,0 +1,4 @@
+-- Items claimed by a worker that stopped before finishing them are claimed again
+ALTER TABLE retry_job_items ADD COLUMN claimed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE retry_job_items SET claimed_at = CURRENT_TIMESTAMP WHERE status = 'in_progress';
@@ -0,0 +1 @@
+ALTER TABLE timers DROP COLUMN attempts;
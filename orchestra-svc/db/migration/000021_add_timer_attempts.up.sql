@@ -0,0 +1,2 @@
+-- Timers whose firing failed are retried with a backoff
+ALTER TABLE timers ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
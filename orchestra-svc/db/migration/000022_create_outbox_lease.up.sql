@@ -0,0 +1,7 @@
+-- Only the replica holding the lease relays the outbox, so messages of a key
+-- are published in the order they were written
+CREATE TABLE outbox_lease (
+    name VARCHAR(50) PRIMARY KEY,
+    owner VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,50 @@
+-- name: CreateApproval :exec
+INSERT INTO approvals (workflow_instance_id, event_id, step_id, event_type, approved_state, rejected_state, request, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: FindApproval :one
+SELECT * FROM approvals
+WHERE id = $1;
+
+-- name: ListApprovals :many
+SELECT * FROM approvals
+WHERE sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status')
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountApprovals :one
+SELECT COUNT(*) FROM approvals
+WHERE sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status');
+
+-- name: FindExpiredApprovals :many
+SELECT * FROM approvals
+WHERE status = 'pending' AND expires_at <= CURRENT_TIMESTAMP
+ORDER BY expires_at
+LIMIT $1;
+
+-- name: DecideApproval :execrows
+UPDATE approvals
+SET
+    status = $1,
+    decided_by = $2,
+    reason = $3,
+    decided_at = CURRENT_TIMESTAMP
+WHERE
+    id = $4 AND status = 'pending';
+
+-- name: CancelInstanceApprovals :exec
+WITH cancelled AS (
+    UPDATE approvals
+    SET
+        status = 'cancelled',
+        decided_at = CURRENT_TIMESTAMP
+    WHERE
+        workflow_instance_id = $1 AND status = 'pending'
+    RETURNING event_id
+)
+UPDATE workflow_instance_steps
+SET
+    status = 'failed',
+    completed_at = CURRENT_TIMESTAMP
+WHERE
+    event_id IN (SELECT event_id FROM cancelled);
@@ -0,0 +1,33 @@
+-- name: CreateDeadLetter :exec
+INSERT INTO dead_letters (
+    service, topic, kafka_partition, kafka_offset, message_key, payload, headers, error, attempts, failed_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (service, topic, kafka_partition, kafka_offset) DO NOTHING;
+
+-- name: ListDeadLetters :many
+SELECT * FROM dead_letters
+WHERE (sqlc.narg('service')::varchar IS NULL OR service = sqlc.narg('service'))
+  AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status'))
+ORDER BY id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountDeadLetters :one
+SELECT COUNT(*) FROM dead_letters
+WHERE (sqlc.narg('service')::varchar IS NULL OR service = sqlc.narg('service'))
+  AND (sqlc.narg('status')::varchar IS NULL OR status = sqlc.narg('status'));
+
+-- name: FindDeadLettersByIDs :many
+SELECT * FROM dead_letters
+WHERE id = ANY(sqlc.arg('ids')::int[])
+ORDER BY id;
+
+-- name: MarkDeadLetterReplayed :exec
+UPDATE dead_letters
+SET
+    status = 'replayed',
+    replay_count = replay_count + 1,
+    replayed_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1;
@@ -0,0 +1,27 @@
+-- name: UpsertInstancePayload :exec
+INSERT INTO instance_payloads (workflow_instance_id, source, payload, expires_at)
+VALUES ($1, $2, $3, (
+    SELECT MAX(expires_at) FROM instance_payloads WHERE workflow_instance_id = $1
+))
+ON CONFLICT (workflow_instance_id, source)
+DO UPDATE SET
+    payload = EXCLUDED.payload,
+    expires_at = instance_payloads.expires_at,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: FindInstancePayloads :many
+SELECT source, payload FROM instance_payloads
+WHERE workflow_instance_id = $1
+ORDER BY id;
+
+-- name: ExpireInstancePayloads :exec
+UPDATE instance_payloads
+SET
+    expires_at = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    workflow_instance_id = $2;
+
+-- name: DeleteExpiredInstancePayloads :execrows
+DELETE FROM instance_payloads
+WHERE expires_at IS NOT NULL AND expires_at <= CURRENT_TIMESTAMP;
@@ -0,0 +1,35 @@
+-- name: AcquireOutboxLease :execrows
+INSERT INTO outbox_lease (name, owner, expires_at)
+VALUES ('relay', sqlc.arg('owner'), CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg('ttl_seconds')::int))
+ON CONFLICT (name) DO UPDATE
+SET
+    owner = EXCLUDED.owner,
+    expires_at = EXCLUDED.expires_at
+WHERE
+    outbox_lease.owner = EXCLUDED.owner OR outbox_lease.expires_at < CURRENT_TIMESTAMP;
+
+-- name: CreateOutboxMessage :exec
+INSERT INTO outbox (topic, message_key, payload)
+VALUES ($1, $2, $3);
+
+-- name: FindPendingOutboxMessages :many
+SELECT * FROM outbox
+WHERE status = 'pending'
+ORDER BY id
+LIMIT $1;
+
+-- name: MarkOutboxMessageSent :exec
+UPDATE outbox
+SET
+    status = 'sent',
+    sent_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1;
+
+-- name: MarkOutboxMessageFailed :exec
+UPDATE outbox
+SET
+    attempts = attempts + 1,
+    last_error = $1
+WHERE
+    id = $2;
@@ -7,3 +7,14 @@ INSERT INTO process_logs (
     status,
     event_message
 ) VALUES ($1, $2, $3, $4, $5, $6 );
+
+-- name: FindProcessLogsByInstanceID :many
+SELECT * FROM process_logs
+WHERE workflow_instance_id = $1
+ORDER BY created_at, id;
+
+-- name: CountSucceededStates :one
+SELECT COUNT(DISTINCT state) FROM process_logs
+WHERE workflow_instance_id = $1
+  AND status IN ('success', 'skipped')
+  AND state = ANY(sqlc.arg('states')::varchar[]);
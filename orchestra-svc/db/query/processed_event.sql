@@ -0,0 +1,12 @@
+-- name: ClaimProcessedEvent :execrows
+INSERT INTO processed_events (event_id, source, state)
+VALUES ($1, $2, $3)
+ON CONFLICT (event_id, source) DO NOTHING;
+
+-- name: CreateProcessedEvent :exec
+INSERT INTO processed_events (event_id, source, state)
+VALUES ($1, $2, $3)
+ON CONFLICT (event_id, source) DO NOTHING;
+
+-- name: DeleteProcessedEvents :exec
+DELETE FROM processed_events WHERE event_id = $1;
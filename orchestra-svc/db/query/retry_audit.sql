@@ -0,0 +1,11 @@
+-- name: CreateRetryAudit :exec
+INSERT INTO retry_audits (
+    workflow_instance_id, event_id, patch_type, patch, original_message, retried_message
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: FindRetryAuditsByInstanceID :many
+SELECT * FROM retry_audits
+WHERE workflow_instance_id = $1
+ORDER BY id;
@@ -0,0 +1,61 @@
+-- name: CreateRetryJob :exec
+INSERT INTO retry_jobs (id, filter, rate, total)
+VALUES ($1, $2, $3, $4);
+
+-- name: CreateRetryJobItem :exec
+INSERT INTO retry_job_items (job_id, event_id, workflow_instance_id)
+VALUES ($1, $2, $3);
+
+-- name: FindRetryJob :one
+SELECT * FROM retry_jobs
+WHERE id = $1;
+
+-- name: FindRetryJobItems :many
+SELECT * FROM retry_job_items
+WHERE job_id = $1
+ORDER BY id;
+
+-- name: FindRunningRetryJobs :many
+SELECT * FROM retry_jobs
+WHERE status = 'running'
+ORDER BY created_at;
+
+-- name: ClaimRetryJobItems :many
+UPDATE retry_job_items
+SET
+    status = 'in_progress',
+    claimed_at = CURRENT_TIMESTAMP
+WHERE id IN (
+    SELECT id FROM retry_job_items
+    WHERE job_id = sqlc.arg('job_id')
+      AND (status = 'pending' OR (status = 'in_progress' AND claimed_at < sqlc.arg('stale_before')))
+    ORDER BY id
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: FinishRetryJobItem :exec
+UPDATE retry_job_items
+SET
+    status = $2,
+    error = $3,
+    processed_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1;
+
+-- name: RefreshRetryJobProgress :exec
+UPDATE retry_jobs
+SET
+    succeeded = (SELECT COUNT(*) FROM retry_job_items WHERE job_id = $1 AND status = 'success'),
+    failed = (SELECT COUNT(*) FROM retry_job_items WHERE job_id = $1 AND status = 'error'),
+    status = CASE
+        WHEN EXISTS (SELECT 1 FROM retry_job_items WHERE job_id = $1 AND status IN ('pending', 'in_progress')) THEN status
+        ELSE 'completed'
+    END,
+    completed_at = CASE
+        WHEN EXISTS (SELECT 1 FROM retry_job_items WHERE job_id = $1 AND status IN ('pending', 'in_progress')) THEN completed_at
+        ELSE CURRENT_TIMESTAMP
+    END
+WHERE
+    id = $1;
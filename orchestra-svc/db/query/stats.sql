@@ -0,0 +1,41 @@
+-- name: FindStepStats :many
+SELECT
+    w.type AS workflow_type,
+    s.name AS step_name,
+    s.service,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE wis.status IN ('success', 'compensated')) AS success_count,
+    COUNT(*) FILTER (WHERE wis.status IN ('error', 'failed')) AS error_count,
+    COUNT(*) FILTER (WHERE wis.status = 'timeout') AS timeout_count,
+    COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM wis.completed_at - wis.started_at) * 1000) FILTER (WHERE wis.status_code IS NOT NULL AND wis.completed_at > wis.started_at), 0)::float8 AS p50_ms,
+    COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM wis.completed_at - wis.started_at) * 1000) FILTER (WHERE wis.status_code IS NOT NULL AND wis.completed_at > wis.started_at), 0)::float8 AS p95_ms,
+    COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM wis.completed_at - wis.started_at) * 1000) FILTER (WHERE wis.status_code IS NOT NULL AND wis.completed_at > wis.started_at), 0)::float8 AS p99_ms
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN workflow_instances wi ON wis.workflow_instance_id = wi.id
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE wis.started_at >= sqlc.arg('started_from')
+  AND wis.started_at < sqlc.arg('started_to')
+  AND (sqlc.narg('type')::varchar IS NULL OR w.type = sqlc.narg('type'))
+  AND (sqlc.narg('service')::varchar IS NULL OR s.service = sqlc.narg('service'))
+  AND wis.status <> 'skipped'
+GROUP BY w.type, s.name, s.service
+ORDER BY w.type, s.name;
+
+-- name: FindStepStatusCodeCounts :many
+SELECT
+    w.type AS workflow_type,
+    s.name AS step_name,
+    wis.status_code,
+    COUNT(*) AS count
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN workflow_instances wi ON wis.workflow_instance_id = wi.id
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE wis.started_at >= sqlc.arg('started_from')
+  AND wis.started_at < sqlc.arg('started_to')
+  AND (sqlc.narg('type')::varchar IS NULL OR w.type = sqlc.narg('type'))
+  AND (sqlc.narg('service')::varchar IS NULL OR s.service = sqlc.narg('service'))
+  AND wis.status <> 'skipped'
+GROUP BY w.type, s.name, wis.status_code
+ORDER BY w.type, s.name, wis.status_code;
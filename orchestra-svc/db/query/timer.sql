@@ -0,0 +1,49 @@
+-- name: CreateTimer :exec
+INSERT INTO timers (workflow_instance_id, event_id, event_type, source, state, fire_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: FindDueTimers :many
+SELECT * FROM timers
+WHERE status = 'pending' AND fire_at <= CURRENT_TIMESTAMP
+ORDER BY fire_at
+LIMIT $1;
+
+-- name: ClaimTimer :execrows
+UPDATE timers
+SET
+    status = 'fired',
+    fired_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 AND status = 'pending';
+
+-- name: UpdateTimerStatus :exec
+UPDATE timers
+SET
+    status = $1
+WHERE
+    id = $2;
+
+-- name: RescheduleTimer :exec
+UPDATE timers
+SET
+    status = 'pending',
+    attempts = attempts + 1,
+    fire_at = $2
+WHERE
+    id = $1 AND status = 'pending';
+
+-- name: CancelInstanceTimers :exec
+WITH cancelled AS (
+    UPDATE timers
+    SET
+        status = 'cancelled'
+    WHERE
+        workflow_instance_id = $1 AND status = 'pending'
+    RETURNING event_id
+)
+UPDATE workflow_instance_steps
+SET
+    status = 'failed',
+    completed_at = CURRENT_TIMESTAMP
+WHERE
+    event_id IN (SELECT event_id FROM cancelled);
@@ -13,13 +13,26 @@ SELECT DISTINCT
     s.service AS service,
     s.name AS step_name,
     s.description AS step_description,
-    s.topic AS step_topic
+    s.topic AS step_topic,
+    sa.guard,
+    s.type AS step_type,
+    s.delay_seconds,
+    s.expire_seconds,
+    s.child_workflow,
+    s.child_start_state,
+    s.emits
 FROM
     state_actions sa
         JOIN steps s ON sa.step_id = s.id
 WHERE
     sa.type = $1 AND
-    sa.state = $2
+    sa.version = $2 AND
+    sa.state = $3
 ORDER BY
     sa.state;
 
+
+-- name: FindPayloadMappingsByStepID :many
+SELECT * FROM payload_mappings
+WHERE step_id = $1
+ORDER BY id;
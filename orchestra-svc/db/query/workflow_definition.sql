@@ -0,0 +1,83 @@
+-- name: UpsertWorkflow :one
+INSERT INTO workflows (type, description)
+VALUES ($1, $2)
+ON CONFLICT (type)
+DO UPDATE SET
+    description = EXCLUDED.description,
+    version = workflows.version + 1,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListWorkflows :many
+SELECT * FROM workflows
+ORDER BY type;
+
+-- name: CreateStep :one
+INSERT INTO steps (
+    name,
+    description,
+    service,
+    topic,
+    emits,
+    timeout_seconds,
+    timeout_action,
+    max_attempts,
+    retry_base_delay_ms,
+    retryable_status_codes,
+    version,
+    type,
+    delay_seconds,
+    expire_seconds,
+    child_workflow,
+    child_start_state
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING *;
+
+-- name: UpdateStepCompensation :exec
+UPDATE steps
+SET
+    compensation_step_id = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2;
+
+-- name: FindStepsByWorkflowType :many
+SELECT * FROM steps
+WHERE id IN (SELECT sa.step_id FROM state_actions sa WHERE sa.type = $1 AND sa.version = $2)
+   OR id IN (
+        SELECT s.compensation_step_id FROM steps s
+        JOIN state_actions sa ON sa.step_id = s.id
+        WHERE sa.type = $1 AND sa.version = $2
+   )
+ORDER BY id;
+
+-- name: FindStepByID :one
+SELECT * FROM steps
+WHERE id = $1 LIMIT 1;
+
+-- name: CreatePayloadKey :exec
+INSERT INTO payload_keys (step_id, key)
+VALUES ($1, $2);
+
+-- name: FindStateActionsByType :many
+SELECT sa.state, s.name AS step_name, sa.guard
+FROM state_actions sa
+JOIN steps s ON sa.step_id = s.id
+WHERE sa.type = $1 AND sa.version = $2
+ORDER BY sa.id;
+
+-- name: CreateStateAction :exec
+INSERT INTO state_actions (type, version, state, step_id, guard)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: CreateStepDependency :exec
+INSERT INTO step_dependencies (step_id, state)
+VALUES ($1, $2);
+
+-- name: FindStepDependenciesByStepID :many
+SELECT state FROM step_dependencies
+WHERE step_id = $1
+ORDER BY id;
+
+-- name: CreatePayloadMapping :exec
+INSERT INTO payload_mappings (step_id, target, source, default_value, required)
+VALUES ($1, $2, $3, $4, $5);
@@ -1,8 +1,18 @@
 
 -- name: CreateWorkflowInstance :one
-INSERT INTO workflow_instances (id, workflow_id, status)
+INSERT INTO workflow_instances (id, workflow_id, workflow_version, status)
 VALUES
-    ($1, $2, $3) RETURNING *;
+    ($1, $2, $3, $4) RETURNING *;
+
+-- name: CreateChildWorkflowInstance :exec
+INSERT INTO workflow_instances (id, workflow_id, workflow_version, status, parent_instance_id, parent_event_id)
+VALUES
+    ($1, $2, $3, $4, $5, $6);
+
+-- name: FindChildWorkflowInstanceIDs :many
+SELECT id FROM workflow_instances
+WHERE parent_instance_id = $1
+ORDER BY created_at;
 
 -- name: UpdateWorkflowInstance :exec
 UPDATE workflow_instances
@@ -19,7 +29,8 @@ SELECT
     wi.id as instance_id,
     wi.status as instance_status,
     wis.status as instance_step_status,
-    wis.step_id as step_id
+    wis.step_id as step_id,
+    EXISTS(SELECT 1 FROM steps s WHERE s.compensation_step_id = wis.step_id) AS is_compensation
 FROM workflows w
 JOIN workflow_instances wi on w.id = wi.workflow_id
 JOIN workflow_instance_steps wis ON wi.id = wis.workflow_instance_id
@@ -27,9 +38,9 @@ WHERE w.type = $1 AND wis.workflow_instance_id = $2;
 
 
 -- name: CreateWorkflowInstanceStep :one
-INSERT INTO workflow_instance_steps (workflow_instance_id,event_id, step_id, status, event_message, started_at, completed_at)
+INSERT INTO workflow_instance_steps (workflow_instance_id,event_id, step_id, status, event_message, started_at, completed_at, guard, guard_result, status_code, response)
 VALUES
-    ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
 
 -- name: CheckIfInstanceStepExists :one
 SELECT EXISTS(SELECT 1 FROM workflow_instance_steps WHERE event_id = $1) AS exists;
@@ -60,7 +71,195 @@ WHERE id = $1 LIMIT 1;
 
 -- name: FindWorkflowInstanceStepsByEventIDAndInsID :one
 SELECT wis.event_id, wis.workflow_instance_id, wis.status_code, wis.status, wis.event_message,
-       s.topic
+       s.topic, s.service, s.emits
 FROM workflow_instance_steps wis
          JOIN steps s on wis.step_id = s.id
 WHERE event_id = $1 AND workflow_instance_id = $2;
+
+-- name: FindCompensableInstanceSteps :many
+SELECT
+    wis.event_id,
+    wis.step_id,
+    wis.event_message,
+    c.id AS compensation_step_id,
+    c.name AS compensation_step_name,
+    c.service AS compensation_service,
+    c.topic AS compensation_topic
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN steps c ON s.compensation_step_id = c.id
+WHERE wis.workflow_instance_id = $1 AND wis.status = 'success'
+ORDER BY wis.started_at DESC, wis.id DESC;
+
+-- name: UpdateWorkflowInstanceStepStatus :exec
+UPDATE workflow_instance_steps
+SET
+    status = $1
+WHERE
+    event_id = $2;
+
+-- name: FindTimedOutInstanceSteps :many
+SELECT
+    wis.event_id,
+    wis.workflow_instance_id,
+    wis.step_id,
+    wis.started_at,
+    wis.event_message,
+    s.name AS step_name,
+    s.timeout_action,
+    w.type AS workflow_type
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN workflow_instances wi ON wis.workflow_instance_id = wi.id
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE wis.status = 'in_progress'
+  AND s.type = 'service'
+  AND s.timeout_seconds > 0
+  AND wis.started_at + make_interval(secs => s.timeout_seconds) < CURRENT_TIMESTAMP
+ORDER BY wis.started_at
+LIMIT $1;
+
+-- name: TimeoutInstanceStep :execrows
+UPDATE workflow_instance_steps
+SET
+    status = 'timeout',
+    completed_at = CURRENT_TIMESTAMP
+WHERE
+    event_id = $1 AND status = 'in_progress';
+
+-- name: FindInstanceStepRetryPolicy :one
+SELECT
+    wis.status,
+    wis.attempts,
+    s.max_attempts,
+    s.retry_base_delay_ms,
+    s.retryable_status_codes,
+    wi.status AS instance_status
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN workflow_instances wi ON wis.workflow_instance_id = wi.id
+WHERE wis.event_id = $1;
+
+-- name: ScheduleInstanceStepRetry :exec
+UPDATE workflow_instance_steps
+SET
+    status = 'retry_scheduled',
+    status_code = $1,
+    response = $2,
+    next_retry_at = $3
+WHERE
+    event_id = $4;
+
+-- name: FindDueInstanceStepRetries :many
+SELECT event_id, workflow_instance_id
+FROM workflow_instance_steps
+WHERE status = 'retry_scheduled' AND next_retry_at <= CURRENT_TIMESTAMP
+ORDER BY next_retry_at
+LIMIT $1;
+
+-- name: ClaimInstanceStepRetry :execrows
+UPDATE workflow_instance_steps
+SET
+    status = 'in_progress',
+    attempts = attempts + 1,
+    next_retry_at = NULL
+WHERE
+    event_id = $1 AND status = 'retry_scheduled';
+
+-- name: ListWorkflowInstances :many
+SELECT wi.id, w.type, wi.workflow_version, wi.status, wi.created_at, wi.updated_at
+FROM workflow_instances wi
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE (sqlc.narg('type')::varchar IS NULL OR w.type = sqlc.narg('type'))
+  AND (sqlc.narg('status')::varchar IS NULL OR wi.status = sqlc.narg('status'))
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR wi.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR wi.created_at < sqlc.narg('created_to'))
+ORDER BY wi.created_at DESC, wi.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountWorkflowInstances :one
+SELECT COUNT(*)
+FROM workflow_instances wi
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE (sqlc.narg('type')::varchar IS NULL OR w.type = sqlc.narg('type'))
+  AND (sqlc.narg('status')::varchar IS NULL OR wi.status = sqlc.narg('status'))
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR wi.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR wi.created_at < sqlc.narg('created_to'));
+
+-- name: FindWorkflowInstanceWithType :one
+SELECT wi.id, w.type, wi.workflow_version, wi.status, wi.created_at, wi.updated_at, wi.parent_instance_id, wi.parent_event_id
+FROM workflow_instances wi
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE wi.id = $1;
+
+-- name: FindInstanceStepsWithStep :many
+SELECT
+    wis.id,
+    wis.event_id,
+    wis.step_id,
+    s.name AS step_name,
+    s.service,
+    wis.status,
+    wis.status_code,
+    wis.response,
+    wis.attempts,
+    wis.next_retry_at,
+    wis.started_at,
+    wis.completed_at,
+    wis.guard,
+    wis.guard_result
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+WHERE wis.workflow_instance_id = $1
+ORDER BY wis.started_at, wis.id;
+
+-- name: LockWorkflowInstance :one
+SELECT id FROM workflow_instances
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CheckIfInstanceStepExistsForStep :one
+SELECT EXISTS(
+    SELECT 1 FROM workflow_instance_steps
+    WHERE workflow_instance_id = $1 AND step_id = $2
+) AS exists;
+
+-- name: CountFailedInstanceSteps :one
+SELECT COUNT(*) FROM workflow_instance_steps
+WHERE workflow_instance_id = $1
+  AND status IN ('error', 'failed', 'timeout');
+
+-- name: TransitionWorkflowInstance :execrows
+UPDATE workflow_instances
+SET
+    status = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2 AND status = ANY(sqlc.arg(from_statuses)::varchar[]);
+
+-- name: CancelInstanceStepRetries :exec
+UPDATE workflow_instance_steps
+SET
+    status = 'failed',
+    next_retry_at = NULL
+WHERE
+    workflow_instance_id = $1 AND status = 'retry_scheduled';
+
+-- name: FindFailedInstanceSteps :many
+SELECT wis.event_id, wis.workflow_instance_id
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN workflow_instances wi ON wis.workflow_instance_id = wi.id
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE wis.status IN ('error', 'timeout')
+  AND wi.status = 'in_progress'
+  AND (sqlc.narg('status')::varchar IS NULL OR wis.status = sqlc.narg('status'))
+  AND (sqlc.narg('type')::varchar IS NULL OR w.type = sqlc.narg('type'))
+  AND (sqlc.narg('step')::varchar IS NULL OR s.name = sqlc.narg('step'))
+  AND (sqlc.narg('service')::varchar IS NULL OR s.service = sqlc.narg('service'))
+  AND (sqlc.narg('status_code_min')::int IS NULL OR wis.status_code >= sqlc.narg('status_code_min'))
+  AND (sqlc.narg('status_code_max')::int IS NULL OR wis.status_code <= sqlc.narg('status_code_max'))
+  AND (sqlc.narg('started_from')::timestamptz IS NULL OR wis.started_at >= sqlc.narg('started_from'))
+  AND (sqlc.narg('started_to')::timestamptz IS NULL OR wis.started_at < sqlc.narg('started_to'))
+ORDER BY wis.started_at, wis.id
+LIMIT sqlc.arg('limit');
@@ -8,6 +8,8 @@ import (
 	"log"
 	"net/http"
 	"orchestra-svc/internal/delivery/messaging"
+	"orchestra-svc/internal/repository/cache"
+	"orchestra-svc/internal/usecase"
 	"orchestra-svc/pkg"
 	"orchestra-svc/pkg/consumer"
 	"orchestra-svc/pkg/producer"
@@ -24,6 +26,15 @@ type App struct {
 	gin    *gin.Engine
 	config *pkg.Config
 	msg    *messaging.MessageHandler
+
+	deadLetters *messaging.DeadLetterHandler
+	payloads    cache.PayloadStore
+	sweeper     *usecase.SweeperUsecase
+	retries     *usecase.RetryUsecase
+	retryJobs   *usecase.RetryJobUsecase
+	timers      *usecase.TimerUsecase
+	approvals   *usecase.ApprovalUsecase
+	outbox      *usecase.OutboxUsecase
 }
 
 func NewApp(db *sql.DB, gin *gin.Engine, config *pkg.Config) *App {
@@ -68,27 +79,60 @@ func (app *App) Run() {
 		Handler: app.gin,
 	}
 
-	c, err := consumer.NewKafkaConsumer(
+	// every worker joins the same group, so partitions are spread across them
+	// and one instance, keyed to one partition, is handled by one worker
+	workers := make([]*consumer.KafkaConsumer, 0, app.config.ConsumerWorkers)
+	for i := 0; i < max(app.config.ConsumerWorkers, 1); i++ {
+		c, err := consumer.NewKafkaConsumer(
+			[]string{app.config.KafkaBroker},
+			app.config.GroupID,
+			[]string{app.config.OrchestraTopic},
+			app.msg,
+		)
+		if err != nil {
+			log.Fatalf("Error creating Kafka consumer: %v", err)
+		}
+		defer c.Close()
+
+		workers = append(workers, c)
+	}
+
+	dlc, err := consumer.NewKafkaConsumer(
 		[]string{app.config.KafkaBroker},
-		app.config.GroupID,
-		[]string{app.config.OrchestraTopic},
-		app.msg,
+		app.config.GroupID+"-dlq",
+		app.config.DeadLetterTopics,
+		app.deadLetters,
 	)
-	defer c.Close()
-
 	if err != nil {
-		log.Fatalf("Error creating Kafka consumer: %v", err)
+		log.Fatalf("Error creating Kafka dead-letter consumer: %v", err)
 	}
+	defer dlc.Close()
 
 	ctxCancel, cancel2 := context.WithCancel(context.Background())
 	defer cancel2()
 
+	for _, c := range workers {
+		go func(c *consumer.KafkaConsumer) {
+			if err := c.Consume(ctxCancel); err != nil {
+				log.Fatalf("Error consuming Kafka messages: %v", err)
+			}
+		}(c)
+	}
+
 	go func() {
-		if err := c.Consume(ctxCancel); err != nil {
-			log.Fatalf("Error consuming Kafka messages: %v", err)
+		if err := dlc.Consume(ctxCancel); err != nil {
+			log.Fatalf("Error consuming dead letters: %v", err)
 		}
 	}()
 
+	go app.runEvery(ctxCancel, app.config.PayloadCleanup, app.purgePayloads)
+	go app.runEvery(ctxCancel, app.config.SweepInterval, app.sweepTimedOutSteps)
+	go app.runEvery(ctxCancel, app.config.RetryInterval, app.retryDueSteps)
+	go app.runEvery(ctxCancel, app.config.RetryJobInterval, app.runRetryJobs)
+	go app.runEvery(ctxCancel, app.config.TimerInterval, app.fireDueTimers)
+	go app.runEvery(ctxCancel, app.config.ApprovalInterval, app.expireApprovals)
+	go app.runEvery(ctxCancel, app.config.OutboxInterval, app.relayOutbox)
+
 	go func() {
 		log.Printf("Starting server on port %s", app.config.Port)
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
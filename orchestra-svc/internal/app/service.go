@@ -1,28 +1,70 @@
 package app
 
 import (
+	"expvar"
 	"orchestra-svc/internal/delivery/http"
 	"orchestra-svc/internal/delivery/messaging"
 	"orchestra-svc/internal/repository/cache"
 	"orchestra-svc/internal/repository/sqlc"
 	"orchestra-svc/internal/usecase"
+	"orchestra-svc/pkg/deadletter"
 	"orchestra-svc/pkg/producer"
+
+	"github.com/gin-gonic/gin"
 )
 
 func (app *App) startService(userProductProducer *producer.KafkaProducer) error {
 
 	s := sqlc.NewStore(app.db)
-	c := cache.NewPayloadCache()
 
-	orc := usecase.NewOrchestraUsecase(s, userProductProducer, c)
-	rc := usecase.NewRetryUsecase(s, userProductProducer, orc)
+	var c cache.PayloadStore
+	if app.config.PayloadStore == "memory" {
+		c = cache.NewPayloadCache()
+	} else {
+		c = cache.NewPostgresPayloadStore(s, app.config.PayloadTTL)
+	}
+	app.payloads = c
+
+	orc := usecase.NewOrchestraUsecase(s, c, usecase.OrchestraOptions{
+		Topic:       app.config.OrchestraTopic,
+		EventsTopic: app.config.EventsTopic,
+	})
+	rc := usecase.NewRetryUsecase(s, orc)
+	app.sweeper = usecase.NewSweeperUsecase(s, orc, app.config.SweepBatchSize)
+	app.retries = rc
+	app.retryJobs = usecase.NewRetryJobUsecase(s, rc, app.config.RetryJobInterval, app.config.RetryJobRate)
+	app.timers = usecase.NewTimerUsecase(s, orc)
+	app.approvals = usecase.NewApprovalUsecase(s, orc)
+	app.outbox = usecase.NewOutboxUsecase(s, userProductProducer)
+
+	dlq := deadletter.NewPublisher(deadletter.SenderFunc(userProductProducer.SendMessage), app.config.DeadLetterTopic, "orchestra-svc", app.config.DeadLetterTries)
+	app.msg = messaging.NewMessageHandler(orc, usecase.NewInboxUsecase(s), dlq)
 
-	app.msg = messaging.NewMessageHandler(orc)
+	dc := usecase.NewDeadLetterUsecase(s, userProductProducer)
+	app.deadLetters = messaging.NewDeadLetterHandler(dc)
+
+	ic := usecase.NewInstanceUsecase(s, c, orc)
+	defc := usecase.NewDefinitionUsecase(s)
+	sc := usecase.NewStatsUsecase(s)
 
 	wfh := http.NewWorkflowHandler(orc, rc)
+	ih := http.NewInstanceHandler(ic)
+	dh := http.NewDeadLetterHandler(dc)
+	gh := http.NewDefinitionHandler(defc)
+	jh := http.NewRetryJobHandler(app.retryJobs)
+	ah := http.NewApprovalHandler(app.approvals)
+	sh := http.NewStatsHandler(sc)
+
+	app.gin.GET("/debug/vars", gin.WrapH(expvar.Handler()))
 
 	wfGroupV1 := app.gin.Group("/api/v1/workflow")
 	wfh.RegisterRoutes(wfGroupV1)
+	ih.RegisterRoutes(wfGroupV1)
+	dh.RegisterRoutes(wfGroupV1)
+	gh.RegisterRoutes(wfGroupV1)
+	jh.RegisterRoutes(wfGroupV1)
+	ah.RegisterRoutes(wfGroupV1)
+	sh.RegisterRoutes(wfGroupV1)
 
 	return nil
 }
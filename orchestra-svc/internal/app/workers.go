@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+func (app *App) runEvery(ctx context.Context, interval time.Duration, fn func(context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn(ctx)
+		}
+	}
+}
+
+func (app *App) purgePayloads(ctx context.Context) {
+	deleted, err := app.payloads.Purge(ctx)
+	if err != nil {
+		log.Println("Error purge expired payloads: ", err)
+		return
+	}
+
+	if deleted > 0 {
+		log.Printf("Purged %d expired payloads", deleted)
+	}
+}
+
+func (app *App) sweepTimedOutSteps(ctx context.Context) {
+	swept, err := app.sweeper.SweepTimedOutSteps(ctx)
+	if err != nil {
+		log.Println("Error sweep timed out steps: ", err)
+		return
+	}
+
+	if swept > 0 {
+		log.Printf("Timed out %d steps", swept)
+	}
+}
+
+func (app *App) retryDueSteps(ctx context.Context) {
+	retried, err := app.retries.RetryDueInstanceSteps(ctx, app.config.RetryBatchSize)
+	if err != nil {
+		log.Println("Error retry due steps: ", err)
+		return
+	}
+
+	if retried > 0 {
+		log.Printf("Retried %d steps", retried)
+	}
+}
+
+func (app *App) runRetryJobs(ctx context.Context) {
+	retried, err := app.retryJobs.RunRetryJobs(ctx)
+	if err != nil {
+		log.Println("Error run retry jobs: ", err)
+		return
+	}
+
+	if retried > 0 {
+		log.Printf("Bulk retried %d steps", retried)
+	}
+}
+
+func (app *App) fireDueTimers(ctx context.Context) {
+	fired, err := app.timers.FireDueTimers(ctx, app.config.TimerBatchSize)
+	if err != nil {
+		log.Println("Error fire due timers: ", err)
+		return
+	}
+
+	if fired > 0 {
+		log.Printf("Fired %d timers", fired)
+	}
+}
+
+func (app *App) expireApprovals(ctx context.Context) {
+	expired, err := app.approvals.ExpireApprovals(ctx, app.config.ApprovalBatch)
+	if err != nil {
+		log.Println("Error expire approvals: ", err)
+		return
+	}
+
+	if expired > 0 {
+		log.Printf("Rejected %d expired approvals", expired)
+	}
+}
+
+func (app *App) relayOutbox(ctx context.Context) {
+	sent, err := app.outbox.RelayPending(ctx, app.config.OutboxBatchSize)
+	if err != nil {
+		log.Println("Error relay outbox messages: ", err)
+		return
+	}
+
+	if sent > 0 {
+		log.Printf("Relayed %d outbox messages", sent)
+	}
+}
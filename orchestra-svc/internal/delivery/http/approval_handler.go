@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/sqlc"
+	"orchestra-svc/internal/usecase"
+	"strconv"
+
+	"github.com/benebobaa/valo"
+	"github.com/gin-gonic/gin"
+)
+
+type ApprovalHandler struct {
+	ac *usecase.ApprovalUsecase
+}
+
+func NewApprovalHandler(ac *usecase.ApprovalUsecase) *ApprovalHandler {
+	return &ApprovalHandler{
+		ac: ac,
+	}
+}
+
+func (ah *ApprovalHandler) ListApprovals(c *gin.Context) {
+
+	var req dto.ApprovalListRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := ah.ac.ListApprovals(c, &req)
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (ah *ApprovalHandler) Approve(c *gin.Context) {
+	ah.decide(c, ah.ac.Approve)
+}
+
+func (ah *ApprovalHandler) Reject(c *gin.Context) {
+	ah.decide(c, ah.ac.Reject)
+}
+
+type decideFunc func(ctx context.Context, id int32, req *dto.ApprovalDecisionRequest) (*sqlc.Approval, error)
+
+func (ah *ApprovalHandler) decide(c *gin.Context, decide decideFunc) {
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, "invalid approval id")
+		return
+	}
+
+	var req dto.ApprovalDecisionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	err = valo.Validate(req)
+
+	if err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := decide(c, int32(id), &req)
+
+	if errors.Is(err, usecase.ErrApprovalNotFound) {
+		c.JSON(404, err.Error())
+		return
+	}
+
+	if errors.Is(err, usecase.ErrApprovalDecided) {
+		c.JSON(409, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/usecase"
+
+	"github.com/benebobaa/valo"
+	"github.com/gin-gonic/gin"
+)
+
+type DeadLetterHandler struct {
+	dc *usecase.DeadLetterUsecase
+}
+
+func NewDeadLetterHandler(dc *usecase.DeadLetterUsecase) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		dc: dc,
+	}
+}
+
+func (dh *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+
+	var req dto.DeadLetterListRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := dh.dc.ListDeadLetters(c, &req)
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (dh *DeadLetterHandler) ReplayDeadLetters(c *gin.Context) {
+
+	var req dto.DeadLetterReplayRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	err := valo.Validate(req)
+
+	if err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := dh.dc.Replay(c, &req)
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
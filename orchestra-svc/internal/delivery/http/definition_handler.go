@@ -0,0 +1,77 @@
+package http
+
+import (
+	"errors"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DefinitionHandler struct {
+	dc *usecase.DefinitionUsecase
+}
+
+func NewDefinitionHandler(dc *usecase.DefinitionUsecase) *DefinitionHandler {
+	return &DefinitionHandler{
+		dc: dc,
+	}
+}
+
+func (dh *DefinitionHandler) GetDefinitionGraph(c *gin.Context) {
+
+	format, ok := bindGraphFormat(c)
+	if !ok {
+		return
+	}
+
+	graph, err := dh.dc.Graph(c, c.Param("type"), format)
+
+	writeGraph(c, graph, err)
+}
+
+func (dh *DefinitionHandler) GetInstanceGraph(c *gin.Context) {
+
+	format, ok := bindGraphFormat(c)
+	if !ok {
+		return
+	}
+
+	graph, err := dh.dc.InstanceGraph(c, c.Param("id"), format)
+
+	writeGraph(c, graph, err)
+}
+
+func bindGraphFormat(c *gin.Context) (string, bool) {
+	var req dto.GraphRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(400, err.Error())
+		return "", false
+	}
+
+	if req.Format == "" {
+		req.Format = usecase.GraphMermaid
+	}
+
+	return req.Format, true
+}
+
+func writeGraph(c *gin.Context, graph string, err error) {
+	if errors.Is(err, usecase.ErrUnknownGraphFormat) {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	if errors.Is(err, usecase.ErrWorkflowNotFound) || errors.Is(err, usecase.ErrInstanceNotFound) {
+		c.JSON(404, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.Data(200, "text/plain; charset=utf-8", []byte(graph))
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InstanceHandler struct {
+	ic *usecase.InstanceUsecase
+}
+
+func NewInstanceHandler(ic *usecase.InstanceUsecase) *InstanceHandler {
+	return &InstanceHandler{
+		ic: ic,
+	}
+}
+
+func (ih *InstanceHandler) ListInstances(c *gin.Context) {
+
+	var req dto.InstanceListRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := ih.ic.ListInstances(c, &req)
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (ih *InstanceHandler) GetInstance(c *gin.Context) {
+
+	response, err := ih.ic.GetInstance(c, c.Param("id"))
+
+	if errors.Is(err, usecase.ErrInstanceNotFound) {
+		c.JSON(404, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (ih *InstanceHandler) GetInstancePayload(c *gin.Context) {
+
+	response, err := ih.ic.GetInstancePayload(c, c.Param("id"))
+
+	if errors.Is(err, usecase.ErrInstanceNotFound) {
+		c.JSON(404, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (ih *InstanceHandler) RecoverInstance(c *gin.Context) {
+
+	var req dto.InstanceRecoverRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := ih.ic.RecoverInstance(c, c.Param("id"), req.DryRun)
+
+	if errors.Is(err, usecase.ErrInstanceNotFound) {
+		c.JSON(404, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
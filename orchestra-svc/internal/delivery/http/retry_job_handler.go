@@ -0,0 +1,65 @@
+package http
+
+import (
+	"errors"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RetryJobHandler struct {
+	jc *usecase.RetryJobUsecase
+}
+
+func NewRetryJobHandler(jc *usecase.RetryJobUsecase) *RetryJobHandler {
+	return &RetryJobHandler{
+		jc: jc,
+	}
+}
+
+func (jh *RetryJobHandler) CreateRetryJob(c *gin.Context) {
+
+	var req dto.RetryJobRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := jh.jc.CreateRetryJob(c, &req)
+
+	if errors.Is(err, usecase.ErrInvalidRetryJob) {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	if errors.Is(err, usecase.ErrNoStepsToRetry) {
+		c.JSON(404, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(202, response)
+}
+
+func (jh *RetryJobHandler) GetRetryJob(c *gin.Context) {
+
+	response, err := jh.jc.GetRetryJob(c, c.Param("id"))
+
+	if errors.Is(err, usecase.ErrRetryJobNotFound) {
+		c.JSON(404, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
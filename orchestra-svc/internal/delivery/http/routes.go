@@ -5,4 +5,40 @@ import "github.com/gin-gonic/gin"
 func (wf *WorkflowHandler) RegisterRoutes(router *gin.RouterGroup) {
 	router.PATCH("/product-retry", wf.RetryProductReserve)
 	router.PATCH("/retry", wf.RetryInstanceStep)
+	router.PATCH("/skip", wf.SkipInstanceStep)
+	router.PATCH("/force-complete", wf.ForceCompleteInstanceStep)
+	router.POST("/instances/:id/abort", wf.AbortInstance)
+	router.GET("/instances/:id/retries", wf.ListRetryAudits)
+}
+
+func (ih *InstanceHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/instances", ih.ListInstances)
+	router.GET("/instances/:id", ih.GetInstance)
+	router.GET("/instances/:id/payload", ih.GetInstancePayload)
+	router.POST("/instances/:id/recover", ih.RecoverInstance)
+}
+
+func (dh *DeadLetterHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/dead-letters", dh.ListDeadLetters)
+	router.POST("/dead-letters/replay", dh.ReplayDeadLetters)
+}
+
+func (dh *DefinitionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/definitions/:type/graph", dh.GetDefinitionGraph)
+	router.GET("/instances/:id/graph", dh.GetInstanceGraph)
+}
+
+func (jh *RetryJobHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/retry-jobs", jh.CreateRetryJob)
+	router.GET("/retry-jobs/:id", jh.GetRetryJob)
+}
+
+func (ah *ApprovalHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/approvals", ah.ListApprovals)
+	router.POST("/approvals/:id/approve", ah.Approve)
+	router.POST("/approvals/:id/reject", ah.Reject)
+}
+
+func (sh *StatsHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/stats/steps", sh.StepStats)
 }
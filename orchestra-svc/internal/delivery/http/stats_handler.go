@@ -0,0 +1,43 @@
+package http
+
+import (
+	"errors"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	sc *usecase.StatsUsecase
+}
+
+func NewStatsHandler(sc *usecase.StatsUsecase) *StatsHandler {
+	return &StatsHandler{
+		sc: sc,
+	}
+}
+
+func (sh *StatsHandler) StepStats(c *gin.Context) {
+
+	var req dto.StepStatsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := sh.sc.StepStats(c, &req)
+
+	if errors.Is(err, usecase.ErrInvalidStatsWindow) {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"orchestra-svc/internal/dto"
 	"orchestra-svc/internal/usecase"
+	"orchestra-svc/pkg/jsonpatch"
 
 	"github.com/benebobaa/valo"
 )
@@ -41,6 +43,11 @@ func (wf *WorkflowHandler) RetryProductReserve(c *gin.Context) {
 
 	response, err := wf.rc.ProductQuantityRetry(c, &req)
 
+	if errors.Is(err, usecase.ErrInstanceNotRetryable) {
+		c.JSON(409, err.Error())
+		return
+	}
+
 	if err != nil {
 		c.JSON(500, err.Error())
 		return
@@ -67,6 +74,122 @@ func (wf *WorkflowHandler) RetryInstanceStep(c *gin.Context) {
 
 	response, err := wf.rc.RetryFailedInstanceStep(c, &req)
 
+	if errors.Is(err, jsonpatch.ErrInvalidPatch) {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInstanceNotRetryable) {
+		c.JSON(409, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (wf *WorkflowHandler) SkipInstanceStep(c *gin.Context) {
+
+	var req dto.StepOverrideRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	err := valo.Validate(req)
+
+	if err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := wf.rc.SkipInstanceStep(c, &req)
+
+	if errors.Is(err, usecase.ErrInvalidStepState) {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	if errors.Is(err, usecase.ErrStepNotOverridable) || errors.Is(err, usecase.ErrInstanceNotOverridable) {
+		c.JSON(409, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (wf *WorkflowHandler) ForceCompleteInstanceStep(c *gin.Context) {
+
+	var req dto.StepOverrideRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	err := valo.Validate(req)
+
+	if err != nil {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	response, err := wf.rc.ForceCompleteInstanceStep(c, &req)
+
+	if errors.Is(err, usecase.ErrInvalidStepState) {
+		c.JSON(400, err.Error())
+		return
+	}
+
+	if errors.Is(err, usecase.ErrStepNotOverridable) || errors.Is(err, usecase.ErrInstanceNotOverridable) {
+		c.JSON(409, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(200, response)
+}
+
+func (wf *WorkflowHandler) AbortInstance(c *gin.Context) {
+
+	response, err := wf.oc.AbortInstance(c, c.Param("id"))
+
+	if errors.Is(err, usecase.ErrInstanceNotFound) {
+		c.JSON(404, err.Error())
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInstanceNotAbortable) {
+		c.JSON(409, err.Error())
+		return
+	}
+
+	if err != nil {
+		c.JSON(500, err.Error())
+		return
+	}
+
+	c.JSON(202, response)
+}
+
+func (wf *WorkflowHandler) ListRetryAudits(c *gin.Context) {
+
+	response, err := wf.rc.ListRetryAudits(c, c.Param("id"))
+
 	if err != nil {
 		c.JSON(500, err.Error())
 		return
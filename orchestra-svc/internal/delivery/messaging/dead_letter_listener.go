@@ -0,0 +1,66 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"orchestra-svc/internal/usecase"
+	"orchestra-svc/pkg/deadletter"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+const storeRetryBackoff = time.Second
+
+type DeadLetterHandler struct {
+	dc *usecase.DeadLetterUsecase
+}
+
+func NewDeadLetterHandler(dc *usecase.DeadLetterUsecase) *DeadLetterHandler {
+	return &DeadLetterHandler{dc: dc}
+}
+
+func (h DeadLetterHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
+func (h DeadLetterHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+
+func (h DeadLetterHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+
+	for msg := range claim.Messages() {
+
+		var record deadletter.Record
+
+		if err := json.Unmarshal(msg.Value, &record); err != nil {
+			log.Println("Error when parse dead letter: ", err.Error())
+			sess.MarkMessage(msg, "")
+			continue
+		}
+
+		if err := h.store(sess.Context(), record); err != nil {
+			// leave the message unmarked so the next session delivers it again
+			return nil
+		}
+
+		sess.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+// store retries until the record is saved or the session ends, a dead letter
+// acknowledged without being saved would be lost.
+func (h DeadLetterHandler) store(ctx context.Context, record deadletter.Record) error {
+	for {
+		err := h.dc.Store(ctx, record)
+		if err == nil {
+			return nil
+		}
+
+		log.Println("Error when store dead letter: ", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(storeRetryBackoff):
+		}
+	}
+}
@@ -1,19 +1,23 @@
 package messaging
 
 import (
-	"log"
+	"context"
 	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
 	"orchestra-svc/internal/usecase"
+	"orchestra-svc/pkg/deadletter"
 
 	"github.com/IBM/sarama"
 )
 
 type MessageHandler struct {
-	oc *usecase.OrchestraUsecase
+	oc    *usecase.OrchestraUsecase
+	inbox *usecase.InboxUsecase
+	dlq   *deadletter.Publisher
 }
 
-func NewMessageHandler(oc *usecase.OrchestraUsecase) *MessageHandler {
-	return &MessageHandler{oc: oc}
+func NewMessageHandler(oc *usecase.OrchestraUsecase, inbox *usecase.InboxUsecase, dlq *deadletter.Publisher) *MessageHandler {
+	return &MessageHandler{oc: oc, inbox: inbox, dlq: dlq}
 }
 
 func (h MessageHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
@@ -23,19 +27,23 @@ func (h MessageHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sar
 
 	for msg := range claim.Messages() {
 
-		eventMsg, err := event.FromJSON[any, any](msg.Value)
-
-		if err != nil {
-			log.Println("Error when parse message: ", err.Error())
-		}
-
-		err = h.oc.ProcessWorkflow(sess.Context(), eventMsg)
-
-		if err != nil {
-			log.Println("Error when process workflow: ", err.Error())
-		}
+		h.dlq.Handle(msg, func() error {
+			return h.handle(sess.Context(), msg.Value)
+		})
 
 		sess.MarkMessage(msg, "")
 	}
 	return nil
 }
+
+func (h MessageHandler) handle(ctx context.Context, value []byte) error {
+	eventMsg, err := event.FromJSON[any, any](value)
+
+	if err != nil {
+		return deadletter.Unprocessable(err)
+	}
+
+	return h.inbox.Handle(ctx, eventMsg.EventID, eventMsg.Source, eventMsg.State, func(ctx context.Context, s sqlc.Store) error {
+		return h.oc.WithStore(s).ProcessWorkflow(ctx, eventMsg)
+	})
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "orchestra-svc/internal/repository/sqlc"
+
+// Statuses of an approval once it is no longer pending.
+const (
+	ApprovalStatusApproved  = "approved"
+	ApprovalStatusRejected  = "rejected"
+	ApprovalStatusExpired   = "expired"
+	ApprovalStatusCancelled = "cancelled"
+)
+
+type ApprovalListRequest struct {
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+}
+
+type ApprovalListResponse struct {
+	Approvals []sqlc.Approval `json:"approvals"`
+	Page      int             `json:"page"`
+	PageSize  int             `json:"page_size"`
+	Total     int64           `json:"total"`
+}
+
+type ApprovalDecisionRequest struct {
+	DecidedBy string `json:"decided_by" valo:"notblank"`
+	Reason    string `json:"reason"`
+}
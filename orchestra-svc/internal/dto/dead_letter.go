@@ -0,0 +1,28 @@
+package dto
+
+import "orchestra-svc/internal/repository/sqlc"
+
+type DeadLetterListRequest struct {
+	Service  string `form:"service"`
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+}
+
+type DeadLetterListResponse struct {
+	DeadLetters []sqlc.DeadLetter `json:"dead_letters"`
+	Page        int               `json:"page"`
+	PageSize    int               `json:"page_size"`
+	Total       int64             `json:"total"`
+}
+
+type DeadLetterReplayRequest struct {
+	IDs []int32 `json:"ids" valo:"sizeMin=1"`
+}
+
+type DeadLetterReplayResult struct {
+	ID       int32  `json:"id"`
+	Topic    string `json:"topic"`
+	Replayed bool   `json:"replayed"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,77 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LifecycleSchemaVersion is bumped on any change to LifecycleEvent that is
+// not a new optional field, so consumers can reject what they don't know.
+const LifecycleSchemaVersion = 1
+
+// Lifecycle event types.
+const (
+	// LifecycleStarted is published once the instance row is created.
+	LifecycleStarted = "started"
+	// LifecycleStepCompleted is published for every forward step that
+	// replied with success, Step names the step and DurationMs is its run
+	// time. Compensating steps are not reported.
+	LifecycleStepCompleted = "step_completed"
+	// LifecycleCompleted is published when every step succeeded.
+	LifecycleCompleted = "completed"
+	// LifecycleFailed is published when the instance ends failed, Step is
+	// the step that failed.
+	LifecycleFailed = "failed"
+	// LifecycleCompensated is published right before failed when the steps
+	// that had succeeded were rolled back and every compensating step
+	// replied with success.
+	LifecycleCompensated = "compensated"
+	// LifecycleAborted is published when an abort finished compensating.
+	LifecycleAborted = "aborted"
+)
+
+// LifecycleEvent is published to the workflow events topic, keyed by
+// instance ID so the events of one instance stay in order. DurationMs is
+// measured from the start of the instance, or of the step for
+// step_completed. Consumers rely on docs/lifecycle-events.md, keep it in
+// sync with this struct.
+type LifecycleEvent struct {
+	SchemaVersion    int            `json:"schema_version"`
+	EventID          string         `json:"event_id"`
+	Type             string         `json:"type"`
+	WorkflowType     string         `json:"workflow_type"`
+	WorkflowVersion  int32          `json:"workflow_version,omitempty"`
+	InstanceID       string         `json:"instance_id"`
+	ParentInstanceID string         `json:"parent_instance_id,omitempty"`
+	Status           string         `json:"status"`
+	Step             *LifecycleStep `json:"step,omitempty"`
+	DurationMs       int64          `json:"duration_ms"`
+	OccurredAt       time.Time      `json:"occurred_at"`
+}
+
+// LifecycleStep is the step a lifecycle event is about.
+type LifecycleStep struct {
+	ID         int32  `json:"id"`
+	Name       string `json:"name"`
+	Service    string `json:"service"`
+	Status     string `json:"status"`
+	StatusCode int32  `json:"status_code,omitempty"`
+}
+
+func NewLifecycleEvent(eventType, workflowType, instanceID, status string) LifecycleEvent {
+	return LifecycleEvent{
+		SchemaVersion: LifecycleSchemaVersion,
+		EventID:       uuid.New().String(),
+		Type:          eventType,
+		WorkflowType:  workflowType,
+		InstanceID:    instanceID,
+		Status:        status,
+		OccurredAt:    time.Now(),
+	}
+}
+
+func (le LifecycleEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(le)
+}
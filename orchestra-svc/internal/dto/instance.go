@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"orchestra-svc/internal/repository/sqlc"
+	"time"
+)
+
+type InstanceListRequest struct {
+	Type     string    `form:"type"`
+	Status   string    `form:"status"`
+	From     time.Time `form:"from"`
+	To       time.Time `form:"to"`
+	Page     int       `form:"page"`
+	PageSize int       `form:"page_size"`
+}
+
+type InstanceListResponse struct {
+	Instances []sqlc.ListWorkflowInstancesRow `json:"instances"`
+	Page      int                             `json:"page"`
+	PageSize  int                             `json:"page_size"`
+	Total     int64                           `json:"total"`
+}
+
+type InstanceDetailResponse struct {
+	Instance    sqlc.FindWorkflowInstanceWithTypeRow `json:"instance"`
+	Steps       []sqlc.FindInstanceStepsWithStepRow  `json:"steps"`
+	ProcessLogs []sqlc.ProcessLog                    `json:"process_logs"`
+}
+
+type InstanceAbortResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type InstanceRecoverRequest struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// InstanceRecoverResponse is the state rebuilt from an instance's process
+// logs. Changes lists the steps whose replayed outcome differs from the one
+// stored, they are only written back when DryRun is false.
+type InstanceRecoverResponse struct {
+	ID            string             `json:"id"`
+	DryRun        bool               `json:"dry_run"`
+	Replayed      int                `json:"replayed"`
+	CacheRestored bool               `json:"cache_restored"`
+	Payload       map[string]any     `json:"payload"`
+	Changes       []StepRecoveryDiff `json:"changes"`
+}
+
+type StepRecoveryDiff struct {
+	EventID            string `json:"event_id"`
+	StepID             int32  `json:"step_id"`
+	CurrentStatus      string `json:"current_status"`
+	ReplayedStatus     string `json:"replayed_status"`
+	CurrentStatusCode  int32  `json:"current_status_code"`
+	ReplayedStatusCode int32  `json:"replayed_status_code"`
+	ResponseChanged    bool   `json:"response_changed"`
+}
@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"encoding/json"
+	"orchestra-svc/internal/repository/sqlc"
+	"time"
+)
+
 type ProductQuantityRetryRequest struct {
 	Quantity   int    `json:"quantity" valo:"min=1"`
 	EventID    string `json:"event_id" valo:"notblank"`
@@ -11,7 +17,42 @@ type ProductReserveRequest struct {
 	Quantity  int    `json:"quantity"`
 }
 
+// RetryRequest resends a failed step. Patch optionally changes the request
+// sent to the service and is either a JSON Merge Patch object or a JSON
+// Patch array. PatchType, merge or json, is inferred when left empty.
 type RetryRequest struct {
+	EventID    string          `json:"event_id" valo:"notblank"`
+	InstanceID string          `json:"instance_id" valo:"notblank"`
+	PatchType  string          `json:"patch_type"`
+	Patch      json.RawMessage `json:"patch"`
+}
+
+// StepOverrideRequest resolves a step on behalf of its service. State
+// defaults to the first state the step emits, Response is cached as if the
+// service had replied with it.
+type StepOverrideRequest struct {
 	EventID    string `json:"event_id" valo:"notblank"`
 	InstanceID string `json:"instance_id" valo:"notblank"`
+	State      string `json:"state"`
+	Response   any    `json:"response"`
+}
+
+// RetryJobRequest selects the failed steps a bulk retry resends. Every
+// filter is optional; Rate is how many steps are resent per second.
+type RetryJobRequest struct {
+	Type          string    `json:"type"`
+	Step          string    `json:"step"`
+	Service       string    `json:"service"`
+	Status        string    `json:"status"`
+	StatusCodeMin int32     `json:"status_code_min"`
+	StatusCodeMax int32     `json:"status_code_max"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Rate          int       `json:"rate"`
+}
+
+type RetryJobResponse struct {
+	Job     sqlc.RetryJob       `json:"job"`
+	Pending int32               `json:"pending"`
+	Items   []sqlc.RetryJobItem `json:"items"`
 }
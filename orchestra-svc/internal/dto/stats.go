@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type StepStatsRequest struct {
+	Type    string    `form:"type"`
+	Service string    `form:"service"`
+	From    time.Time `form:"from"`
+	To      time.Time `form:"to"`
+}
+
+// StepStatsResponse aggregates the steps started between From and To per
+// workflow type and step name, across the versions of a definition.
+type StepStatsResponse struct {
+	From  time.Time   `json:"from"`
+	To    time.Time   `json:"to"`
+	Steps []StepStats `json:"steps"`
+}
+
+// StepStats counts the steps by outcome. Latencies only cover the steps that
+// replied, StatusCodes keys the steps still waiting for a reply as "none".
+type StepStats struct {
+	WorkflowType string           `json:"workflow_type"`
+	Step         string           `json:"step"`
+	Service      string           `json:"service"`
+	Total        int64            `json:"total"`
+	Success      int64            `json:"success"`
+	Error        int64            `json:"error"`
+	Timeout      int64            `json:"timeout"`
+	SuccessRate  float64          `json:"success_rate"`
+	LatencyMs    StepLatency      `json:"latency_ms"`
+	StatusCodes  map[string]int64 `json:"status_codes"`
+}
+
+type StepLatency struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
@@ -0,0 +1,100 @@
+package dto
+
+// Actions taken by the sweeper once a step exceeds its timeout.
+const (
+	TimeoutFail  = "fail"
+	TimeoutRetry = "retry"
+)
+
+// Step types. A service step is published to its topic, the others are run
+// by the orchestrator: a delay step waits DelaySeconds and then emits the
+// step's first state, an approval step waits for an operator to approve or
+// reject and emits its first or second state, and a workflow step starts a
+// child instance of Workflow at StartState and emits its first or second
+// state once the child completed or failed.
+const (
+	StepService  = "service"
+	StepDelay    = "delay"
+	StepApproval = "approval"
+	StepWorkflow = "workflow"
+)
+
+// States an approval step emits when the definition does not name them.
+const (
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// OrchestratorService is the service of the steps the orchestrator runs
+// itself, and the key their replies are cached under.
+const OrchestratorService = "orchestra-svc"
+
+// WorkflowAborted is emitted by the orchestrator once an aborted instance has
+// been compensated, so a definition can map it to the steps that clean up.
+const WorkflowAborted = "workflow_aborted"
+
+var DefaultRetryableStatusCodes = []int32{429, 500, 502, 503, 504}
+
+// WorkflowDefinition is the file format used by wfctl to import and export
+// workflows together with their steps, payload keys and state actions.
+// Version is filled in on export; every import creates the next version.
+type WorkflowDefinition struct {
+	Type          string            `json:"type" yaml:"type"`
+	Description   string            `json:"description" yaml:"description"`
+	Version       int32             `json:"version,omitempty" yaml:"version,omitempty"`
+	InitialStates []string          `json:"initial_states" yaml:"initial_states"`
+	Steps         []StepDefinition  `json:"steps" yaml:"steps"`
+	States        []StateDefinition `json:"states" yaml:"states"`
+}
+
+type StepDefinition struct {
+	Name           string              `json:"name" yaml:"name"`
+	Description    string              `json:"description" yaml:"description"`
+	Type           string              `json:"type,omitempty" yaml:"type,omitempty"`
+	Service        string              `json:"service" yaml:"service"`
+	Topic          string              `json:"topic" yaml:"topic"`
+	DelaySeconds   int32               `json:"delay_seconds,omitempty" yaml:"delay_seconds,omitempty"`
+	ExpireSeconds  int32               `json:"expire_seconds,omitempty" yaml:"expire_seconds,omitempty"`
+	Workflow       string              `json:"workflow,omitempty" yaml:"workflow,omitempty"`
+	StartState     string              `json:"start_state,omitempty" yaml:"start_state,omitempty"`
+	PayloadKeys    []string            `json:"payload_keys,omitempty" yaml:"payload_keys,omitempty"`
+	Mappings       []MappingDefinition `json:"mappings,omitempty" yaml:"mappings,omitempty"`
+	Requires       []string            `json:"requires,omitempty" yaml:"requires,omitempty"`
+	Emits          []string            `json:"emits,omitempty" yaml:"emits,omitempty"`
+	Compensation   string              `json:"compensation,omitempty" yaml:"compensation,omitempty"`
+	TimeoutSeconds int32               `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"`
+	OnTimeout      string              `json:"on_timeout,omitempty" yaml:"on_timeout,omitempty"`
+	Retry          *RetryDefinition    `json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// RetryDefinition configures automatic retries of a step that replies with
+// one of StatusCodes. The delay doubles after every attempt.
+type RetryDefinition struct {
+	MaxAttempts int32   `json:"max_attempts" yaml:"max_attempts"`
+	BaseDelayMs int32   `json:"base_delay_ms" yaml:"base_delay_ms"`
+	StatusCodes []int32 `json:"status_codes,omitempty" yaml:"status_codes,omitempty"`
+}
+
+// MappingDefinition copies the value at Source, a JSONPath into the cached
+// responses keyed by service such as $.order-svc.ref_id, to Target in the
+// step's request. Default is used when Source is missing, a required mapping
+// without a default fails the step instead.
+type MappingDefinition struct {
+	Target   string `json:"target" yaml:"target"`
+	Source   string `json:"source" yaml:"source"`
+	Default  any    `json:"default,omitempty" yaml:"default,omitempty"`
+	Required bool   `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// StateDefinition lists the steps triggered by State. Guards optionally maps
+// a step name to an expression over the cached responses, such as
+// $.order-svc.amount > 1000, and the step only runs when it holds.
+type StateDefinition struct {
+	State  string            `json:"state" yaml:"state"`
+	Steps  []string          `json:"steps" yaml:"steps"`
+	Guards map[string]string `json:"guards,omitempty" yaml:"guards,omitempty"`
+}
+
+type GraphRequest struct {
+	Format string `form:"format"`
+}
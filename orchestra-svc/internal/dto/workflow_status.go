@@ -8,8 +8,20 @@ const (
 	COMPLETE
 	ERROR
 	FAILED
+	COMPENSATING
+	COMPENSATED
+	TIMEOUT
+	RETRY_SCHEDULED
+	ABORTING
+	ABORTED
+	SKIPPED
+	AWAITING_APPROVAL
 )
 
 func (s Status) String() string {
-	return [...]string{"pending", "in_progress", "success", "error", "failed"}[s]
+	return [...]string{"pending", "in_progress", "success", "error", "failed", "compensating", "compensated", "timeout", "retry_scheduled", "aborting", "aborted", "skipped", "awaiting_approval"}[s]
+}
+
+func IsFailureStatus(status string) bool {
+	return status == ERROR.String() || status == FAILED.String() || status == TIMEOUT.String()
 }
@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"context"
 	"log"
+	"orchestra-svc/internal/repository/sqlc"
 	"sync"
 )
 
@@ -53,3 +55,49 @@ func (c *PayloadCacher) Clear() {
 
 	c.data = make(map[string]map[string]any)
 }
+
+func (c *PayloadCacher) Append(_ context.Context, instanceID, source string, response any) (map[string]any, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	value, ok := c.data[instanceID]
+	if !ok || value == nil {
+		value = make(map[string]any)
+	}
+
+	value[source] = response
+	c.data[instanceID] = value
+
+	return copyPayload(value), nil
+}
+
+func (c *PayloadCacher) Load(_ context.Context, instanceID string) (map[string]any, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return copyPayload(c.data[instanceID]), nil
+}
+
+// Expire drops the instance right away, the in-memory cache has nothing to
+// inspect after a restart anyway.
+func (c *PayloadCacher) Expire(_ context.Context, instanceID string) error {
+	c.Delete(instanceID)
+	return nil
+}
+
+func (c *PayloadCacher) Purge(_ context.Context) (int64, error) {
+	return 0, nil
+}
+
+// WithStore returns the cache itself, it keeps nothing in the database.
+func (c *PayloadCacher) WithStore(_ sqlc.Store) PayloadStore {
+	return c
+}
+
+func copyPayload(value map[string]any) map[string]any {
+	result := make(map[string]any, len(value))
+	for k, v := range value {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"context"
+	"orchestra-svc/internal/repository/sqlc"
+)
+
+// PayloadStore keeps the responses each service returned for a workflow
+// instance, keyed by source, so later steps can build their request from them.
+type PayloadStore interface {
+	Append(ctx context.Context, instanceID, source string, response any) (map[string]any, error)
+	Load(ctx context.Context, instanceID string) (map[string]any, error)
+	Expire(ctx context.Context, instanceID string) error
+	Purge(ctx context.Context) (int64, error)
+	// WithStore returns the store running its queries on s, so payloads are
+	// written in the transaction s is bound to.
+	WithStore(s sqlc.Store) PayloadStore
+}
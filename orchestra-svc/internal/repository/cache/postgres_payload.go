@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"orchestra-svc/internal/repository/sqlc"
+	"time"
+)
+
+type PostgresPayloadStore struct {
+	queries sqlc.Store
+	ttl     time.Duration
+}
+
+func NewPostgresPayloadStore(q sqlc.Store, ttl time.Duration) *PostgresPayloadStore {
+	return &PostgresPayloadStore{
+		queries: q,
+		ttl:     ttl,
+	}
+}
+
+// Append keeps the expiry of a finished instance's payloads, so a late reply
+// does not keep them from being purged.
+func (p *PostgresPayloadStore) Append(ctx context.Context, instanceID, source string, response any) (map[string]any, error) {
+	bytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("parse payload: %w", err)
+	}
+
+	err = p.queries.UpsertInstancePayload(ctx, sqlc.UpsertInstancePayloadParams{
+		WorkflowInstanceID: instanceID,
+		Source:             source,
+		Payload:            string(bytes),
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("upsert payload: %w", err)
+	}
+
+	return p.Load(ctx, instanceID)
+}
+
+func (p *PostgresPayloadStore) Load(ctx context.Context, instanceID string) (map[string]any, error) {
+	rows, err := p.queries.FindInstancePayloads(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("find payloads: %w", err)
+	}
+
+	payloads := make(map[string]any, len(rows))
+	for _, row := range rows {
+		var value any
+		if err := json.Unmarshal([]byte(row.Payload), &value); err != nil {
+			return nil, fmt.Errorf("parse payload %s: %w", row.Source, err)
+		}
+		payloads[row.Source] = value
+	}
+
+	return payloads, nil
+}
+
+// Expire keeps the payloads of a finished instance around for the configured
+// TTL so operators can still inspect or retry it, Purge removes them afterwards.
+func (p *PostgresPayloadStore) Expire(ctx context.Context, instanceID string) error {
+	return p.queries.ExpireInstancePayloads(ctx, sqlc.ExpireInstancePayloadsParams{
+		ExpiresAt:          sql.NullTime{Time: time.Now().Add(p.ttl), Valid: true},
+		WorkflowInstanceID: instanceID,
+	})
+}
+
+func (p *PostgresPayloadStore) Purge(ctx context.Context) (int64, error) {
+	return p.queries.DeleteExpiredInstancePayloads(ctx)
+}
+
+func (p *PostgresPayloadStore) WithStore(s sqlc.Store) PayloadStore {
+	return &PostgresPayloadStore{
+		queries: s,
+		ttl:     p.ttl,
+	}
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPostgresPayloadStore_Append(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	ps := NewPostgresPayloadStore(store, time.Hour)
+
+	ctx := context.Background()
+
+	store.EXPECT().UpsertInstancePayload(ctx, sqlc.UpsertInstancePayloadParams{
+		WorkflowInstanceID: "instance-001",
+		Source:             "user-svc",
+		Payload:            `{"username":"bene"}`,
+	}).Return(nil)
+	store.EXPECT().FindInstancePayloads(ctx, "instance-001").Return([]sqlc.FindInstancePayloadsRow{
+		{Source: "order-svc", Payload: `{"amount":10}`},
+		{Source: "user-svc", Payload: `{"username":"bene"}`},
+	}, nil)
+
+	result, err := ps.Append(ctx, "instance-001", "user-svc", map[string]any{"username": "bene"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"order-svc": map[string]any{"amount": float64(10)},
+		"user-svc":  map[string]any{"username": "bene"},
+	}, result)
+}
+
+func TestPostgresPayloadStore_Append_UpsertError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	ps := NewPostgresPayloadStore(store, time.Hour)
+
+	ctx := context.Background()
+
+	store.EXPECT().UpsertInstancePayload(ctx, gomock.Any()).Return(fmt.Errorf("error"))
+
+	_, err := ps.Append(ctx, "instance-001", "user-svc", nil)
+	assert.Error(t, err)
+}
+
+func TestPostgresPayloadStore_Expire(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	ps := NewPostgresPayloadStore(store, time.Hour)
+
+	ctx := context.Background()
+
+	store.EXPECT().ExpireInstancePayloads(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.ExpireInstancePayloadsParams) error {
+			assert.Equal(t, "instance-001", arg.WorkflowInstanceID)
+			assert.True(t, arg.ExpiresAt.Valid)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt.Time, time.Minute)
+			return nil
+		})
+
+	err := ps.Expire(ctx, "instance-001")
+	assert.NoError(t, err)
+}
+
+func TestPostgresPayloadStore_WithStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	tx := mockdb.NewMockStore(ctrl)
+	ps := NewPostgresPayloadStore(store, time.Hour).WithStore(tx)
+
+	ctx := context.Background()
+
+	// the payload is written through the transaction, not the store it was built with
+	tx.EXPECT().UpsertInstancePayload(ctx, gomock.Any()).Return(nil)
+	tx.EXPECT().FindInstancePayloads(ctx, "instance-001").Return([]sqlc.FindInstancePayloadsRow{}, nil)
+
+	_, err := ps.Append(ctx, "instance-001", "user-svc", nil)
+	assert.NoError(t, err)
+}
@@ -11,6 +11,7 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	sqlc "orchestra-svc/internal/repository/sqlc"
 	reflect "reflect"
 
@@ -40,108 +41,943 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcquireOutboxLease mocks base method.
+func (m *MockStore) AcquireOutboxLease(ctx context.Context, arg sqlc.AcquireOutboxLeaseParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireOutboxLease", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireOutboxLease indicates an expected call of AcquireOutboxLease.
+func (mr *MockStoreMockRecorder) AcquireOutboxLease(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireOutboxLease", reflect.TypeOf((*MockStore)(nil).AcquireOutboxLease), ctx, arg)
+}
+
+// CancelInstanceApprovals mocks base method.
+func (m *MockStore) CancelInstanceApprovals(ctx context.Context, workflowInstanceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelInstanceApprovals", ctx, workflowInstanceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelInstanceApprovals indicates an expected call of CancelInstanceApprovals.
+func (mr *MockStoreMockRecorder) CancelInstanceApprovals(ctx, workflowInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelInstanceApprovals", reflect.TypeOf((*MockStore)(nil).CancelInstanceApprovals), ctx, workflowInstanceID)
+}
+
+// CancelInstanceStepRetries mocks base method.
+func (m *MockStore) CancelInstanceStepRetries(ctx context.Context, workflowInstanceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelInstanceStepRetries", ctx, workflowInstanceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelInstanceStepRetries indicates an expected call of CancelInstanceStepRetries.
+func (mr *MockStoreMockRecorder) CancelInstanceStepRetries(ctx, workflowInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelInstanceStepRetries", reflect.TypeOf((*MockStore)(nil).CancelInstanceStepRetries), ctx, workflowInstanceID)
+}
+
+// CancelInstanceTimers mocks base method.
+func (m *MockStore) CancelInstanceTimers(ctx context.Context, workflowInstanceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelInstanceTimers", ctx, workflowInstanceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelInstanceTimers indicates an expected call of CancelInstanceTimers.
+func (mr *MockStoreMockRecorder) CancelInstanceTimers(ctx, workflowInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelInstanceTimers", reflect.TypeOf((*MockStore)(nil).CancelInstanceTimers), ctx, workflowInstanceID)
+}
+
 // CheckIfInstanceStepExists mocks base method.
 func (m *MockStore) CheckIfInstanceStepExists(ctx context.Context, eventID string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckIfInstanceStepExists", ctx, eventID)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "CheckIfInstanceStepExists", ctx, eventID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIfInstanceStepExists indicates an expected call of CheckIfInstanceStepExists.
+func (mr *MockStoreMockRecorder) CheckIfInstanceStepExists(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfInstanceStepExists", reflect.TypeOf((*MockStore)(nil).CheckIfInstanceStepExists), ctx, eventID)
+}
+
+// CheckIfInstanceStepExistsForStep mocks base method.
+func (m *MockStore) CheckIfInstanceStepExistsForStep(ctx context.Context, arg sqlc.CheckIfInstanceStepExistsForStepParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIfInstanceStepExistsForStep", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIfInstanceStepExistsForStep indicates an expected call of CheckIfInstanceStepExistsForStep.
+func (mr *MockStoreMockRecorder) CheckIfInstanceStepExistsForStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfInstanceStepExistsForStep", reflect.TypeOf((*MockStore)(nil).CheckIfInstanceStepExistsForStep), ctx, arg)
+}
+
+// ClaimInstanceStepRetry mocks base method.
+func (m *MockStore) ClaimInstanceStepRetry(ctx context.Context, eventID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimInstanceStepRetry", ctx, eventID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimInstanceStepRetry indicates an expected call of ClaimInstanceStepRetry.
+func (mr *MockStoreMockRecorder) ClaimInstanceStepRetry(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimInstanceStepRetry", reflect.TypeOf((*MockStore)(nil).ClaimInstanceStepRetry), ctx, eventID)
+}
+
+// ClaimProcessedEvent mocks base method.
+func (m *MockStore) ClaimProcessedEvent(ctx context.Context, arg sqlc.ClaimProcessedEventParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimProcessedEvent", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimProcessedEvent indicates an expected call of ClaimProcessedEvent.
+func (mr *MockStoreMockRecorder) ClaimProcessedEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimProcessedEvent", reflect.TypeOf((*MockStore)(nil).ClaimProcessedEvent), ctx, arg)
+}
+
+// ClaimRetryJobItems mocks base method.
+func (m *MockStore) ClaimRetryJobItems(ctx context.Context, arg sqlc.ClaimRetryJobItemsParams) ([]sqlc.RetryJobItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRetryJobItems", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.RetryJobItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimRetryJobItems indicates an expected call of ClaimRetryJobItems.
+func (mr *MockStoreMockRecorder) ClaimRetryJobItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRetryJobItems", reflect.TypeOf((*MockStore)(nil).ClaimRetryJobItems), ctx, arg)
+}
+
+// ClaimTimer mocks base method.
+func (m *MockStore) ClaimTimer(ctx context.Context, id int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimTimer", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimTimer indicates an expected call of ClaimTimer.
+func (mr *MockStoreMockRecorder) ClaimTimer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimTimer", reflect.TypeOf((*MockStore)(nil).ClaimTimer), ctx, id)
+}
+
+// CountApprovals mocks base method.
+func (m *MockStore) CountApprovals(ctx context.Context, status sql.NullString) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountApprovals", ctx, status)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountApprovals indicates an expected call of CountApprovals.
+func (mr *MockStoreMockRecorder) CountApprovals(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountApprovals", reflect.TypeOf((*MockStore)(nil).CountApprovals), ctx, status)
+}
+
+// CountDeadLetters mocks base method.
+func (m *MockStore) CountDeadLetters(ctx context.Context, arg sqlc.CountDeadLettersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeadLetters", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeadLetters indicates an expected call of CountDeadLetters.
+func (mr *MockStoreMockRecorder) CountDeadLetters(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeadLetters", reflect.TypeOf((*MockStore)(nil).CountDeadLetters), ctx, arg)
+}
+
+// CountFailedInstanceSteps mocks base method.
+func (m *MockStore) CountFailedInstanceSteps(ctx context.Context, workflowInstanceID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFailedInstanceSteps", ctx, workflowInstanceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFailedInstanceSteps indicates an expected call of CountFailedInstanceSteps.
+func (mr *MockStoreMockRecorder) CountFailedInstanceSteps(ctx, workflowInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFailedInstanceSteps", reflect.TypeOf((*MockStore)(nil).CountFailedInstanceSteps), ctx, workflowInstanceID)
+}
+
+// CountSucceededStates mocks base method.
+func (m *MockStore) CountSucceededStates(ctx context.Context, arg sqlc.CountSucceededStatesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSucceededStates", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSucceededStates indicates an expected call of CountSucceededStates.
+func (mr *MockStoreMockRecorder) CountSucceededStates(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSucceededStates", reflect.TypeOf((*MockStore)(nil).CountSucceededStates), ctx, arg)
+}
+
+// CountWorkflowInstances mocks base method.
+func (m *MockStore) CountWorkflowInstances(ctx context.Context, arg sqlc.CountWorkflowInstancesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWorkflowInstances", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWorkflowInstances indicates an expected call of CountWorkflowInstances.
+func (mr *MockStoreMockRecorder) CountWorkflowInstances(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWorkflowInstances", reflect.TypeOf((*MockStore)(nil).CountWorkflowInstances), ctx, arg)
+}
+
+// CreateApproval mocks base method.
+func (m *MockStore) CreateApproval(ctx context.Context, arg sqlc.CreateApprovalParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApproval", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateApproval indicates an expected call of CreateApproval.
+func (mr *MockStoreMockRecorder) CreateApproval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApproval", reflect.TypeOf((*MockStore)(nil).CreateApproval), ctx, arg)
+}
+
+// CreateChildWorkflowInstance mocks base method.
+func (m *MockStore) CreateChildWorkflowInstance(ctx context.Context, arg sqlc.CreateChildWorkflowInstanceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChildWorkflowInstance", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChildWorkflowInstance indicates an expected call of CreateChildWorkflowInstance.
+func (mr *MockStoreMockRecorder) CreateChildWorkflowInstance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChildWorkflowInstance", reflect.TypeOf((*MockStore)(nil).CreateChildWorkflowInstance), ctx, arg)
+}
+
+// CreateDeadLetter mocks base method.
+func (m *MockStore) CreateDeadLetter(ctx context.Context, arg sqlc.CreateDeadLetterParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeadLetter", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeadLetter indicates an expected call of CreateDeadLetter.
+func (mr *MockStoreMockRecorder) CreateDeadLetter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeadLetter", reflect.TypeOf((*MockStore)(nil).CreateDeadLetter), ctx, arg)
+}
+
+// CreateOutboxMessage mocks base method.
+func (m *MockStore) CreateOutboxMessage(ctx context.Context, arg sqlc.CreateOutboxMessageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxMessage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOutboxMessage indicates an expected call of CreateOutboxMessage.
+func (mr *MockStoreMockRecorder) CreateOutboxMessage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxMessage", reflect.TypeOf((*MockStore)(nil).CreateOutboxMessage), ctx, arg)
+}
+
+// CreatePayloadKey mocks base method.
+func (m *MockStore) CreatePayloadKey(ctx context.Context, arg sqlc.CreatePayloadKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayloadKey", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePayloadKey indicates an expected call of CreatePayloadKey.
+func (mr *MockStoreMockRecorder) CreatePayloadKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayloadKey", reflect.TypeOf((*MockStore)(nil).CreatePayloadKey), ctx, arg)
+}
+
+// CreatePayloadMapping mocks base method.
+func (m *MockStore) CreatePayloadMapping(ctx context.Context, arg sqlc.CreatePayloadMappingParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayloadMapping", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePayloadMapping indicates an expected call of CreatePayloadMapping.
+func (mr *MockStoreMockRecorder) CreatePayloadMapping(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayloadMapping", reflect.TypeOf((*MockStore)(nil).CreatePayloadMapping), ctx, arg)
+}
+
+// CreateProcessLog mocks base method.
+func (m *MockStore) CreateProcessLog(ctx context.Context, arg sqlc.CreateProcessLogParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProcessLog", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProcessLog indicates an expected call of CreateProcessLog.
+func (mr *MockStoreMockRecorder) CreateProcessLog(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProcessLog", reflect.TypeOf((*MockStore)(nil).CreateProcessLog), ctx, arg)
+}
+
+// CreateProcessedEvent mocks base method.
+func (m *MockStore) CreateProcessedEvent(ctx context.Context, arg sqlc.CreateProcessedEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProcessedEvent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProcessedEvent indicates an expected call of CreateProcessedEvent.
+func (mr *MockStoreMockRecorder) CreateProcessedEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProcessedEvent", reflect.TypeOf((*MockStore)(nil).CreateProcessedEvent), ctx, arg)
+}
+
+// CreateRetryAudit mocks base method.
+func (m *MockStore) CreateRetryAudit(ctx context.Context, arg sqlc.CreateRetryAuditParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRetryAudit", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRetryAudit indicates an expected call of CreateRetryAudit.
+func (mr *MockStoreMockRecorder) CreateRetryAudit(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRetryAudit", reflect.TypeOf((*MockStore)(nil).CreateRetryAudit), ctx, arg)
+}
+
+// CreateRetryJob mocks base method.
+func (m *MockStore) CreateRetryJob(ctx context.Context, arg sqlc.CreateRetryJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRetryJob", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRetryJob indicates an expected call of CreateRetryJob.
+func (mr *MockStoreMockRecorder) CreateRetryJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRetryJob", reflect.TypeOf((*MockStore)(nil).CreateRetryJob), ctx, arg)
+}
+
+// CreateRetryJobItem mocks base method.
+func (m *MockStore) CreateRetryJobItem(ctx context.Context, arg sqlc.CreateRetryJobItemParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRetryJobItem", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRetryJobItem indicates an expected call of CreateRetryJobItem.
+func (mr *MockStoreMockRecorder) CreateRetryJobItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRetryJobItem", reflect.TypeOf((*MockStore)(nil).CreateRetryJobItem), ctx, arg)
+}
+
+// CreateStateAction mocks base method.
+func (m *MockStore) CreateStateAction(ctx context.Context, arg sqlc.CreateStateActionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStateAction", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStateAction indicates an expected call of CreateStateAction.
+func (mr *MockStoreMockRecorder) CreateStateAction(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStateAction", reflect.TypeOf((*MockStore)(nil).CreateStateAction), ctx, arg)
+}
+
+// CreateStep mocks base method.
+func (m *MockStore) CreateStep(ctx context.Context, arg sqlc.CreateStepParams) (sqlc.Step, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStep", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Step)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStep indicates an expected call of CreateStep.
+func (mr *MockStoreMockRecorder) CreateStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStep", reflect.TypeOf((*MockStore)(nil).CreateStep), ctx, arg)
+}
+
+// CreateStepDependency mocks base method.
+func (m *MockStore) CreateStepDependency(ctx context.Context, arg sqlc.CreateStepDependencyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStepDependency", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStepDependency indicates an expected call of CreateStepDependency.
+func (mr *MockStoreMockRecorder) CreateStepDependency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStepDependency", reflect.TypeOf((*MockStore)(nil).CreateStepDependency), ctx, arg)
+}
+
+// CreateTimer mocks base method.
+func (m *MockStore) CreateTimer(ctx context.Context, arg sqlc.CreateTimerParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTimer", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTimer indicates an expected call of CreateTimer.
+func (mr *MockStoreMockRecorder) CreateTimer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTimer", reflect.TypeOf((*MockStore)(nil).CreateTimer), ctx, arg)
+}
+
+// CreateWorkflowInstance mocks base method.
+func (m *MockStore) CreateWorkflowInstance(ctx context.Context, arg sqlc.CreateWorkflowInstanceParams) (sqlc.WorkflowInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkflowInstance", ctx, arg)
+	ret0, _ := ret[0].(sqlc.WorkflowInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkflowInstance indicates an expected call of CreateWorkflowInstance.
+func (mr *MockStoreMockRecorder) CreateWorkflowInstance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkflowInstance", reflect.TypeOf((*MockStore)(nil).CreateWorkflowInstance), ctx, arg)
+}
+
+// CreateWorkflowInstanceStep mocks base method.
+func (m *MockStore) CreateWorkflowInstanceStep(ctx context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkflowInstanceStep", ctx, arg)
+	ret0, _ := ret[0].(sqlc.WorkflowInstanceStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkflowInstanceStep indicates an expected call of CreateWorkflowInstanceStep.
+func (mr *MockStoreMockRecorder) CreateWorkflowInstanceStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkflowInstanceStep", reflect.TypeOf((*MockStore)(nil).CreateWorkflowInstanceStep), ctx, arg)
+}
+
+// DecideApproval mocks base method.
+func (m *MockStore) DecideApproval(ctx context.Context, arg sqlc.DecideApprovalParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideApproval", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideApproval indicates an expected call of DecideApproval.
+func (mr *MockStoreMockRecorder) DecideApproval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideApproval", reflect.TypeOf((*MockStore)(nil).DecideApproval), ctx, arg)
+}
+
+// DeleteExpiredInstancePayloads mocks base method.
+func (m *MockStore) DeleteExpiredInstancePayloads(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredInstancePayloads", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredInstancePayloads indicates an expected call of DeleteExpiredInstancePayloads.
+func (mr *MockStoreMockRecorder) DeleteExpiredInstancePayloads(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredInstancePayloads", reflect.TypeOf((*MockStore)(nil).DeleteExpiredInstancePayloads), ctx)
+}
+
+// DeleteProcessedEvents mocks base method.
+func (m *MockStore) DeleteProcessedEvents(ctx context.Context, eventID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProcessedEvents", ctx, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProcessedEvents indicates an expected call of DeleteProcessedEvents.
+func (mr *MockStoreMockRecorder) DeleteProcessedEvents(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProcessedEvents", reflect.TypeOf((*MockStore)(nil).DeleteProcessedEvents), ctx, eventID)
+}
+
+// ExecTx mocks base method.
+func (m *MockStore) ExecTx(ctx context.Context, fn func(sqlc.Querier) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockStoreMockRecorder) ExecTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStore)(nil).ExecTx), ctx, fn)
+}
+
+// ExecTxStore mocks base method.
+func (m *MockStore) ExecTxStore(ctx context.Context, fn func(sqlc.Store) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTxStore", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTxStore indicates an expected call of ExecTxStore.
+func (mr *MockStoreMockRecorder) ExecTxStore(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTxStore", reflect.TypeOf((*MockStore)(nil).ExecTxStore), ctx, fn)
+}
+
+// ExpireInstancePayloads mocks base method.
+func (m *MockStore) ExpireInstancePayloads(ctx context.Context, arg sqlc.ExpireInstancePayloadsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireInstancePayloads", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireInstancePayloads indicates an expected call of ExpireInstancePayloads.
+func (mr *MockStoreMockRecorder) ExpireInstancePayloads(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireInstancePayloads", reflect.TypeOf((*MockStore)(nil).ExpireInstancePayloads), ctx, arg)
+}
+
+// FindApproval mocks base method.
+func (m *MockStore) FindApproval(ctx context.Context, id int32) (sqlc.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApproval", ctx, id)
+	ret0, _ := ret[0].(sqlc.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApproval indicates an expected call of FindApproval.
+func (mr *MockStoreMockRecorder) FindApproval(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApproval", reflect.TypeOf((*MockStore)(nil).FindApproval), ctx, id)
+}
+
+// FindChildWorkflowInstanceIDs mocks base method.
+func (m *MockStore) FindChildWorkflowInstanceIDs(ctx context.Context, parentInstanceID sql.NullString) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChildWorkflowInstanceIDs", ctx, parentInstanceID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChildWorkflowInstanceIDs indicates an expected call of FindChildWorkflowInstanceIDs.
+func (mr *MockStoreMockRecorder) FindChildWorkflowInstanceIDs(ctx, parentInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChildWorkflowInstanceIDs", reflect.TypeOf((*MockStore)(nil).FindChildWorkflowInstanceIDs), ctx, parentInstanceID)
+}
+
+// FindCompensableInstanceSteps mocks base method.
+func (m *MockStore) FindCompensableInstanceSteps(ctx context.Context, workflowInstanceID string) ([]sqlc.FindCompensableInstanceStepsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCompensableInstanceSteps", ctx, workflowInstanceID)
+	ret0, _ := ret[0].([]sqlc.FindCompensableInstanceStepsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCompensableInstanceSteps indicates an expected call of FindCompensableInstanceSteps.
+func (mr *MockStoreMockRecorder) FindCompensableInstanceSteps(ctx, workflowInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCompensableInstanceSteps", reflect.TypeOf((*MockStore)(nil).FindCompensableInstanceSteps), ctx, workflowInstanceID)
+}
+
+// FindDeadLettersByIDs mocks base method.
+func (m *MockStore) FindDeadLettersByIDs(ctx context.Context, ids []int32) ([]sqlc.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeadLettersByIDs", ctx, ids)
+	ret0, _ := ret[0].([]sqlc.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeadLettersByIDs indicates an expected call of FindDeadLettersByIDs.
+func (mr *MockStoreMockRecorder) FindDeadLettersByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeadLettersByIDs", reflect.TypeOf((*MockStore)(nil).FindDeadLettersByIDs), ctx, ids)
+}
+
+// FindDueInstanceStepRetries mocks base method.
+func (m *MockStore) FindDueInstanceStepRetries(ctx context.Context, limit int32) ([]sqlc.FindDueInstanceStepRetriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueInstanceStepRetries", ctx, limit)
+	ret0, _ := ret[0].([]sqlc.FindDueInstanceStepRetriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueInstanceStepRetries indicates an expected call of FindDueInstanceStepRetries.
+func (mr *MockStoreMockRecorder) FindDueInstanceStepRetries(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueInstanceStepRetries", reflect.TypeOf((*MockStore)(nil).FindDueInstanceStepRetries), ctx, limit)
+}
+
+// FindDueTimers mocks base method.
+func (m *MockStore) FindDueTimers(ctx context.Context, limit int32) ([]sqlc.Timer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueTimers", ctx, limit)
+	ret0, _ := ret[0].([]sqlc.Timer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueTimers indicates an expected call of FindDueTimers.
+func (mr *MockStoreMockRecorder) FindDueTimers(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueTimers", reflect.TypeOf((*MockStore)(nil).FindDueTimers), ctx, limit)
+}
+
+// FindExpiredApprovals mocks base method.
+func (m *MockStore) FindExpiredApprovals(ctx context.Context, limit int32) ([]sqlc.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpiredApprovals", ctx, limit)
+	ret0, _ := ret[0].([]sqlc.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpiredApprovals indicates an expected call of FindExpiredApprovals.
+func (mr *MockStoreMockRecorder) FindExpiredApprovals(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpiredApprovals", reflect.TypeOf((*MockStore)(nil).FindExpiredApprovals), ctx, limit)
+}
+
+// FindFailedInstanceSteps mocks base method.
+func (m *MockStore) FindFailedInstanceSteps(ctx context.Context, arg sqlc.FindFailedInstanceStepsParams) ([]sqlc.FindFailedInstanceStepsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFailedInstanceSteps", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.FindFailedInstanceStepsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFailedInstanceSteps indicates an expected call of FindFailedInstanceSteps.
+func (mr *MockStoreMockRecorder) FindFailedInstanceSteps(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFailedInstanceSteps", reflect.TypeOf((*MockStore)(nil).FindFailedInstanceSteps), ctx, arg)
+}
+
+// FindInstancePayloads mocks base method.
+func (m *MockStore) FindInstancePayloads(ctx context.Context, workflowInstanceID string) ([]sqlc.FindInstancePayloadsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInstancePayloads", ctx, workflowInstanceID)
+	ret0, _ := ret[0].([]sqlc.FindInstancePayloadsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInstancePayloads indicates an expected call of FindInstancePayloads.
+func (mr *MockStoreMockRecorder) FindInstancePayloads(ctx, workflowInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInstancePayloads", reflect.TypeOf((*MockStore)(nil).FindInstancePayloads), ctx, workflowInstanceID)
+}
+
+// FindInstanceStepByEventID mocks base method.
+func (m *MockStore) FindInstanceStepByEventID(ctx context.Context, eventID string) (sqlc.WorkflowInstanceStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInstanceStepByEventID", ctx, eventID)
+	ret0, _ := ret[0].(sqlc.WorkflowInstanceStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInstanceStepByEventID indicates an expected call of FindInstanceStepByEventID.
+func (mr *MockStoreMockRecorder) FindInstanceStepByEventID(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInstanceStepByEventID", reflect.TypeOf((*MockStore)(nil).FindInstanceStepByEventID), ctx, eventID)
+}
+
+// FindInstanceStepByID mocks base method.
+func (m *MockStore) FindInstanceStepByID(ctx context.Context, workflowInstanceID string) ([]sqlc.WorkflowInstanceStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInstanceStepByID", ctx, workflowInstanceID)
+	ret0, _ := ret[0].([]sqlc.WorkflowInstanceStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInstanceStepByID indicates an expected call of FindInstanceStepByID.
+func (mr *MockStoreMockRecorder) FindInstanceStepByID(ctx, workflowInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInstanceStepByID", reflect.TypeOf((*MockStore)(nil).FindInstanceStepByID), ctx, workflowInstanceID)
+}
+
+// FindInstanceStepRetryPolicy mocks base method.
+func (m *MockStore) FindInstanceStepRetryPolicy(ctx context.Context, eventID string) (sqlc.FindInstanceStepRetryPolicyRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInstanceStepRetryPolicy", ctx, eventID)
+	ret0, _ := ret[0].(sqlc.FindInstanceStepRetryPolicyRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInstanceStepRetryPolicy indicates an expected call of FindInstanceStepRetryPolicy.
+func (mr *MockStoreMockRecorder) FindInstanceStepRetryPolicy(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInstanceStepRetryPolicy", reflect.TypeOf((*MockStore)(nil).FindInstanceStepRetryPolicy), ctx, eventID)
+}
+
+// FindInstanceStepsWithStep mocks base method.
+func (m *MockStore) FindInstanceStepsWithStep(ctx context.Context, workflowInstanceID string) ([]sqlc.FindInstanceStepsWithStepRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInstanceStepsWithStep", ctx, workflowInstanceID)
+	ret0, _ := ret[0].([]sqlc.FindInstanceStepsWithStepRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInstanceStepsWithStep indicates an expected call of FindInstanceStepsWithStep.
+func (mr *MockStoreMockRecorder) FindInstanceStepsWithStep(ctx, workflowInstanceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInstanceStepsWithStep", reflect.TypeOf((*MockStore)(nil).FindInstanceStepsWithStep), ctx, workflowInstanceID)
+}
+
+// FindPayloadKeysByStepID mocks base method.
+func (m *MockStore) FindPayloadKeysByStepID(ctx context.Context, stepID int32) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPayloadKeysByStepID", ctx, stepID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPayloadKeysByStepID indicates an expected call of FindPayloadKeysByStepID.
+func (mr *MockStoreMockRecorder) FindPayloadKeysByStepID(ctx, stepID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayloadKeysByStepID", reflect.TypeOf((*MockStore)(nil).FindPayloadKeysByStepID), ctx, stepID)
+}
+
+// FindPayloadMappingsByStepID mocks base method.
+func (m *MockStore) FindPayloadMappingsByStepID(ctx context.Context, stepID int32) ([]sqlc.PayloadMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPayloadMappingsByStepID", ctx, stepID)
+	ret0, _ := ret[0].([]sqlc.PayloadMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPayloadMappingsByStepID indicates an expected call of FindPayloadMappingsByStepID.
+func (mr *MockStoreMockRecorder) FindPayloadMappingsByStepID(ctx, stepID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayloadMappingsByStepID", reflect.TypeOf((*MockStore)(nil).FindPayloadMappingsByStepID), ctx, stepID)
+}
+
+// FindPendingOutboxMessages mocks base method.
+func (m *MockStore) FindPendingOutboxMessages(ctx context.Context, limit int32) ([]sqlc.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingOutboxMessages", ctx, limit)
+	ret0, _ := ret[0].([]sqlc.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingOutboxMessages indicates an expected call of FindPendingOutboxMessages.
+func (mr *MockStoreMockRecorder) FindPendingOutboxMessages(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingOutboxMessages", reflect.TypeOf((*MockStore)(nil).FindPendingOutboxMessages), ctx, limit)
+}
+
+// FindProcessLogsByInstanceID mocks base method.
+func (m *MockStore) FindProcessLogsByInstanceID(ctx context.Context, workflowInstanceID string) ([]sqlc.ProcessLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProcessLogsByInstanceID", ctx, workflowInstanceID)
+	ret0, _ := ret[0].([]sqlc.ProcessLog)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckIfInstanceStepExists indicates an expected call of CheckIfInstanceStepExists.
-func (mr *MockStoreMockRecorder) CheckIfInstanceStepExists(ctx, eventID any) *gomock.Call {
+// FindProcessLogsByInstanceID indicates an expected call of FindProcessLogsByInstanceID.
+func (mr *MockStoreMockRecorder) FindProcessLogsByInstanceID(ctx, workflowInstanceID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfInstanceStepExists", reflect.TypeOf((*MockStore)(nil).CheckIfInstanceStepExists), ctx, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProcessLogsByInstanceID", reflect.TypeOf((*MockStore)(nil).FindProcessLogsByInstanceID), ctx, workflowInstanceID)
 }
 
-// CreateProcessLog mocks base method.
-func (m *MockStore) CreateProcessLog(ctx context.Context, arg sqlc.CreateProcessLogParams) error {
+// FindRetryAuditsByInstanceID mocks base method.
+func (m *MockStore) FindRetryAuditsByInstanceID(ctx context.Context, workflowInstanceID string) ([]sqlc.RetryAudit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateProcessLog", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "FindRetryAuditsByInstanceID", ctx, workflowInstanceID)
+	ret0, _ := ret[0].([]sqlc.RetryAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CreateProcessLog indicates an expected call of CreateProcessLog.
-func (mr *MockStoreMockRecorder) CreateProcessLog(ctx, arg any) *gomock.Call {
+// FindRetryAuditsByInstanceID indicates an expected call of FindRetryAuditsByInstanceID.
+func (mr *MockStoreMockRecorder) FindRetryAuditsByInstanceID(ctx, workflowInstanceID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProcessLog", reflect.TypeOf((*MockStore)(nil).CreateProcessLog), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRetryAuditsByInstanceID", reflect.TypeOf((*MockStore)(nil).FindRetryAuditsByInstanceID), ctx, workflowInstanceID)
 }
 
-// CreateWorkflowInstance mocks base method.
-func (m *MockStore) CreateWorkflowInstance(ctx context.Context, arg sqlc.CreateWorkflowInstanceParams) (sqlc.WorkflowInstance, error) {
+// FindRetryJob mocks base method.
+func (m *MockStore) FindRetryJob(ctx context.Context, id string) (sqlc.RetryJob, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateWorkflowInstance", ctx, arg)
-	ret0, _ := ret[0].(sqlc.WorkflowInstance)
+	ret := m.ctrl.Call(m, "FindRetryJob", ctx, id)
+	ret0, _ := ret[0].(sqlc.RetryJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateWorkflowInstance indicates an expected call of CreateWorkflowInstance.
-func (mr *MockStoreMockRecorder) CreateWorkflowInstance(ctx, arg any) *gomock.Call {
+// FindRetryJob indicates an expected call of FindRetryJob.
+func (mr *MockStoreMockRecorder) FindRetryJob(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkflowInstance", reflect.TypeOf((*MockStore)(nil).CreateWorkflowInstance), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRetryJob", reflect.TypeOf((*MockStore)(nil).FindRetryJob), ctx, id)
 }
 
-// CreateWorkflowInstanceStep mocks base method.
-func (m *MockStore) CreateWorkflowInstanceStep(ctx context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+// FindRetryJobItems mocks base method.
+func (m *MockStore) FindRetryJobItems(ctx context.Context, jobID string) ([]sqlc.RetryJobItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateWorkflowInstanceStep", ctx, arg)
-	ret0, _ := ret[0].(sqlc.WorkflowInstanceStep)
+	ret := m.ctrl.Call(m, "FindRetryJobItems", ctx, jobID)
+	ret0, _ := ret[0].([]sqlc.RetryJobItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateWorkflowInstanceStep indicates an expected call of CreateWorkflowInstanceStep.
-func (mr *MockStoreMockRecorder) CreateWorkflowInstanceStep(ctx, arg any) *gomock.Call {
+// FindRetryJobItems indicates an expected call of FindRetryJobItems.
+func (mr *MockStoreMockRecorder) FindRetryJobItems(ctx, jobID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkflowInstanceStep", reflect.TypeOf((*MockStore)(nil).CreateWorkflowInstanceStep), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRetryJobItems", reflect.TypeOf((*MockStore)(nil).FindRetryJobItems), ctx, jobID)
 }
 
-// FindInstanceStepByEventID mocks base method.
-func (m *MockStore) FindInstanceStepByEventID(ctx context.Context, eventID string) (sqlc.WorkflowInstanceStep, error) {
+// FindRunningRetryJobs mocks base method.
+func (m *MockStore) FindRunningRetryJobs(ctx context.Context) ([]sqlc.RetryJob, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindInstanceStepByEventID", ctx, eventID)
-	ret0, _ := ret[0].(sqlc.WorkflowInstanceStep)
+	ret := m.ctrl.Call(m, "FindRunningRetryJobs", ctx)
+	ret0, _ := ret[0].([]sqlc.RetryJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindInstanceStepByEventID indicates an expected call of FindInstanceStepByEventID.
-func (mr *MockStoreMockRecorder) FindInstanceStepByEventID(ctx, eventID any) *gomock.Call {
+// FindRunningRetryJobs indicates an expected call of FindRunningRetryJobs.
+func (mr *MockStoreMockRecorder) FindRunningRetryJobs(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInstanceStepByEventID", reflect.TypeOf((*MockStore)(nil).FindInstanceStepByEventID), ctx, eventID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRunningRetryJobs", reflect.TypeOf((*MockStore)(nil).FindRunningRetryJobs), ctx)
 }
 
-// FindInstanceStepByID mocks base method.
-func (m *MockStore) FindInstanceStepByID(ctx context.Context, workflowInstanceID string) ([]sqlc.WorkflowInstanceStep, error) {
+// FindStateActionsByType mocks base method.
+func (m *MockStore) FindStateActionsByType(ctx context.Context, arg sqlc.FindStateActionsByTypeParams) ([]sqlc.FindStateActionsByTypeRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindInstanceStepByID", ctx, workflowInstanceID)
-	ret0, _ := ret[0].([]sqlc.WorkflowInstanceStep)
+	ret := m.ctrl.Call(m, "FindStateActionsByType", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.FindStateActionsByTypeRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindInstanceStepByID indicates an expected call of FindInstanceStepByID.
-func (mr *MockStoreMockRecorder) FindInstanceStepByID(ctx, workflowInstanceID any) *gomock.Call {
+// FindStateActionsByType indicates an expected call of FindStateActionsByType.
+func (mr *MockStoreMockRecorder) FindStateActionsByType(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInstanceStepByID", reflect.TypeOf((*MockStore)(nil).FindInstanceStepByID), ctx, workflowInstanceID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStateActionsByType", reflect.TypeOf((*MockStore)(nil).FindStateActionsByType), ctx, arg)
 }
 
-// FindPayloadKeysByStepID mocks base method.
-func (m *MockStore) FindPayloadKeysByStepID(ctx context.Context, stepID int32) ([]string, error) {
+// FindStepByID mocks base method.
+func (m *MockStore) FindStepByID(ctx context.Context, id int32) (sqlc.Step, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindPayloadKeysByStepID", ctx, stepID)
+	ret := m.ctrl.Call(m, "FindStepByID", ctx, id)
+	ret0, _ := ret[0].(sqlc.Step)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStepByID indicates an expected call of FindStepByID.
+func (mr *MockStoreMockRecorder) FindStepByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStepByID", reflect.TypeOf((*MockStore)(nil).FindStepByID), ctx, id)
+}
+
+// FindStepDependenciesByStepID mocks base method.
+func (m *MockStore) FindStepDependenciesByStepID(ctx context.Context, stepID int32) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStepDependenciesByStepID", ctx, stepID)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindPayloadKeysByStepID indicates an expected call of FindPayloadKeysByStepID.
-func (mr *MockStoreMockRecorder) FindPayloadKeysByStepID(ctx, stepID any) *gomock.Call {
+// FindStepDependenciesByStepID indicates an expected call of FindStepDependenciesByStepID.
+func (mr *MockStoreMockRecorder) FindStepDependenciesByStepID(ctx, stepID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayloadKeysByStepID", reflect.TypeOf((*MockStore)(nil).FindPayloadKeysByStepID), ctx, stepID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStepDependenciesByStepID", reflect.TypeOf((*MockStore)(nil).FindStepDependenciesByStepID), ctx, stepID)
+}
+
+// FindStepStats mocks base method.
+func (m *MockStore) FindStepStats(ctx context.Context, arg sqlc.FindStepStatsParams) ([]sqlc.FindStepStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStepStats", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.FindStepStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStepStats indicates an expected call of FindStepStats.
+func (mr *MockStoreMockRecorder) FindStepStats(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStepStats", reflect.TypeOf((*MockStore)(nil).FindStepStats), ctx, arg)
+}
+
+// FindStepStatusCodeCounts mocks base method.
+func (m *MockStore) FindStepStatusCodeCounts(ctx context.Context, arg sqlc.FindStepStatusCodeCountsParams) ([]sqlc.FindStepStatusCodeCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStepStatusCodeCounts", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.FindStepStatusCodeCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStepStatusCodeCounts indicates an expected call of FindStepStatusCodeCounts.
+func (mr *MockStoreMockRecorder) FindStepStatusCodeCounts(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStepStatusCodeCounts", reflect.TypeOf((*MockStore)(nil).FindStepStatusCodeCounts), ctx, arg)
 }
 
 // FindStepsByTypeAndState mocks base method.
@@ -159,6 +995,36 @@ func (mr *MockStoreMockRecorder) FindStepsByTypeAndState(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStepsByTypeAndState", reflect.TypeOf((*MockStore)(nil).FindStepsByTypeAndState), ctx, arg)
 }
 
+// FindStepsByWorkflowType mocks base method.
+func (m *MockStore) FindStepsByWorkflowType(ctx context.Context, arg sqlc.FindStepsByWorkflowTypeParams) ([]sqlc.Step, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStepsByWorkflowType", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Step)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStepsByWorkflowType indicates an expected call of FindStepsByWorkflowType.
+func (mr *MockStoreMockRecorder) FindStepsByWorkflowType(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStepsByWorkflowType", reflect.TypeOf((*MockStore)(nil).FindStepsByWorkflowType), ctx, arg)
+}
+
+// FindTimedOutInstanceSteps mocks base method.
+func (m *MockStore) FindTimedOutInstanceSteps(ctx context.Context, limit int32) ([]sqlc.FindTimedOutInstanceStepsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTimedOutInstanceSteps", ctx, limit)
+	ret0, _ := ret[0].([]sqlc.FindTimedOutInstanceStepsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTimedOutInstanceSteps indicates an expected call of FindTimedOutInstanceSteps.
+func (mr *MockStoreMockRecorder) FindTimedOutInstanceSteps(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTimedOutInstanceSteps", reflect.TypeOf((*MockStore)(nil).FindTimedOutInstanceSteps), ctx, limit)
+}
+
 // FindWorkflowByType mocks base method.
 func (m *MockStore) FindWorkflowByType(ctx context.Context, type_ string) (sqlc.Workflow, error) {
 	m.ctrl.T.Helper()
@@ -219,6 +1085,252 @@ func (mr *MockStoreMockRecorder) FindWorkflowInstanceStepsByEventIDAndInsID(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWorkflowInstanceStepsByEventIDAndInsID", reflect.TypeOf((*MockStore)(nil).FindWorkflowInstanceStepsByEventIDAndInsID), ctx, arg)
 }
 
+// FindWorkflowInstanceWithType mocks base method.
+func (m *MockStore) FindWorkflowInstanceWithType(ctx context.Context, id string) (sqlc.FindWorkflowInstanceWithTypeRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWorkflowInstanceWithType", ctx, id)
+	ret0, _ := ret[0].(sqlc.FindWorkflowInstanceWithTypeRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWorkflowInstanceWithType indicates an expected call of FindWorkflowInstanceWithType.
+func (mr *MockStoreMockRecorder) FindWorkflowInstanceWithType(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWorkflowInstanceWithType", reflect.TypeOf((*MockStore)(nil).FindWorkflowInstanceWithType), ctx, id)
+}
+
+// FinishRetryJobItem mocks base method.
+func (m *MockStore) FinishRetryJobItem(ctx context.Context, arg sqlc.FinishRetryJobItemParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRetryJobItem", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRetryJobItem indicates an expected call of FinishRetryJobItem.
+func (mr *MockStoreMockRecorder) FinishRetryJobItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRetryJobItem", reflect.TypeOf((*MockStore)(nil).FinishRetryJobItem), ctx, arg)
+}
+
+// ListApprovals mocks base method.
+func (m *MockStore) ListApprovals(ctx context.Context, arg sqlc.ListApprovalsParams) ([]sqlc.Approval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApprovals", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Approval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApprovals indicates an expected call of ListApprovals.
+func (mr *MockStoreMockRecorder) ListApprovals(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApprovals", reflect.TypeOf((*MockStore)(nil).ListApprovals), ctx, arg)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockStore) ListDeadLetters(ctx context.Context, arg sqlc.ListDeadLettersParams) ([]sqlc.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockStoreMockRecorder) ListDeadLetters(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockStore)(nil).ListDeadLetters), ctx, arg)
+}
+
+// ListWorkflowInstances mocks base method.
+func (m *MockStore) ListWorkflowInstances(ctx context.Context, arg sqlc.ListWorkflowInstancesParams) ([]sqlc.ListWorkflowInstancesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkflowInstances", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.ListWorkflowInstancesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkflowInstances indicates an expected call of ListWorkflowInstances.
+func (mr *MockStoreMockRecorder) ListWorkflowInstances(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkflowInstances", reflect.TypeOf((*MockStore)(nil).ListWorkflowInstances), ctx, arg)
+}
+
+// ListWorkflows mocks base method.
+func (m *MockStore) ListWorkflows(ctx context.Context) ([]sqlc.Workflow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkflows", ctx)
+	ret0, _ := ret[0].([]sqlc.Workflow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkflows indicates an expected call of ListWorkflows.
+func (mr *MockStoreMockRecorder) ListWorkflows(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkflows", reflect.TypeOf((*MockStore)(nil).ListWorkflows), ctx)
+}
+
+// LockWorkflowInstance mocks base method.
+func (m *MockStore) LockWorkflowInstance(ctx context.Context, id string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockWorkflowInstance", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockWorkflowInstance indicates an expected call of LockWorkflowInstance.
+func (mr *MockStoreMockRecorder) LockWorkflowInstance(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockWorkflowInstance", reflect.TypeOf((*MockStore)(nil).LockWorkflowInstance), ctx, id)
+}
+
+// MarkDeadLetterReplayed mocks base method.
+func (m *MockStore) MarkDeadLetterReplayed(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDeadLetterReplayed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDeadLetterReplayed indicates an expected call of MarkDeadLetterReplayed.
+func (mr *MockStoreMockRecorder) MarkDeadLetterReplayed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeadLetterReplayed", reflect.TypeOf((*MockStore)(nil).MarkDeadLetterReplayed), ctx, id)
+}
+
+// MarkOutboxMessageFailed mocks base method.
+func (m *MockStore) MarkOutboxMessageFailed(ctx context.Context, arg sqlc.MarkOutboxMessageFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxMessageFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxMessageFailed indicates an expected call of MarkOutboxMessageFailed.
+func (mr *MockStoreMockRecorder) MarkOutboxMessageFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxMessageFailed", reflect.TypeOf((*MockStore)(nil).MarkOutboxMessageFailed), ctx, arg)
+}
+
+// MarkOutboxMessageSent mocks base method.
+func (m *MockStore) MarkOutboxMessageSent(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxMessageSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxMessageSent indicates an expected call of MarkOutboxMessageSent.
+func (mr *MockStoreMockRecorder) MarkOutboxMessageSent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxMessageSent", reflect.TypeOf((*MockStore)(nil).MarkOutboxMessageSent), ctx, id)
+}
+
+// RefreshRetryJobProgress mocks base method.
+func (m *MockStore) RefreshRetryJobProgress(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRetryJobProgress", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshRetryJobProgress indicates an expected call of RefreshRetryJobProgress.
+func (mr *MockStoreMockRecorder) RefreshRetryJobProgress(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRetryJobProgress", reflect.TypeOf((*MockStore)(nil).RefreshRetryJobProgress), ctx, id)
+}
+
+// RescheduleTimer mocks base method.
+func (m *MockStore) RescheduleTimer(ctx context.Context, arg sqlc.RescheduleTimerParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleTimer", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleTimer indicates an expected call of RescheduleTimer.
+func (mr *MockStoreMockRecorder) RescheduleTimer(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleTimer", reflect.TypeOf((*MockStore)(nil).RescheduleTimer), ctx, arg)
+}
+
+// ScheduleInstanceStepRetry mocks base method.
+func (m *MockStore) ScheduleInstanceStepRetry(ctx context.Context, arg sqlc.ScheduleInstanceStepRetryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleInstanceStepRetry", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleInstanceStepRetry indicates an expected call of ScheduleInstanceStepRetry.
+func (mr *MockStoreMockRecorder) ScheduleInstanceStepRetry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleInstanceStepRetry", reflect.TypeOf((*MockStore)(nil).ScheduleInstanceStepRetry), ctx, arg)
+}
+
+// TimeoutInstanceStep mocks base method.
+func (m *MockStore) TimeoutInstanceStep(ctx context.Context, eventID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TimeoutInstanceStep", ctx, eventID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TimeoutInstanceStep indicates an expected call of TimeoutInstanceStep.
+func (mr *MockStoreMockRecorder) TimeoutInstanceStep(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimeoutInstanceStep", reflect.TypeOf((*MockStore)(nil).TimeoutInstanceStep), ctx, eventID)
+}
+
+// TransitionWorkflowInstance mocks base method.
+func (m *MockStore) TransitionWorkflowInstance(ctx context.Context, arg sqlc.TransitionWorkflowInstanceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionWorkflowInstance", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionWorkflowInstance indicates an expected call of TransitionWorkflowInstance.
+func (mr *MockStoreMockRecorder) TransitionWorkflowInstance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionWorkflowInstance", reflect.TypeOf((*MockStore)(nil).TransitionWorkflowInstance), ctx, arg)
+}
+
+// UpdateStepCompensation mocks base method.
+func (m *MockStore) UpdateStepCompensation(ctx context.Context, arg sqlc.UpdateStepCompensationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStepCompensation", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStepCompensation indicates an expected call of UpdateStepCompensation.
+func (mr *MockStoreMockRecorder) UpdateStepCompensation(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStepCompensation", reflect.TypeOf((*MockStore)(nil).UpdateStepCompensation), ctx, arg)
+}
+
+// UpdateTimerStatus mocks base method.
+func (m *MockStore) UpdateTimerStatus(ctx context.Context, arg sqlc.UpdateTimerStatusParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTimerStatus", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTimerStatus indicates an expected call of UpdateTimerStatus.
+func (mr *MockStoreMockRecorder) UpdateTimerStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTimerStatus", reflect.TypeOf((*MockStore)(nil).UpdateTimerStatus), ctx, arg)
+}
+
 // UpdateWorkflowInstance mocks base method.
 func (m *MockStore) UpdateWorkflowInstance(ctx context.Context, arg sqlc.UpdateWorkflowInstanceParams) error {
 	m.ctrl.T.Helper()
@@ -246,3 +1358,46 @@ func (mr *MockStoreMockRecorder) UpdateWorkflowInstanceStep(ctx, arg any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkflowInstanceStep", reflect.TypeOf((*MockStore)(nil).UpdateWorkflowInstanceStep), ctx, arg)
 }
+
+// UpdateWorkflowInstanceStepStatus mocks base method.
+func (m *MockStore) UpdateWorkflowInstanceStepStatus(ctx context.Context, arg sqlc.UpdateWorkflowInstanceStepStatusParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkflowInstanceStepStatus", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkflowInstanceStepStatus indicates an expected call of UpdateWorkflowInstanceStepStatus.
+func (mr *MockStoreMockRecorder) UpdateWorkflowInstanceStepStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkflowInstanceStepStatus", reflect.TypeOf((*MockStore)(nil).UpdateWorkflowInstanceStepStatus), ctx, arg)
+}
+
+// UpsertInstancePayload mocks base method.
+func (m *MockStore) UpsertInstancePayload(ctx context.Context, arg sqlc.UpsertInstancePayloadParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertInstancePayload", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertInstancePayload indicates an expected call of UpsertInstancePayload.
+func (mr *MockStoreMockRecorder) UpsertInstancePayload(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertInstancePayload", reflect.TypeOf((*MockStore)(nil).UpsertInstancePayload), ctx, arg)
+}
+
+// UpsertWorkflow mocks base method.
+func (m *MockStore) UpsertWorkflow(ctx context.Context, arg sqlc.UpsertWorkflowParams) (sqlc.Workflow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkflow", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Workflow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkflow indicates an expected call of UpsertWorkflow.
+func (mr *MockStoreMockRecorder) UpsertWorkflow(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkflow", reflect.TypeOf((*MockStore)(nil).UpsertWorkflow), ctx, arg)
+}
@@ -6,8 +6,73 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
+	"time"
 )
 
+type Approval struct {
+	ID                 int32           `json:"id"`
+	WorkflowInstanceID string          `json:"workflow_instance_id"`
+	EventID            string          `json:"event_id"`
+	StepID             int32           `json:"step_id"`
+	EventType          string          `json:"event_type"`
+	ApprovedState      string          `json:"approved_state"`
+	RejectedState      string          `json:"rejected_state"`
+	Request            json.RawMessage `json:"request"`
+	Status             string          `json:"status"`
+	ExpiresAt          sql.NullTime    `json:"expires_at"`
+	DecidedBy          string          `json:"decided_by"`
+	Reason             string          `json:"reason"`
+	CreatedAt          sql.NullTime    `json:"created_at"`
+	DecidedAt          sql.NullTime    `json:"decided_at"`
+}
+
+type DeadLetter struct {
+	ID             int32           `json:"id"`
+	Service        string          `json:"service"`
+	Topic          string          `json:"topic"`
+	KafkaPartition int32           `json:"kafka_partition"`
+	KafkaOffset    int64           `json:"kafka_offset"`
+	MessageKey     string          `json:"message_key"`
+	Payload        []byte          `json:"payload"`
+	Headers        json.RawMessage `json:"headers"`
+	Error          string          `json:"error"`
+	Attempts       int32           `json:"attempts"`
+	Status         string          `json:"status"`
+	ReplayCount    int32           `json:"replay_count"`
+	FailedAt       time.Time       `json:"failed_at"`
+	CreatedAt      sql.NullTime    `json:"created_at"`
+	ReplayedAt     sql.NullTime    `json:"replayed_at"`
+}
+
+type InstancePayload struct {
+	ID                 int32        `json:"id"`
+	WorkflowInstanceID string       `json:"workflow_instance_id"`
+	Source             string       `json:"source"`
+	Payload            string       `json:"payload"`
+	ExpiresAt          sql.NullTime `json:"expires_at"`
+	CreatedAt          sql.NullTime `json:"created_at"`
+	UpdatedAt          sql.NullTime `json:"updated_at"`
+}
+
+type Outbox struct {
+	ID         int32          `json:"id"`
+	Topic      string         `json:"topic"`
+	MessageKey string         `json:"message_key"`
+	Payload    string         `json:"payload"`
+	Status     string         `json:"status"`
+	Attempts   int32          `json:"attempts"`
+	LastError  sql.NullString `json:"last_error"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+	SentAt     sql.NullTime   `json:"sent_at"`
+}
+
+type OutboxLease struct {
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 type PayloadKey struct {
 	ID        int32        `json:"id"`
 	StepID    int32        `json:"step_id"`
@@ -16,6 +81,16 @@ type PayloadKey struct {
 	UpdatedAt sql.NullTime `json:"updated_at"`
 }
 
+type PayloadMapping struct {
+	ID           int32           `json:"id"`
+	StepID       int32           `json:"step_id"`
+	Target       string          `json:"target"`
+	Source       string          `json:"source"`
+	DefaultValue json.RawMessage `json:"default_value"`
+	Required     bool            `json:"required"`
+	CreatedAt    sql.NullTime    `json:"created_at"`
+}
+
 type ProcessLog struct {
 	ID                 int32         `json:"id"`
 	EventID            string        `json:"event_id"`
@@ -34,16 +109,93 @@ type StateAction struct {
 	StepID    int32        `json:"step_id"`
 	CreatedAt sql.NullTime `json:"created_at"`
 	UpdatedAt sql.NullTime `json:"updated_at"`
+	Version   int32        `json:"version"`
+	Guard     string       `json:"guard"`
+}
+
+type ProcessedEvent struct {
+	EventID     string       `json:"event_id"`
+	Source      string       `json:"source"`
+	State       string       `json:"state"`
+	ProcessedAt sql.NullTime `json:"processed_at"`
+}
+
+type RetryAudit struct {
+	ID                 int32           `json:"id"`
+	WorkflowInstanceID string          `json:"workflow_instance_id"`
+	EventID            string          `json:"event_id"`
+	PatchType          string          `json:"patch_type"`
+	Patch              json.RawMessage `json:"patch"`
+	OriginalMessage    string          `json:"original_message"`
+	RetriedMessage     string          `json:"retried_message"`
+	CreatedAt          sql.NullTime    `json:"created_at"`
+}
+
+type RetryJob struct {
+	ID          string          `json:"id"`
+	Filter      json.RawMessage `json:"filter"`
+	Rate        int32           `json:"rate"`
+	Status      string          `json:"status"`
+	Total       int32           `json:"total"`
+	Succeeded   int32           `json:"succeeded"`
+	Failed      int32           `json:"failed"`
+	CreatedAt   sql.NullTime    `json:"created_at"`
+	CompletedAt sql.NullTime    `json:"completed_at"`
+}
+
+type RetryJobItem struct {
+	ID                 int32        `json:"id"`
+	JobID              string       `json:"job_id"`
+	EventID            string       `json:"event_id"`
+	WorkflowInstanceID string       `json:"workflow_instance_id"`
+	Status             string       `json:"status"`
+	Error              string       `json:"error"`
+	ProcessedAt        sql.NullTime `json:"processed_at"`
+	ClaimedAt          sql.NullTime `json:"claimed_at"`
 }
 
 type Step struct {
-	ID          int32        `json:"id"`
-	Name        string       `json:"name"`
-	Description string       `json:"description"`
-	Service     string       `json:"service"`
-	Topic       string       `json:"topic"`
-	CreatedAt   sql.NullTime `json:"created_at"`
-	UpdatedAt   sql.NullTime `json:"updated_at"`
+	ID                   int32         `json:"id"`
+	Name                 string        `json:"name"`
+	Description          string        `json:"description"`
+	Service              string        `json:"service"`
+	Topic                string        `json:"topic"`
+	CreatedAt            sql.NullTime  `json:"created_at"`
+	UpdatedAt            sql.NullTime  `json:"updated_at"`
+	CompensationStepID   sql.NullInt32 `json:"compensation_step_id"`
+	Emits                []string      `json:"emits"`
+	TimeoutSeconds       int32         `json:"timeout_seconds"`
+	TimeoutAction        string        `json:"timeout_action"`
+	MaxAttempts          int32         `json:"max_attempts"`
+	RetryBaseDelayMs     int32         `json:"retry_base_delay_ms"`
+	RetryableStatusCodes []int32       `json:"retryable_status_codes"`
+	Version              int32         `json:"version"`
+	Type                 string        `json:"type"`
+	DelaySeconds         int32         `json:"delay_seconds"`
+	ExpireSeconds        int32         `json:"expire_seconds"`
+	ChildWorkflow        string        `json:"child_workflow"`
+	ChildStartState      string        `json:"child_start_state"`
+}
+
+type StepDependency struct {
+	ID        int32        `json:"id"`
+	StepID    int32        `json:"step_id"`
+	State     string       `json:"state"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type Timer struct {
+	ID                 int32        `json:"id"`
+	WorkflowInstanceID string       `json:"workflow_instance_id"`
+	EventID            string       `json:"event_id"`
+	EventType          string       `json:"event_type"`
+	Source             string       `json:"source"`
+	State              string       `json:"state"`
+	FireAt             time.Time    `json:"fire_at"`
+	Status             string       `json:"status"`
+	CreatedAt          sql.NullTime `json:"created_at"`
+	FiredAt            sql.NullTime `json:"fired_at"`
+	Attempts           int32        `json:"attempts"`
 }
 
 type Workflow struct {
@@ -52,14 +204,18 @@ type Workflow struct {
 	Description string       `json:"description"`
 	CreatedAt   sql.NullTime `json:"created_at"`
 	UpdatedAt   sql.NullTime `json:"updated_at"`
+	Version     int32        `json:"version"`
 }
 
 type WorkflowInstance struct {
-	ID         string       `json:"id"`
-	WorkflowID int32        `json:"workflow_id"`
-	Status     string       `json:"status"`
-	CreatedAt  sql.NullTime `json:"created_at"`
-	UpdatedAt  sql.NullTime `json:"updated_at"`
+	ID               string         `json:"id"`
+	WorkflowID       int32          `json:"workflow_id"`
+	Status           string         `json:"status"`
+	CreatedAt        sql.NullTime   `json:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	WorkflowVersion  int32          `json:"workflow_version"`
+	ParentInstanceID sql.NullString `json:"parent_instance_id"`
+	ParentEventID    sql.NullString `json:"parent_event_id"`
 }
 
 type WorkflowInstanceStep struct {
@@ -73,4 +229,8 @@ type WorkflowInstanceStep struct {
 	EventMessage       sql.NullString `json:"event_message"`
 	StartedAt          sql.NullTime   `json:"started_at"`
 	CompletedAt        sql.NullTime   `json:"completed_at"`
+	Attempts           int32          `json:"attempts"`
+	NextRetryAt        sql.NullTime   `json:"next_retry_at"`
+	Guard              string         `json:"guard"`
+	GuardResult        sql.NullBool   `json:"guard_result"`
 }
@@ -8,8 +8,29 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
+const countSucceededStates = `-- name: CountSucceededStates :one
+SELECT COUNT(DISTINCT state) FROM process_logs
+WHERE workflow_instance_id = $1
+  AND status IN ('success', 'skipped')
+  AND state = ANY($2::varchar[])
+`
+
+type CountSucceededStatesParams struct {
+	WorkflowInstanceID string   `json:"workflow_instance_id"`
+	States             []string `json:"states"`
+}
+
+func (q *Queries) CountSucceededStates(ctx context.Context, arg CountSucceededStatesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSucceededStates, arg.WorkflowInstanceID, pq.Array(arg.States))
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createProcessLog = `-- name: CreateProcessLog :exec
 INSERT INTO process_logs (
     event_id,
@@ -41,3 +62,41 @@ func (q *Queries) CreateProcessLog(ctx context.Context, arg CreateProcessLogPara
 	)
 	return err
 }
+
+const findProcessLogsByInstanceID = `-- name: FindProcessLogsByInstanceID :many
+SELECT id, event_id, workflow_instance_id, state, status_code, status, event_message, created_at FROM process_logs
+WHERE workflow_instance_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) FindProcessLogsByInstanceID(ctx context.Context, workflowInstanceID string) ([]ProcessLog, error) {
+	rows, err := q.db.QueryContext(ctx, findProcessLogsByInstanceID, workflowInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProcessLog{}
+	for rows.Next() {
+		var i ProcessLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.WorkflowInstanceID,
+			&i.State,
+			&i.StatusCode,
+			&i.Status,
+			&i.EventMessage,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
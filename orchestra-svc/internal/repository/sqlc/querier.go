@@ -6,23 +6,101 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	AcquireOutboxLease(ctx context.Context, arg AcquireOutboxLeaseParams) (int64, error)
+	CancelInstanceApprovals(ctx context.Context, workflowInstanceID string) error
+	CancelInstanceStepRetries(ctx context.Context, workflowInstanceID string) error
+	CancelInstanceTimers(ctx context.Context, workflowInstanceID string) error
 	CheckIfInstanceStepExists(ctx context.Context, eventID string) (bool, error)
+	CheckIfInstanceStepExistsForStep(ctx context.Context, arg CheckIfInstanceStepExistsForStepParams) (bool, error)
+	ClaimInstanceStepRetry(ctx context.Context, eventID string) (int64, error)
+	ClaimProcessedEvent(ctx context.Context, arg ClaimProcessedEventParams) (int64, error)
+	ClaimRetryJobItems(ctx context.Context, arg ClaimRetryJobItemsParams) ([]RetryJobItem, error)
+	ClaimTimer(ctx context.Context, id int32) (int64, error)
+	CountApprovals(ctx context.Context, status sql.NullString) (int64, error)
+	CountDeadLetters(ctx context.Context, arg CountDeadLettersParams) (int64, error)
+	CountFailedInstanceSteps(ctx context.Context, workflowInstanceID string) (int64, error)
+	CountSucceededStates(ctx context.Context, arg CountSucceededStatesParams) (int64, error)
+	CountWorkflowInstances(ctx context.Context, arg CountWorkflowInstancesParams) (int64, error)
+	CreateApproval(ctx context.Context, arg CreateApprovalParams) error
+	CreateChildWorkflowInstance(ctx context.Context, arg CreateChildWorkflowInstanceParams) error
+	CreateDeadLetter(ctx context.Context, arg CreateDeadLetterParams) error
+	CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) error
+	CreatePayloadKey(ctx context.Context, arg CreatePayloadKeyParams) error
+	CreatePayloadMapping(ctx context.Context, arg CreatePayloadMappingParams) error
 	CreateProcessLog(ctx context.Context, arg CreateProcessLogParams) error
+	CreateProcessedEvent(ctx context.Context, arg CreateProcessedEventParams) error
+	CreateRetryAudit(ctx context.Context, arg CreateRetryAuditParams) error
+	CreateRetryJob(ctx context.Context, arg CreateRetryJobParams) error
+	CreateRetryJobItem(ctx context.Context, arg CreateRetryJobItemParams) error
+	CreateStateAction(ctx context.Context, arg CreateStateActionParams) error
+	CreateStep(ctx context.Context, arg CreateStepParams) (Step, error)
+	CreateStepDependency(ctx context.Context, arg CreateStepDependencyParams) error
+	CreateTimer(ctx context.Context, arg CreateTimerParams) error
 	CreateWorkflowInstance(ctx context.Context, arg CreateWorkflowInstanceParams) (WorkflowInstance, error)
 	CreateWorkflowInstanceStep(ctx context.Context, arg CreateWorkflowInstanceStepParams) (WorkflowInstanceStep, error)
+	DecideApproval(ctx context.Context, arg DecideApprovalParams) (int64, error)
+	DeleteExpiredInstancePayloads(ctx context.Context) (int64, error)
+	DeleteProcessedEvents(ctx context.Context, eventID string) error
+	ExpireInstancePayloads(ctx context.Context, arg ExpireInstancePayloadsParams) error
+	FindApproval(ctx context.Context, id int32) (Approval, error)
+	FindChildWorkflowInstanceIDs(ctx context.Context, parentInstanceID sql.NullString) ([]string, error)
+	FindCompensableInstanceSteps(ctx context.Context, workflowInstanceID string) ([]FindCompensableInstanceStepsRow, error)
+	FindDeadLettersByIDs(ctx context.Context, ids []int32) ([]DeadLetter, error)
+	FindDueInstanceStepRetries(ctx context.Context, limit int32) ([]FindDueInstanceStepRetriesRow, error)
+	FindDueTimers(ctx context.Context, limit int32) ([]Timer, error)
+	FindExpiredApprovals(ctx context.Context, limit int32) ([]Approval, error)
+	FindFailedInstanceSteps(ctx context.Context, arg FindFailedInstanceStepsParams) ([]FindFailedInstanceStepsRow, error)
+	FindInstancePayloads(ctx context.Context, workflowInstanceID string) ([]FindInstancePayloadsRow, error)
 	FindInstanceStepByEventID(ctx context.Context, eventID string) (WorkflowInstanceStep, error)
 	FindInstanceStepByID(ctx context.Context, workflowInstanceID string) ([]WorkflowInstanceStep, error)
+	FindInstanceStepRetryPolicy(ctx context.Context, eventID string) (FindInstanceStepRetryPolicyRow, error)
+	FindInstanceStepsWithStep(ctx context.Context, workflowInstanceID string) ([]FindInstanceStepsWithStepRow, error)
 	FindPayloadKeysByStepID(ctx context.Context, stepID int32) ([]string, error)
+	FindPayloadMappingsByStepID(ctx context.Context, stepID int32) ([]PayloadMapping, error)
+	FindPendingOutboxMessages(ctx context.Context, limit int32) ([]Outbox, error)
+	FindProcessLogsByInstanceID(ctx context.Context, workflowInstanceID string) ([]ProcessLog, error)
+	FindRetryAuditsByInstanceID(ctx context.Context, workflowInstanceID string) ([]RetryAudit, error)
+	FindRetryJob(ctx context.Context, id string) (RetryJob, error)
+	FindRetryJobItems(ctx context.Context, jobID string) ([]RetryJobItem, error)
+	FindRunningRetryJobs(ctx context.Context) ([]RetryJob, error)
+	FindStateActionsByType(ctx context.Context, arg FindStateActionsByTypeParams) ([]FindStateActionsByTypeRow, error)
+	FindStepByID(ctx context.Context, id int32) (Step, error)
+	FindStepDependenciesByStepID(ctx context.Context, stepID int32) ([]string, error)
+	FindStepStats(ctx context.Context, arg FindStepStatsParams) ([]FindStepStatsRow, error)
+	FindStepStatusCodeCounts(ctx context.Context, arg FindStepStatusCodeCountsParams) ([]FindStepStatusCodeCountsRow, error)
 	FindStepsByTypeAndState(ctx context.Context, arg FindStepsByTypeAndStateParams) ([]FindStepsByTypeAndStateRow, error)
+	FindStepsByWorkflowType(ctx context.Context, arg FindStepsByWorkflowTypeParams) ([]Step, error)
+	FindTimedOutInstanceSteps(ctx context.Context, limit int32) ([]FindTimedOutInstanceStepsRow, error)
 	FindWorkflowByType(ctx context.Context, type_ string) (Workflow, error)
 	FindWorkflowInstanceByID(ctx context.Context, id string) (WorkflowInstance, error)
 	FindWorkflowInstanceByTypeAndID(ctx context.Context, arg FindWorkflowInstanceByTypeAndIDParams) ([]FindWorkflowInstanceByTypeAndIDRow, error)
 	FindWorkflowInstanceStepsByEventIDAndInsID(ctx context.Context, arg FindWorkflowInstanceStepsByEventIDAndInsIDParams) (FindWorkflowInstanceStepsByEventIDAndInsIDRow, error)
+	FindWorkflowInstanceWithType(ctx context.Context, id string) (FindWorkflowInstanceWithTypeRow, error)
+	FinishRetryJobItem(ctx context.Context, arg FinishRetryJobItemParams) error
+	ListApprovals(ctx context.Context, arg ListApprovalsParams) ([]Approval, error)
+	ListDeadLetters(ctx context.Context, arg ListDeadLettersParams) ([]DeadLetter, error)
+	ListWorkflowInstances(ctx context.Context, arg ListWorkflowInstancesParams) ([]ListWorkflowInstancesRow, error)
+	ListWorkflows(ctx context.Context) ([]Workflow, error)
+	LockWorkflowInstance(ctx context.Context, id string) (string, error)
+	MarkDeadLetterReplayed(ctx context.Context, id int32) error
+	MarkOutboxMessageFailed(ctx context.Context, arg MarkOutboxMessageFailedParams) error
+	MarkOutboxMessageSent(ctx context.Context, id int32) error
+	RefreshRetryJobProgress(ctx context.Context, id string) error
+	RescheduleTimer(ctx context.Context, arg RescheduleTimerParams) error
+	ScheduleInstanceStepRetry(ctx context.Context, arg ScheduleInstanceStepRetryParams) error
+	TimeoutInstanceStep(ctx context.Context, eventID string) (int64, error)
+	TransitionWorkflowInstance(ctx context.Context, arg TransitionWorkflowInstanceParams) (int64, error)
+	UpdateStepCompensation(ctx context.Context, arg UpdateStepCompensationParams) error
+	UpdateTimerStatus(ctx context.Context, arg UpdateTimerStatusParams) error
 	UpdateWorkflowInstance(ctx context.Context, arg UpdateWorkflowInstanceParams) error
 	UpdateWorkflowInstanceStep(ctx context.Context, arg UpdateWorkflowInstanceStepParams) error
+	UpdateWorkflowInstanceStepStatus(ctx context.Context, arg UpdateWorkflowInstanceStepStatusParams) error
+	UpsertInstancePayload(ctx context.Context, arg UpsertInstancePayloadParams) error
+	UpsertWorkflow(ctx context.Context, arg UpsertWorkflowParams) (Workflow, error)
 }
 
 var _ Querier = (*Queries)(nil)
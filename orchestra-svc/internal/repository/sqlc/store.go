@@ -1,9 +1,18 @@
 package sqlc
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
 
 type Store interface {
 	Querier
+	ExecTx(ctx context.Context, fn func(Querier) error) error
+	// ExecTxStore runs fn inside a single database transaction like ExecTx,
+	// handing it a Store bound to that transaction so that code written
+	// against a Store joins it instead of opening transactions of its own.
+	ExecTxStore(ctx context.Context, fn func(Store) error) error
 }
 
 type SQLStore struct {
@@ -17,3 +26,66 @@ func NewStore(db *sql.DB) Store {
 		Queries: New(db),
 	}
 }
+
+// ExecTx runs fn inside a single database transaction, rolling back when fn
+// returns an error.
+func (store *SQLStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	return store.execTx(ctx, func(tx *sql.Tx) error {
+		return fn(New(tx))
+	})
+}
+
+func (store *SQLStore) ExecTxStore(ctx context.Context, fn func(Store) error) error {
+	return store.execTx(ctx, func(tx *sql.Tx) error {
+		return fn(&txStore{tx: tx, Queries: New(tx)})
+	})
+}
+
+func (store *SQLStore) execTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// txStore is a Store bound to a transaction that is already open. ExecTx
+// nests fn in a savepoint, so fn still rolls back on its own without ending
+// the transaction around it.
+type txStore struct {
+	tx *sql.Tx
+	*Queries
+}
+
+func (store *txStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	_, err := store.tx.ExecContext(ctx, "SAVEPOINT exec_tx")
+	if err != nil {
+		return err
+	}
+
+	err = fn(store.Queries)
+	if err != nil {
+		if _, rbErr := store.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT exec_tx"); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	_, err = store.tx.ExecContext(ctx, "RELEASE SAVEPOINT exec_tx")
+	return err
+}
+
+func (store *txStore) ExecTxStore(ctx context.Context, fn func(Store) error) error {
+	return store.ExecTx(ctx, func(Querier) error {
+		return fn(store)
+	})
+}
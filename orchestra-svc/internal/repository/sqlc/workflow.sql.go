@@ -7,6 +7,8 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const findPayloadKeysByStepID = `-- name: FindPayloadKeysByStepID :many
@@ -36,6 +38,43 @@ func (q *Queries) FindPayloadKeysByStepID(ctx context.Context, stepID int32) ([]
 	return items, nil
 }
 
+const findPayloadMappingsByStepID = `-- name: FindPayloadMappingsByStepID :many
+SELECT id, step_id, target, source, default_value, required, created_at FROM payload_mappings
+WHERE step_id = $1
+ORDER BY id
+`
+
+func (q *Queries) FindPayloadMappingsByStepID(ctx context.Context, stepID int32) ([]PayloadMapping, error) {
+	rows, err := q.db.QueryContext(ctx, findPayloadMappingsByStepID, stepID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PayloadMapping{}
+	for rows.Next() {
+		var i PayloadMapping
+		if err := rows.Scan(
+			&i.ID,
+			&i.StepID,
+			&i.Target,
+			&i.Source,
+			&i.DefaultValue,
+			&i.Required,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findStepsByTypeAndState = `-- name: FindStepsByTypeAndState :many
 SELECT DISTINCT
     sa.state,
@@ -43,33 +82,49 @@ SELECT DISTINCT
     s.service AS service,
     s.name AS step_name,
     s.description AS step_description,
-    s.topic AS step_topic
+    s.topic AS step_topic,
+    sa.guard,
+    s.type AS step_type,
+    s.delay_seconds,
+    s.expire_seconds,
+    s.child_workflow,
+    s.child_start_state,
+    s.emits
 FROM
     state_actions sa
         JOIN steps s ON sa.step_id = s.id
 WHERE
     sa.type = $1 AND
-    sa.state = $2
+    sa.version = $2 AND
+    sa.state = $3
 ORDER BY
     sa.state
 `
 
 type FindStepsByTypeAndStateParams struct {
-	Type  string `json:"type"`
-	State string `json:"state"`
+	Type    string `json:"type"`
+	Version int32  `json:"version"`
+	State   string `json:"state"`
 }
 
 type FindStepsByTypeAndStateRow struct {
-	State           string `json:"state"`
-	StepID          int32  `json:"step_id"`
-	Service         string `json:"service"`
-	StepName        string `json:"step_name"`
-	StepDescription string `json:"step_description"`
-	StepTopic       string `json:"step_topic"`
+	State           string   `json:"state"`
+	StepID          int32    `json:"step_id"`
+	Service         string   `json:"service"`
+	StepName        string   `json:"step_name"`
+	StepDescription string   `json:"step_description"`
+	StepTopic       string   `json:"step_topic"`
+	Guard           string   `json:"guard"`
+	StepType        string   `json:"step_type"`
+	DelaySeconds    int32    `json:"delay_seconds"`
+	ExpireSeconds   int32    `json:"expire_seconds"`
+	ChildWorkflow   string   `json:"child_workflow"`
+	ChildStartState string   `json:"child_start_state"`
+	Emits           []string `json:"emits"`
 }
 
 func (q *Queries) FindStepsByTypeAndState(ctx context.Context, arg FindStepsByTypeAndStateParams) ([]FindStepsByTypeAndStateRow, error) {
-	rows, err := q.db.QueryContext(ctx, findStepsByTypeAndState, arg.Type, arg.State)
+	rows, err := q.db.QueryContext(ctx, findStepsByTypeAndState, arg.Type, arg.Version, arg.State)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +139,13 @@ func (q *Queries) FindStepsByTypeAndState(ctx context.Context, arg FindStepsByTy
 			&i.StepName,
 			&i.StepDescription,
 			&i.StepTopic,
+			&i.Guard,
+			&i.StepType,
+			&i.DelaySeconds,
+			&i.ExpireSeconds,
+			&i.ChildWorkflow,
+			&i.ChildStartState,
+			pq.Array(&i.Emits),
 		); err != nil {
 			return nil, err
 		}
@@ -99,7 +161,7 @@ func (q *Queries) FindStepsByTypeAndState(ctx context.Context, arg FindStepsByTy
 }
 
 const findWorkflowByType = `-- name: FindWorkflowByType :one
-SELECT id, type, description, created_at, updated_at FROM workflows
+SELECT id, type, description, created_at, updated_at, version FROM workflows
 WHERE type = $1 LIMIT 1
 `
 
@@ -112,6 +174,7 @@ func (q *Queries) FindWorkflowByType(ctx context.Context, type_ string) (Workflo
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
 	)
 	return i, err
 }
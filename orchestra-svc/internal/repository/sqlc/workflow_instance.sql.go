@@ -8,8 +8,24 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
+const cancelInstanceStepRetries = `-- name: CancelInstanceStepRetries :exec
+UPDATE workflow_instance_steps
+SET
+    status = 'failed',
+    next_retry_at = NULL
+WHERE
+    workflow_instance_id = $1 AND status = 'retry_scheduled'
+`
+
+func (q *Queries) CancelInstanceStepRetries(ctx context.Context, workflowInstanceID string) error {
+	_, err := q.db.ExecContext(ctx, cancelInstanceStepRetries, workflowInstanceID)
+	return err
+}
+
 const checkIfInstanceStepExists = `-- name: CheckIfInstanceStepExists :one
 SELECT EXISTS(SELECT 1 FROM workflow_instance_steps WHERE event_id = $1) AS exists
 `
@@ -21,20 +37,132 @@ func (q *Queries) CheckIfInstanceStepExists(ctx context.Context, eventID string)
 	return exists, err
 }
 
+const checkIfInstanceStepExistsForStep = `-- name: CheckIfInstanceStepExistsForStep :one
+SELECT EXISTS(
+    SELECT 1 FROM workflow_instance_steps
+    WHERE workflow_instance_id = $1 AND step_id = $2
+) AS exists
+`
+
+type CheckIfInstanceStepExistsForStepParams struct {
+	WorkflowInstanceID string `json:"workflow_instance_id"`
+	StepID             int32  `json:"step_id"`
+}
+
+func (q *Queries) CheckIfInstanceStepExistsForStep(ctx context.Context, arg CheckIfInstanceStepExistsForStepParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, checkIfInstanceStepExistsForStep, arg.WorkflowInstanceID, arg.StepID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const claimInstanceStepRetry = `-- name: ClaimInstanceStepRetry :execrows
+UPDATE workflow_instance_steps
+SET
+    status = 'in_progress',
+    attempts = attempts + 1,
+    next_retry_at = NULL
+WHERE
+    event_id = $1 AND status = 'retry_scheduled'
+`
+
+func (q *Queries) ClaimInstanceStepRetry(ctx context.Context, eventID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimInstanceStepRetry, eventID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countFailedInstanceSteps = `-- name: CountFailedInstanceSteps :one
+SELECT COUNT(*) FROM workflow_instance_steps
+WHERE workflow_instance_id = $1
+  AND status IN ('error', 'failed', 'timeout')
+`
+
+func (q *Queries) CountFailedInstanceSteps(ctx context.Context, workflowInstanceID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFailedInstanceSteps, workflowInstanceID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWorkflowInstances = `-- name: CountWorkflowInstances :one
+SELECT COUNT(*)
+FROM workflow_instances wi
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE ($1::varchar IS NULL OR w.type = $1)
+  AND ($2::varchar IS NULL OR wi.status = $2)
+  AND ($3::timestamptz IS NULL OR wi.created_at >= $3)
+  AND ($4::timestamptz IS NULL OR wi.created_at < $4)
+`
+
+type CountWorkflowInstancesParams struct {
+	Type        sql.NullString `json:"type"`
+	Status      sql.NullString `json:"status"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+}
+
+func (q *Queries) CountWorkflowInstances(ctx context.Context, arg CountWorkflowInstancesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWorkflowInstances,
+		arg.Type,
+		arg.Status,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createChildWorkflowInstance = `-- name: CreateChildWorkflowInstance :exec
+INSERT INTO workflow_instances (id, workflow_id, workflow_version, status, parent_instance_id, parent_event_id)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateChildWorkflowInstanceParams struct {
+	ID               string         `json:"id"`
+	WorkflowID       int32          `json:"workflow_id"`
+	WorkflowVersion  int32          `json:"workflow_version"`
+	Status           string         `json:"status"`
+	ParentInstanceID sql.NullString `json:"parent_instance_id"`
+	ParentEventID    sql.NullString `json:"parent_event_id"`
+}
+
+func (q *Queries) CreateChildWorkflowInstance(ctx context.Context, arg CreateChildWorkflowInstanceParams) error {
+	_, err := q.db.ExecContext(ctx, createChildWorkflowInstance,
+		arg.ID,
+		arg.WorkflowID,
+		arg.WorkflowVersion,
+		arg.Status,
+		arg.ParentInstanceID,
+		arg.ParentEventID,
+	)
+	return err
+}
+
 const createWorkflowInstance = `-- name: CreateWorkflowInstance :one
-INSERT INTO workflow_instances (id, workflow_id, status)
+INSERT INTO workflow_instances (id, workflow_id, workflow_version, status)
 VALUES
-    ($1, $2, $3) RETURNING id, workflow_id, status, created_at, updated_at
+    ($1, $2, $3, $4) RETURNING id, workflow_id, status, created_at, updated_at, workflow_version, parent_instance_id, parent_event_id
 `
 
 type CreateWorkflowInstanceParams struct {
-	ID         string `json:"id"`
-	WorkflowID int32  `json:"workflow_id"`
-	Status     string `json:"status"`
+	ID              string `json:"id"`
+	WorkflowID      int32  `json:"workflow_id"`
+	WorkflowVersion int32  `json:"workflow_version"`
+	Status          string `json:"status"`
 }
 
 func (q *Queries) CreateWorkflowInstance(ctx context.Context, arg CreateWorkflowInstanceParams) (WorkflowInstance, error) {
-	row := q.db.QueryRowContext(ctx, createWorkflowInstance, arg.ID, arg.WorkflowID, arg.Status)
+	row := q.db.QueryRowContext(ctx, createWorkflowInstance,
+		arg.ID,
+		arg.WorkflowID,
+		arg.WorkflowVersion,
+		arg.Status,
+	)
 	var i WorkflowInstance
 	err := row.Scan(
 		&i.ID,
@@ -42,14 +170,17 @@ func (q *Queries) CreateWorkflowInstance(ctx context.Context, arg CreateWorkflow
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkflowVersion,
+		&i.ParentInstanceID,
+		&i.ParentEventID,
 	)
 	return i, err
 }
 
 const createWorkflowInstanceStep = `-- name: CreateWorkflowInstanceStep :one
-INSERT INTO workflow_instance_steps (workflow_instance_id,event_id, step_id, status, event_message, started_at, completed_at)
+INSERT INTO workflow_instance_steps (workflow_instance_id,event_id, step_id, status, event_message, started_at, completed_at, guard, guard_result, status_code, response)
 VALUES
-    ($1, $2, $3, $4, $5, $6, $7) RETURNING id, event_id, status_code, response, workflow_instance_id, step_id, status, event_message, started_at, completed_at
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, event_id, status_code, response, workflow_instance_id, step_id, status, event_message, started_at, completed_at, attempts, next_retry_at, guard, guard_result
 `
 
 type CreateWorkflowInstanceStepParams struct {
@@ -60,6 +191,10 @@ type CreateWorkflowInstanceStepParams struct {
 	EventMessage       sql.NullString `json:"event_message"`
 	StartedAt          sql.NullTime   `json:"started_at"`
 	CompletedAt        sql.NullTime   `json:"completed_at"`
+	Guard              string         `json:"guard"`
+	GuardResult        sql.NullBool   `json:"guard_result"`
+	StatusCode         sql.NullInt32  `json:"status_code"`
+	Response           sql.NullString `json:"response"`
 }
 
 func (q *Queries) CreateWorkflowInstanceStep(ctx context.Context, arg CreateWorkflowInstanceStepParams) (WorkflowInstanceStep, error) {
@@ -71,6 +206,10 @@ func (q *Queries) CreateWorkflowInstanceStep(ctx context.Context, arg CreateWork
 		arg.EventMessage,
 		arg.StartedAt,
 		arg.CompletedAt,
+		arg.Guard,
+		arg.GuardResult,
+		arg.StatusCode,
+		arg.Response,
 	)
 	var i WorkflowInstanceStep
 	err := row.Scan(
@@ -84,12 +223,208 @@ func (q *Queries) CreateWorkflowInstanceStep(ctx context.Context, arg CreateWork
 		&i.EventMessage,
 		&i.StartedAt,
 		&i.CompletedAt,
+		&i.Attempts,
+		&i.NextRetryAt,
+		&i.Guard,
+		&i.GuardResult,
 	)
 	return i, err
 }
 
+const findChildWorkflowInstanceIDs = `-- name: FindChildWorkflowInstanceIDs :many
+SELECT id FROM workflow_instances
+WHERE parent_instance_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) FindChildWorkflowInstanceIDs(ctx context.Context, parentInstanceID sql.NullString) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, findChildWorkflowInstanceIDs, parentInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findCompensableInstanceSteps = `-- name: FindCompensableInstanceSteps :many
+SELECT
+    wis.event_id,
+    wis.step_id,
+    wis.event_message,
+    c.id AS compensation_step_id,
+    c.name AS compensation_step_name,
+    c.service AS compensation_service,
+    c.topic AS compensation_topic
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN steps c ON s.compensation_step_id = c.id
+WHERE wis.workflow_instance_id = $1 AND wis.status = 'success'
+ORDER BY wis.started_at DESC, wis.id DESC
+`
+
+type FindCompensableInstanceStepsRow struct {
+	EventID              string         `json:"event_id"`
+	StepID               int32          `json:"step_id"`
+	EventMessage         sql.NullString `json:"event_message"`
+	CompensationStepID   int32          `json:"compensation_step_id"`
+	CompensationStepName string         `json:"compensation_step_name"`
+	CompensationService  string         `json:"compensation_service"`
+	CompensationTopic    string         `json:"compensation_topic"`
+}
+
+func (q *Queries) FindCompensableInstanceSteps(ctx context.Context, workflowInstanceID string) ([]FindCompensableInstanceStepsRow, error) {
+	rows, err := q.db.QueryContext(ctx, findCompensableInstanceSteps, workflowInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindCompensableInstanceStepsRow{}
+	for rows.Next() {
+		var i FindCompensableInstanceStepsRow
+		if err := rows.Scan(
+			&i.EventID,
+			&i.StepID,
+			&i.EventMessage,
+			&i.CompensationStepID,
+			&i.CompensationStepName,
+			&i.CompensationService,
+			&i.CompensationTopic,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findDueInstanceStepRetries = `-- name: FindDueInstanceStepRetries :many
+SELECT event_id, workflow_instance_id
+FROM workflow_instance_steps
+WHERE status = 'retry_scheduled' AND next_retry_at <= CURRENT_TIMESTAMP
+ORDER BY next_retry_at
+LIMIT $1
+`
+
+type FindDueInstanceStepRetriesRow struct {
+	EventID            string `json:"event_id"`
+	WorkflowInstanceID string `json:"workflow_instance_id"`
+}
+
+func (q *Queries) FindDueInstanceStepRetries(ctx context.Context, limit int32) ([]FindDueInstanceStepRetriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, findDueInstanceStepRetries, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindDueInstanceStepRetriesRow{}
+	for rows.Next() {
+		var i FindDueInstanceStepRetriesRow
+		if err := rows.Scan(&i.EventID, &i.WorkflowInstanceID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findFailedInstanceSteps = `-- name: FindFailedInstanceSteps :many
+SELECT wis.event_id, wis.workflow_instance_id
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN workflow_instances wi ON wis.workflow_instance_id = wi.id
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE wis.status IN ('error', 'timeout')
+  AND wi.status = 'in_progress'
+  AND ($1::varchar IS NULL OR wis.status = $1)
+  AND ($2::varchar IS NULL OR w.type = $2)
+  AND ($3::varchar IS NULL OR s.name = $3)
+  AND ($4::varchar IS NULL OR s.service = $4)
+  AND ($5::int IS NULL OR wis.status_code >= $5)
+  AND ($6::int IS NULL OR wis.status_code <= $6)
+  AND ($7::timestamptz IS NULL OR wis.started_at >= $7)
+  AND ($8::timestamptz IS NULL OR wis.started_at < $8)
+ORDER BY wis.started_at, wis.id
+LIMIT $9
+`
+
+type FindFailedInstanceStepsParams struct {
+	Status        sql.NullString `json:"status"`
+	Type          sql.NullString `json:"type"`
+	Step          sql.NullString `json:"step"`
+	Service       sql.NullString `json:"service"`
+	StatusCodeMin sql.NullInt32  `json:"status_code_min"`
+	StatusCodeMax sql.NullInt32  `json:"status_code_max"`
+	StartedFrom   sql.NullTime   `json:"started_from"`
+	StartedTo     sql.NullTime   `json:"started_to"`
+	Limit         int32          `json:"limit"`
+}
+
+type FindFailedInstanceStepsRow struct {
+	EventID            string `json:"event_id"`
+	WorkflowInstanceID string `json:"workflow_instance_id"`
+}
+
+func (q *Queries) FindFailedInstanceSteps(ctx context.Context, arg FindFailedInstanceStepsParams) ([]FindFailedInstanceStepsRow, error) {
+	rows, err := q.db.QueryContext(ctx, findFailedInstanceSteps,
+		arg.Status,
+		arg.Type,
+		arg.Step,
+		arg.Service,
+		arg.StatusCodeMin,
+		arg.StatusCodeMax,
+		arg.StartedFrom,
+		arg.StartedTo,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindFailedInstanceStepsRow{}
+	for rows.Next() {
+		var i FindFailedInstanceStepsRow
+		if err := rows.Scan(&i.EventID, &i.WorkflowInstanceID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findInstanceStepByEventID = `-- name: FindInstanceStepByEventID :one
-SELECT id, event_id, status_code, response, workflow_instance_id, step_id, status, event_message, started_at, completed_at FROM workflow_instance_steps
+SELECT id, event_id, status_code, response, workflow_instance_id, step_id, status, event_message, started_at, completed_at, attempts, next_retry_at, guard, guard_result FROM workflow_instance_steps
 WHERE event_id = $1 LIMIT 1
 `
 
@@ -107,12 +442,16 @@ func (q *Queries) FindInstanceStepByEventID(ctx context.Context, eventID string)
 		&i.EventMessage,
 		&i.StartedAt,
 		&i.CompletedAt,
+		&i.Attempts,
+		&i.NextRetryAt,
+		&i.Guard,
+		&i.GuardResult,
 	)
 	return i, err
 }
 
 const findInstanceStepByID = `-- name: FindInstanceStepByID :many
-SELECT id, event_id, status_code, response, workflow_instance_id, step_id, status, event_message, started_at, completed_at FROM workflow_instance_steps
+SELECT id, event_id, status_code, response, workflow_instance_id, step_id, status, event_message, started_at, completed_at, attempts, next_retry_at, guard, guard_result FROM workflow_instance_steps
 WHERE workflow_instance_id = $1
 `
 
@@ -136,6 +475,189 @@ func (q *Queries) FindInstanceStepByID(ctx context.Context, workflowInstanceID s
 			&i.EventMessage,
 			&i.StartedAt,
 			&i.CompletedAt,
+			&i.Attempts,
+			&i.NextRetryAt,
+			&i.Guard,
+			&i.GuardResult,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findInstanceStepRetryPolicy = `-- name: FindInstanceStepRetryPolicy :one
+SELECT
+    wis.status,
+    wis.attempts,
+    s.max_attempts,
+    s.retry_base_delay_ms,
+    s.retryable_status_codes,
+    wi.status AS instance_status
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN workflow_instances wi ON wis.workflow_instance_id = wi.id
+WHERE wis.event_id = $1
+`
+
+type FindInstanceStepRetryPolicyRow struct {
+	Status               string  `json:"status"`
+	Attempts             int32   `json:"attempts"`
+	MaxAttempts          int32   `json:"max_attempts"`
+	RetryBaseDelayMs     int32   `json:"retry_base_delay_ms"`
+	RetryableStatusCodes []int32 `json:"retryable_status_codes"`
+	InstanceStatus       string  `json:"instance_status"`
+}
+
+func (q *Queries) FindInstanceStepRetryPolicy(ctx context.Context, eventID string) (FindInstanceStepRetryPolicyRow, error) {
+	row := q.db.QueryRowContext(ctx, findInstanceStepRetryPolicy, eventID)
+	var i FindInstanceStepRetryPolicyRow
+	err := row.Scan(
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.RetryBaseDelayMs,
+		pq.Array(&i.RetryableStatusCodes),
+		&i.InstanceStatus,
+	)
+	return i, err
+}
+
+const findInstanceStepsWithStep = `-- name: FindInstanceStepsWithStep :many
+SELECT
+    wis.id,
+    wis.event_id,
+    wis.step_id,
+    s.name AS step_name,
+    s.service,
+    wis.status,
+    wis.status_code,
+    wis.response,
+    wis.attempts,
+    wis.next_retry_at,
+    wis.started_at,
+    wis.completed_at,
+    wis.guard,
+    wis.guard_result
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+WHERE wis.workflow_instance_id = $1
+ORDER BY wis.started_at, wis.id
+`
+
+type FindInstanceStepsWithStepRow struct {
+	ID          int32          `json:"id"`
+	EventID     string         `json:"event_id"`
+	StepID      int32          `json:"step_id"`
+	StepName    string         `json:"step_name"`
+	Service     string         `json:"service"`
+	Status      string         `json:"status"`
+	StatusCode  sql.NullInt32  `json:"status_code"`
+	Response    sql.NullString `json:"response"`
+	Attempts    int32          `json:"attempts"`
+	NextRetryAt sql.NullTime   `json:"next_retry_at"`
+	StartedAt   sql.NullTime   `json:"started_at"`
+	CompletedAt sql.NullTime   `json:"completed_at"`
+	Guard       string         `json:"guard"`
+	GuardResult sql.NullBool   `json:"guard_result"`
+}
+
+func (q *Queries) FindInstanceStepsWithStep(ctx context.Context, workflowInstanceID string) ([]FindInstanceStepsWithStepRow, error) {
+	rows, err := q.db.QueryContext(ctx, findInstanceStepsWithStep, workflowInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindInstanceStepsWithStepRow{}
+	for rows.Next() {
+		var i FindInstanceStepsWithStepRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.StepID,
+			&i.StepName,
+			&i.Service,
+			&i.Status,
+			&i.StatusCode,
+			&i.Response,
+			&i.Attempts,
+			&i.NextRetryAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.Guard,
+			&i.GuardResult,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findTimedOutInstanceSteps = `-- name: FindTimedOutInstanceSteps :many
+SELECT
+    wis.event_id,
+    wis.workflow_instance_id,
+    wis.step_id,
+    wis.started_at,
+    wis.event_message,
+    s.name AS step_name,
+    s.timeout_action,
+    w.type AS workflow_type
+FROM workflow_instance_steps wis
+JOIN steps s ON wis.step_id = s.id
+JOIN workflow_instances wi ON wis.workflow_instance_id = wi.id
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE wis.status = 'in_progress'
+  AND s.type = 'service'
+  AND s.timeout_seconds > 0
+  AND wis.started_at + make_interval(secs => s.timeout_seconds) < CURRENT_TIMESTAMP
+ORDER BY wis.started_at
+LIMIT $1
+`
+
+type FindTimedOutInstanceStepsRow struct {
+	EventID            string         `json:"event_id"`
+	WorkflowInstanceID string         `json:"workflow_instance_id"`
+	StepID             int32          `json:"step_id"`
+	StartedAt          sql.NullTime   `json:"started_at"`
+	EventMessage       sql.NullString `json:"event_message"`
+	StepName           string         `json:"step_name"`
+	TimeoutAction      string         `json:"timeout_action"`
+	WorkflowType       string         `json:"workflow_type"`
+}
+
+func (q *Queries) FindTimedOutInstanceSteps(ctx context.Context, limit int32) ([]FindTimedOutInstanceStepsRow, error) {
+	rows, err := q.db.QueryContext(ctx, findTimedOutInstanceSteps, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindTimedOutInstanceStepsRow{}
+	for rows.Next() {
+		var i FindTimedOutInstanceStepsRow
+		if err := rows.Scan(
+			&i.EventID,
+			&i.WorkflowInstanceID,
+			&i.StepID,
+			&i.StartedAt,
+			&i.EventMessage,
+			&i.StepName,
+			&i.TimeoutAction,
+			&i.WorkflowType,
 		); err != nil {
 			return nil, err
 		}
@@ -151,7 +673,7 @@ func (q *Queries) FindInstanceStepByID(ctx context.Context, workflowInstanceID s
 }
 
 const findWorkflowInstanceByID = `-- name: FindWorkflowInstanceByID :one
-SELECT id, workflow_id, status, created_at, updated_at FROM workflow_instances
+SELECT id, workflow_id, status, created_at, updated_at, workflow_version, parent_instance_id, parent_event_id FROM workflow_instances
 WHERE id = $1 LIMIT 1
 `
 
@@ -164,6 +686,9 @@ func (q *Queries) FindWorkflowInstanceByID(ctx context.Context, id string) (Work
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkflowVersion,
+		&i.ParentInstanceID,
+		&i.ParentEventID,
 	)
 	return i, err
 }
@@ -175,7 +700,8 @@ SELECT
     wi.id as instance_id,
     wi.status as instance_status,
     wis.status as instance_step_status,
-    wis.step_id as step_id
+    wis.step_id as step_id,
+    EXISTS(SELECT 1 FROM steps s WHERE s.compensation_step_id = wis.step_id) AS is_compensation
 FROM workflows w
 JOIN workflow_instances wi on w.id = wi.workflow_id
 JOIN workflow_instance_steps wis ON wi.id = wis.workflow_instance_id
@@ -194,6 +720,7 @@ type FindWorkflowInstanceByTypeAndIDRow struct {
 	InstanceStatus     string `json:"instance_status"`
 	InstanceStepStatus string `json:"instance_step_status"`
 	StepID             int32  `json:"step_id"`
+	IsCompensation     bool   `json:"is_compensation"`
 }
 
 func (q *Queries) FindWorkflowInstanceByTypeAndID(ctx context.Context, arg FindWorkflowInstanceByTypeAndIDParams) ([]FindWorkflowInstanceByTypeAndIDRow, error) {
@@ -212,6 +739,7 @@ func (q *Queries) FindWorkflowInstanceByTypeAndID(ctx context.Context, arg FindW
 			&i.InstanceStatus,
 			&i.InstanceStepStatus,
 			&i.StepID,
+			&i.IsCompensation,
 		); err != nil {
 			return nil, err
 		}
@@ -228,7 +756,7 @@ func (q *Queries) FindWorkflowInstanceByTypeAndID(ctx context.Context, arg FindW
 
 const findWorkflowInstanceStepsByEventIDAndInsID = `-- name: FindWorkflowInstanceStepsByEventIDAndInsID :one
 SELECT wis.event_id, wis.workflow_instance_id, wis.status_code, wis.status, wis.event_message,
-       s.topic
+       s.topic, s.service, s.emits
 FROM workflow_instance_steps wis
          JOIN steps s on wis.step_id = s.id
 WHERE event_id = $1 AND workflow_instance_id = $2
@@ -246,6 +774,8 @@ type FindWorkflowInstanceStepsByEventIDAndInsIDRow struct {
 	Status             string         `json:"status"`
 	EventMessage       sql.NullString `json:"event_message"`
 	Topic              string         `json:"topic"`
+	Service            string         `json:"service"`
+	Emits              []string       `json:"emits"`
 }
 
 func (q *Queries) FindWorkflowInstanceStepsByEventIDAndInsID(ctx context.Context, arg FindWorkflowInstanceStepsByEventIDAndInsIDParams) (FindWorkflowInstanceStepsByEventIDAndInsIDRow, error) {
@@ -258,10 +788,193 @@ func (q *Queries) FindWorkflowInstanceStepsByEventIDAndInsID(ctx context.Context
 		&i.Status,
 		&i.EventMessage,
 		&i.Topic,
+		&i.Service,
+		pq.Array(&i.Emits),
 	)
 	return i, err
 }
 
+const findWorkflowInstanceWithType = `-- name: FindWorkflowInstanceWithType :one
+SELECT wi.id, w.type, wi.workflow_version, wi.status, wi.created_at, wi.updated_at, wi.parent_instance_id, wi.parent_event_id
+FROM workflow_instances wi
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE wi.id = $1
+`
+
+type FindWorkflowInstanceWithTypeRow struct {
+	ID               string         `json:"id"`
+	Type             string         `json:"type"`
+	WorkflowVersion  int32          `json:"workflow_version"`
+	Status           string         `json:"status"`
+	CreatedAt        sql.NullTime   `json:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	ParentInstanceID sql.NullString `json:"parent_instance_id"`
+	ParentEventID    sql.NullString `json:"parent_event_id"`
+}
+
+func (q *Queries) FindWorkflowInstanceWithType(ctx context.Context, id string) (FindWorkflowInstanceWithTypeRow, error) {
+	row := q.db.QueryRowContext(ctx, findWorkflowInstanceWithType, id)
+	var i FindWorkflowInstanceWithTypeRow
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.WorkflowVersion,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentInstanceID,
+		&i.ParentEventID,
+	)
+	return i, err
+}
+
+const listWorkflowInstances = `-- name: ListWorkflowInstances :many
+SELECT wi.id, w.type, wi.workflow_version, wi.status, wi.created_at, wi.updated_at
+FROM workflow_instances wi
+JOIN workflows w ON wi.workflow_id = w.id
+WHERE ($1::varchar IS NULL OR w.type = $1)
+  AND ($2::varchar IS NULL OR wi.status = $2)
+  AND ($3::timestamptz IS NULL OR wi.created_at >= $3)
+  AND ($4::timestamptz IS NULL OR wi.created_at < $4)
+ORDER BY wi.created_at DESC, wi.id
+LIMIT $5 OFFSET $6
+`
+
+type ListWorkflowInstancesParams struct {
+	Type        sql.NullString `json:"type"`
+	Status      sql.NullString `json:"status"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type ListWorkflowInstancesRow struct {
+	ID              string       `json:"id"`
+	Type            string       `json:"type"`
+	WorkflowVersion int32        `json:"workflow_version"`
+	Status          string       `json:"status"`
+	CreatedAt       sql.NullTime `json:"created_at"`
+	UpdatedAt       sql.NullTime `json:"updated_at"`
+}
+
+func (q *Queries) ListWorkflowInstances(ctx context.Context, arg ListWorkflowInstancesParams) ([]ListWorkflowInstancesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkflowInstances,
+		arg.Type,
+		arg.Status,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWorkflowInstancesRow{}
+	for rows.Next() {
+		var i ListWorkflowInstancesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.WorkflowVersion,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockWorkflowInstance = `-- name: LockWorkflowInstance :one
+SELECT id FROM workflow_instances
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockWorkflowInstance(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRowContext(ctx, lockWorkflowInstance, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const scheduleInstanceStepRetry = `-- name: ScheduleInstanceStepRetry :exec
+UPDATE workflow_instance_steps
+SET
+    status = 'retry_scheduled',
+    status_code = $1,
+    response = $2,
+    next_retry_at = $3
+WHERE
+    event_id = $4
+`
+
+type ScheduleInstanceStepRetryParams struct {
+	StatusCode  sql.NullInt32  `json:"status_code"`
+	Response    sql.NullString `json:"response"`
+	NextRetryAt sql.NullTime   `json:"next_retry_at"`
+	EventID     string         `json:"event_id"`
+}
+
+func (q *Queries) ScheduleInstanceStepRetry(ctx context.Context, arg ScheduleInstanceStepRetryParams) error {
+	_, err := q.db.ExecContext(ctx, scheduleInstanceStepRetry,
+		arg.StatusCode,
+		arg.Response,
+		arg.NextRetryAt,
+		arg.EventID,
+	)
+	return err
+}
+
+const timeoutInstanceStep = `-- name: TimeoutInstanceStep :execrows
+UPDATE workflow_instance_steps
+SET
+    status = 'timeout',
+    completed_at = CURRENT_TIMESTAMP
+WHERE
+    event_id = $1 AND status = 'in_progress'
+`
+
+func (q *Queries) TimeoutInstanceStep(ctx context.Context, eventID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, timeoutInstanceStep, eventID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const transitionWorkflowInstance = `-- name: TransitionWorkflowInstance :execrows
+UPDATE workflow_instances
+SET
+    status = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2 AND status = ANY($3::varchar[])
+`
+
+type TransitionWorkflowInstanceParams struct {
+	Status       string   `json:"status"`
+	ID           string   `json:"id"`
+	FromStatuses []string `json:"from_statuses"`
+}
+
+func (q *Queries) TransitionWorkflowInstance(ctx context.Context, arg TransitionWorkflowInstanceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, transitionWorkflowInstance, arg.Status, arg.ID, pq.Array(arg.FromStatuses))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateWorkflowInstance = `-- name: UpdateWorkflowInstance :exec
 UPDATE workflow_instances
 SET
@@ -316,3 +1029,21 @@ func (q *Queries) UpdateWorkflowInstanceStep(ctx context.Context, arg UpdateWork
 	)
 	return err
 }
+
+const updateWorkflowInstanceStepStatus = `-- name: UpdateWorkflowInstanceStepStatus :exec
+UPDATE workflow_instance_steps
+SET
+    status = $1
+WHERE
+    event_id = $2
+`
+
+type UpdateWorkflowInstanceStepStatusParams struct {
+	Status  string `json:"status"`
+	EventID string `json:"event_id"`
+}
+
+func (q *Queries) UpdateWorkflowInstanceStepStatus(ctx context.Context, arg UpdateWorkflowInstanceStepStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkflowInstanceStepStatus, arg.Status, arg.EventID)
+	return err
+}
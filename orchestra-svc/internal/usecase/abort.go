@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+)
+
+var ErrInstanceNotAbortable = errors.New("workflow instance is not running")
+
+// AbortInstance stops a running instance and the child instances it started.
+// Forward events are ignored from now on, the steps that already succeeded
+// are compensated and once every step has replied the instance emits
+// workflow_aborted.
+func (o *OrchestraUsecase) AbortInstance(ctx context.Context, id string) (*dto.InstanceAbortResponse, error) {
+	unlock := o.instances.Lock(id)
+	defer unlock()
+
+	found, err := o.queries.FindWorkflowInstanceWithType(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInstanceNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("find instance: %w", err)
+	}
+
+	err = o.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		aborted, err := q.TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+			Status:       dto.ABORTING.String(),
+			ID:           id,
+			FromStatuses: []string{dto.IN_PROGRESS.String(), dto.COMPENSATING.String(), dto.AWAITING_APPROVAL.String()},
+		})
+		if err != nil {
+			return fmt.Errorf("abort instance: %w", err)
+		}
+
+		if aborted == 0 {
+			return ErrInstanceNotAbortable
+		}
+
+		err = q.CancelInstanceStepRetries(ctx, id)
+		if err != nil {
+			return fmt.Errorf("cancel retries: %w", err)
+		}
+
+		err = q.CancelInstanceApprovals(ctx, id)
+		if err != nil {
+			return fmt.Errorf("cancel approvals: %w", err)
+		}
+
+		err = q.CancelInstanceTimers(ctx, id)
+		if err != nil {
+			return fmt.Errorf("cancel timers: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	abortEvent := o.createAbortEvent(found.Type, id)
+
+	err = o.logDB(ctx, abortEvent)
+	if err != nil {
+		log.Println("Error logging to db: ", err)
+	}
+
+	instance := sqlc.WorkflowInstance{
+		ID:               id,
+		Status:           dto.ABORTING.String(),
+		WorkflowVersion:  found.WorkflowVersion,
+		CreatedAt:        found.CreatedAt,
+		ParentInstanceID: found.ParentInstanceID,
+		ParentEventID:    found.ParentEventID,
+	}
+
+	cachePayload, err := o.cache.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("load payload: %w", err)
+	}
+
+	err = o.processAborted(ctx, abortEvent, instance, cachePayload)
+	if err != nil {
+		return nil, err
+	}
+
+	o.abortChildren(ctx, id)
+
+	status := dto.ABORTING.String()
+	current, err := o.queries.FindWorkflowInstanceByID(ctx, id)
+	if err == nil {
+		status = current.Status
+	}
+
+	return &dto.InstanceAbortResponse{
+		ID:     id,
+		Status: status,
+	}, nil
+}
+
+// processAborted handles an event for an instance that is being aborted. The
+// reply only rolls back what its step completed, no forward step is emitted.
+func (o *OrchestraUsecase) processAborted(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, cachePayload map[string]any) error {
+	if instance.Status == dto.ABORTED.String() {
+		log.Printf("Ignoring %s for aborted instance %s", eventMsg.State, instance.ID)
+		return nil
+	}
+
+	abortEvent := o.createAbortEvent(eventMsg.EventType, instance.ID)
+
+	err := o.compensate(ctx, abortEvent, instance)
+	if err != nil {
+		log.Println("Error compensating instance: ", err)
+	}
+
+	return o.finishAbort(ctx, abortEvent, instance, cachePayload)
+}
+
+// finishAbort marks the instance aborted once no step is waiting for a reply
+// anymore and emits the steps the definition maps to workflow_aborted.
+func (o *OrchestraUsecase) finishAbort(ctx context.Context, abortEvent event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, cachePayload map[string]any) error {
+	wfiSteps, err := o.queries.FindWorkflowInstanceByTypeAndID(ctx, sqlc.FindWorkflowInstanceByTypeAndIDParams{
+		Type:               abortEvent.EventType,
+		WorkflowInstanceID: instance.ID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("find workflow instance by type and id: %w", err)
+	}
+
+	for _, value := range wfiSteps {
+		if value.InstanceStepStatus == dto.IN_PROGRESS.String() || value.InstanceStepStatus == dto.RETRY_SCHEDULED.String() {
+			log.Printf("Step %d still in progress", value.StepID)
+			return nil
+		}
+	}
+
+	// only the reply that moves the instance out of aborting emits the final state
+	var finished int64
+	err = o.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		finished, err = q.TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+			Status:       dto.ABORTED.String(),
+			ID:           instance.ID,
+			FromStatuses: []string{dto.ABORTING.String()},
+		})
+		if err != nil {
+			return err
+		}
+
+		if finished == 0 {
+			return nil
+		}
+
+		err = o.publishAborted(ctx, q, abortEvent.EventType, instance)
+		if err != nil {
+			return err
+		}
+
+		// an aborted child fails the workflow step of its parent
+		if !instance.ParentInstanceID.Valid {
+			return nil
+		}
+
+		return o.resumeParent(ctx, q, instance, dto.ABORTED.String(), cachePayload)
+	})
+
+	if err != nil {
+		return fmt.Errorf("update workflow instance: %w", err)
+	}
+
+	if finished == 0 {
+		return nil
+	}
+
+	steps, err := o.queries.FindStepsByTypeAndState(ctx, sqlc.FindStepsByTypeAndStateParams{
+		Type:    abortEvent.EventType,
+		Version: instance.WorkflowVersion,
+		State:   abortEvent.State,
+	})
+
+	if err != nil {
+		return fmt.Errorf("find steps: %w", err)
+	}
+
+	for _, step := range steps {
+		err := o.processStep(ctx, abortEvent, instance, step, cachePayload)
+		if err != nil {
+			log.Printf("Error processing step %d: %v", step.StepID, err)
+			continue
+		}
+	}
+
+	err = o.cache.Expire(ctx, instance.ID)
+	if err != nil {
+		log.Println("Error expire cache payload: ", err)
+	}
+
+	return nil
+}
+
+func (o *OrchestraUsecase) createAbortEvent(eventType, instanceID string) event.GlobalEvent[any, any] {
+	gevent := event.NewGlobalEvent[any, any]("abort", dto.ABORTED.String(), event.BasePayload[any, any]{})
+
+	gevent.EventType = eventType
+	gevent.State = dto.WorkflowAborted
+	gevent.InstanceID = instanceID
+
+	return gevent
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrchestraUsecase_AbortInstance_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{}, sql.ErrNoRows)
+
+	_, err := uc.AbortInstance(ctx, "instance-001")
+	assert.ErrorIs(t, err, ErrInstanceNotFound)
+}
+
+func TestOrchestraUsecase_AbortInstance_NotRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{
+		ID:     "instance-001",
+		Type:   "order_process",
+		Status: "completed",
+	}, nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, gomock.Any()).Return(int64(0), nil)
+
+	_, err := uc.AbortInstance(ctx, "instance-001")
+	assert.ErrorIs(t, err, ErrInstanceNotAbortable)
+}
+
+func TestOrchestraUsecase_AbortInstance_CompensatesSucceededSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{
+		ID:              "instance-001",
+		Type:            "order_process",
+		WorkflowVersion: 2,
+		Status:          "in_progress",
+	}, nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+		Status:       "aborting",
+		ID:           "instance-001",
+		FromStatuses: []string{"in_progress", "compensating", "awaiting_approval"},
+	}).Return(int64(1), nil)
+	store.EXPECT().CancelInstanceStepRetries(ctx, "instance-001").Return(nil)
+	store.EXPECT().CancelInstanceApprovals(ctx, "instance-001").Return(nil)
+	store.EXPECT().CancelInstanceTimers(ctx, "instance-001").Return(nil)
+	store.EXPECT().CreateProcessLog(ctx, gomock.Any()).Return(nil)
+	store.EXPECT().FindCompensableInstanceSteps(ctx, "instance-001").Return([]sqlc.FindCompensableInstanceStepsRow{
+		{EventID: "event-001", StepID: 2, CompensationStepID: 5, CompensationTopic: "product-topic"},
+	}, nil)
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+			assert.Equal(t, int32(5), arg.StepID)
+			assert.Contains(t, arg.EventMessage.String, `"state":"workflow_aborted"`)
+			return sqlc.WorkflowInstanceStep{}, nil
+		})
+	store.EXPECT().UpdateWorkflowInstanceStepStatus(ctx, sqlc.UpdateWorkflowInstanceStepStatusParams{
+		Status:  "compensated",
+		EventID: "event-001",
+	}).Return(nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).Return(nil)
+
+	// the compensation has not replied yet, so the abort is not finished
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "compensated", StepID: 2},
+		{InstanceStepStatus: "in_progress", StepID: 5},
+	}, nil)
+	store.EXPECT().FindChildWorkflowInstanceIDs(ctx, sql.NullString{String: "instance-001", Valid: true}).Return([]string{}, nil)
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "aborting"}, nil)
+
+	response, err := uc.AbortInstance(ctx, "instance-001")
+	assert.NoError(t, err)
+	assert.Equal(t, "aborting", response.Status)
+}
+
+func TestOrchestraUsecase_finishAbort_EmitsAbortedState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	instance := sqlc.WorkflowInstance{ID: "instance-001", Status: "aborting", WorkflowVersion: 2}
+	abortEvent := uc.createAbortEvent("order_process", "instance-001")
+
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "compensated", StepID: 2},
+		{InstanceStepStatus: "success", StepID: 5},
+	}, nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+		Status:       "aborted",
+		ID:           "instance-001",
+		FromStatuses: []string{"aborting"},
+	}).Return(int64(1), nil)
+	store.EXPECT().FindStepsByTypeAndState(ctx, sqlc.FindStepsByTypeAndStateParams{
+		Type:    "order_process",
+		Version: 2,
+		State:   dto.WorkflowAborted,
+	}).Return([]sqlc.FindStepsByTypeAndStateRow{{StepID: 6, StepTopic: "order-topic"}}, nil)
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(6)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(6)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(6)).Return([]sqlc.PayloadMapping{}, nil)
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Return(sqlc.WorkflowInstanceStep{}, nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "order-topic", arg.Topic)
+			return nil
+		})
+
+	err := uc.finishAbort(ctx, abortEvent, instance, map[string]any{})
+	assert.NoError(t, err)
+}
+
+func TestOrchestraUsecase_processAborted_IgnoresAbortedInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	instance := sqlc.WorkflowInstance{ID: "instance-001", Status: "aborted"}
+
+	err := uc.processAborted(ctx, event.GlobalEvent[any, any]{State: "order_updated"}, instance, map[string]any{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+	"time"
+)
+
+// actionApproval is recorded on the events the orchestrator emits for a
+// decision on an approval step.
+const actionApproval = "approval"
+
+var (
+	ErrApprovalNotFound = errors.New("approval not found")
+	ErrApprovalDecided  = errors.New("approval is already decided")
+)
+
+// requestApproval records the pending approval of a step and parks the
+// instance until an operator decides on it or it expires.
+func requestApproval(ctx context.Context, q sqlc.Querier, gevent event.GlobalEvent[any, any], step sqlc.FindStepsByTypeAndStateRow) error {
+	if len(step.Emits) != 2 {
+		return fmt.Errorf("step %s does not emit an approved and a rejected state", step.StepName)
+	}
+
+	request, err := json.Marshal(gevent.Payload.Request)
+	if err != nil {
+		return fmt.Errorf("parse request: %w", err)
+	}
+
+	var expiresAt sql.NullTime
+	if step.ExpireSeconds > 0 {
+		expiresAt = sql.NullTime{Time: time.Now().Add(time.Duration(step.ExpireSeconds) * time.Second), Valid: true}
+	}
+
+	err = q.CreateApproval(ctx, sqlc.CreateApprovalParams{
+		WorkflowInstanceID: gevent.InstanceID,
+		EventID:            gevent.EventID,
+		StepID:             step.StepID,
+		EventType:          gevent.EventType,
+		ApprovedState:      step.Emits[0],
+		RejectedState:      step.Emits[1],
+		Request:            request,
+		ExpiresAt:          expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("create approval: %w", err)
+	}
+
+	_, err = q.TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+		Status:       dto.AWAITING_APPROVAL.String(),
+		ID:           gevent.InstanceID,
+		FromStatuses: []string{dto.IN_PROGRESS.String()},
+	})
+	return err
+}
+
+type ApprovalUsecase struct {
+	queries sqlc.Store
+	oc      *OrchestraUsecase
+}
+
+func NewApprovalUsecase(queries sqlc.Store, oc *OrchestraUsecase) *ApprovalUsecase {
+	return &ApprovalUsecase{
+		queries: queries,
+		oc:      oc,
+	}
+}
+
+func (a *ApprovalUsecase) ListApprovals(ctx context.Context, req *dto.ApprovalListRequest) (*dto.ApprovalListResponse, error) {
+	page := max(req.Page, 1)
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	status := sql.NullString{String: req.Status, Valid: req.Status != ""}
+
+	total, err := a.queries.CountApprovals(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("count approvals: %w", err)
+	}
+
+	approvals, err := a.queries.ListApprovals(ctx, sqlc.ListApprovalsParams{
+		Status: status,
+		Limit:  int32(pageSize),
+		Offset: int32((page - 1) * pageSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list approvals: %w", err)
+	}
+
+	return &dto.ApprovalListResponse{
+		Approvals: approvals,
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+	}, nil
+}
+
+// Approve resumes the instance with the approval step's approved state.
+func (a *ApprovalUsecase) Approve(ctx context.Context, id int32, req *dto.ApprovalDecisionRequest) (*sqlc.Approval, error) {
+	return a.decideByID(ctx, id, dto.ApprovalStatusApproved, req)
+}
+
+// Reject resumes the instance with the approval step's rejected state.
+func (a *ApprovalUsecase) Reject(ctx context.Context, id int32, req *dto.ApprovalDecisionRequest) (*sqlc.Approval, error) {
+	return a.decideByID(ctx, id, dto.ApprovalStatusRejected, req)
+}
+
+func (a *ApprovalUsecase) decideByID(ctx context.Context, id int32, status string, req *dto.ApprovalDecisionRequest) (*sqlc.Approval, error) {
+	approval, err := a.queries.FindApproval(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrApprovalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find approval: %w", err)
+	}
+
+	err = a.decide(ctx, &approval, status, req.DecidedBy, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &approval, nil
+}
+
+// ExpireApprovals rejects the pending approvals whose expiry has passed.
+func (a *ApprovalUsecase) ExpireApprovals(ctx context.Context, limit int) (int, error) {
+	expired, err := a.queries.FindExpiredApprovals(ctx, int32(limit))
+	if err != nil {
+		return 0, fmt.Errorf("find expired approvals: %w", err)
+	}
+
+	var rejected int
+	for _, approval := range expired {
+		err := a.decide(ctx, &approval, dto.ApprovalStatusExpired, dto.OrchestratorService, "approval expired")
+		if errors.Is(err, ErrApprovalDecided) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Error expiring approval %d: %v", approval.ID, err)
+			continue
+		}
+
+		rejected++
+	}
+
+	return rejected, nil
+}
+
+// decide records the decision and enqueues the reply to the approval step to
+// the orchestrator's topic in the same transaction, so a decision is never
+// stored without the event that resumes the instance.
+func (a *ApprovalUsecase) decide(ctx context.Context, approval *sqlc.Approval, status, decidedBy, reason string) error {
+	decision := *approval
+	decision.Status = status
+	decision.DecidedBy = decidedBy
+	decision.Reason = reason
+
+	err := a.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		// an operator and the expiry may decide at the same time
+		decided, err := q.DecideApproval(ctx, sqlc.DecideApprovalParams{
+			Status:    status,
+			DecidedBy: decidedBy,
+			Reason:    reason,
+			ID:        approval.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("decide approval: %w", err)
+		}
+
+		if decided == 0 {
+			return ErrApprovalDecided
+		}
+
+		resumed, err := q.TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+			Status:       dto.IN_PROGRESS.String(),
+			ID:           approval.WorkflowInstanceID,
+			FromStatuses: []string{dto.AWAITING_APPROVAL.String()},
+		})
+		if err != nil {
+			return err
+		}
+
+		gevent := approvalEvent(decision)
+
+		// a parallel branch failed and the instance is rolling back. The step
+		// fails without a state, so no forward step runs and the rollback
+		// can finish.
+		if resumed == 0 {
+			gevent.State = ""
+			gevent.Status = dto.FAILED.String()
+			gevent.StatusCode = 409
+		}
+
+		bytes, err := gevent.ToJSON()
+		if err != nil {
+			return fmt.Errorf("parse message: %w", err)
+		}
+
+		return enqueueMessage(ctx, q, a.oc.topic, gevent.InstanceID, bytes)
+	})
+	if err != nil {
+		return err
+	}
+
+	*approval = decision
+
+	return nil
+}
+
+// approvalEvent replies to the approval step as the orchestrator. A rejection
+// fails the step, so a rejected state nobody handles compensates the instance.
+func approvalEvent(approval sqlc.Approval) event.GlobalEvent[any, any] {
+	state, status, statusCode := approval.ApprovedState, dto.COMPLETE.String(), 200
+	if approval.Status != dto.ApprovalStatusApproved {
+		state, status, statusCode = approval.RejectedState, dto.FAILED.String(), 403
+	}
+
+	gevent := event.NewGlobalEvent[any, any](
+		actionApproval, status, event.BasePayload[any, any]{
+			Response: map[string]any{
+				"approval_id": approval.ID,
+				"decision":    approval.Status,
+				"decided_by":  approval.DecidedBy,
+				"reason":      approval.Reason,
+			},
+		})
+
+	gevent.Source = dto.OrchestratorService
+	gevent.State = state
+	gevent.EventType = approval.EventType
+	gevent.InstanceID = approval.WorkflowInstanceID
+	gevent.EventID = approval.EventID
+	gevent.StatusCode = statusCode
+
+	return gevent
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrchestraUsecase_processStep_ApprovalParksInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventType:  "order_process",
+		State:      "product_reservation_success",
+		Status:     "success",
+		InstanceID: "instance-001",
+	}
+	step := sqlc.FindStepsByTypeAndStateRow{
+		StepID:        9,
+		StepName:      "manual_review",
+		Service:       "orchestra-svc",
+		StepType:      "approval",
+		ExpireSeconds: 3600,
+		Emits:         []string{"approved", "rejected"},
+	}
+
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(9)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(9)).Return([]string{"order-svc"}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(9)).Return([]sqlc.PayloadMapping{}, nil)
+
+	var stepEventID string
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+			stepEventID = arg.EventID
+			return sqlc.WorkflowInstanceStep{}, nil
+		})
+	store.EXPECT().CreateApproval(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateApprovalParams) error {
+			assert.Equal(t, stepEventID, arg.EventID)
+			assert.Equal(t, int32(9), arg.StepID)
+			assert.Equal(t, "approved", arg.ApprovedState)
+			assert.Equal(t, "rejected", arg.RejectedState)
+			assert.JSONEq(t, `{"amount":5000}`, string(arg.Request))
+			assert.True(t, arg.ExpiresAt.Valid)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt.Time, time.Minute)
+			return nil
+		})
+	store.EXPECT().TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+		Status:       "awaiting_approval",
+		ID:           "instance-001",
+		FromStatuses: []string{"in_progress"},
+	}).Return(int64(1), nil)
+
+	err := uc.processStep(ctx, eventMsg, sqlc.WorkflowInstance{ID: "instance-001"}, step, map[string]any{
+		"order-svc": map[string]any{"amount": 5000},
+	})
+	assert.NoError(t, err)
+}
+
+func TestApprovalUsecase_Approve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	ac := NewApprovalUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{Topic: "orchestra-topic"}))
+
+	ctx := context.Background()
+
+	store.EXPECT().FindApproval(ctx, int32(1)).Return(sqlc.Approval{
+		ID:                 1,
+		WorkflowInstanceID: "instance-001",
+		EventID:            "event-001",
+		EventType:          "order_process",
+		ApprovedState:      "approved",
+		RejectedState:      "rejected",
+		Status:             "pending",
+	}, nil)
+	store.EXPECT().DecideApproval(ctx, sqlc.DecideApprovalParams{
+		Status:    "approved",
+		DecidedBy: "alice",
+		Reason:    "known customer",
+		ID:        1,
+	}).Return(int64(1), nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+		Status:       "in_progress",
+		ID:           "instance-001",
+		FromStatuses: []string{"awaiting_approval"},
+	}).Return(int64(1), nil)
+
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "orchestra-topic", arg.Topic)
+			assert.Equal(t, "instance-001", arg.MessageKey)
+
+			reply, err := event.FromJSON[any, any]([]byte(arg.Payload))
+			assert.NoError(t, err)
+			assert.Equal(t, "approval", reply.Action)
+			assert.Equal(t, "success", reply.Status)
+			assert.Equal(t, "approved", reply.State)
+			assert.Equal(t, "event-001", reply.EventID)
+			assert.Contains(t, arg.Payload, `"decided_by":"alice"`)
+			return nil
+		})
+
+	approval, err := ac.Approve(ctx, 1, &dto.ApprovalDecisionRequest{DecidedBy: "alice", Reason: "known customer"})
+	assert.NoError(t, err)
+	assert.Equal(t, "approved", approval.Status)
+	assert.Equal(t, "alice", approval.DecidedBy)
+}
+
+func TestApprovalUsecase_Reject_NotPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	ac := NewApprovalUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{Topic: "orchestra-topic"}))
+
+	ctx := context.Background()
+	req := &dto.ApprovalDecisionRequest{DecidedBy: "alice"}
+
+	store.EXPECT().FindApproval(ctx, int32(1)).Return(sqlc.Approval{}, sql.ErrNoRows)
+
+	_, err := ac.Reject(ctx, 1, req)
+	assert.ErrorIs(t, err, ErrApprovalNotFound)
+
+	// expired between the lookup and the decision
+	store.EXPECT().FindApproval(ctx, int32(2)).Return(sqlc.Approval{ID: 2, Status: "pending"}, nil)
+	store.EXPECT().DecideApproval(ctx, gomock.Any()).Return(int64(0), nil)
+
+	_, err = ac.Reject(ctx, 2, req)
+	assert.ErrorIs(t, err, ErrApprovalDecided)
+}
+
+func TestApprovalUsecase_ExpireApprovals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	ac := NewApprovalUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{Topic: "orchestra-topic"}))
+
+	ctx := context.Background()
+
+	store.EXPECT().FindExpiredApprovals(ctx, int32(10)).Return([]sqlc.Approval{
+		{ID: 1, WorkflowInstanceID: "instance-001", EventID: "event-001", EventType: "order_process", ApprovedState: "approved", RejectedState: "rejected", Status: "pending"},
+	}, nil)
+	store.EXPECT().DecideApproval(ctx, sqlc.DecideApprovalParams{
+		Status:    "expired",
+		DecidedBy: "orchestra-svc",
+		Reason:    "approval expired",
+		ID:        1,
+	}).Return(int64(1), nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, gomock.Any()).Return(int64(1), nil)
+
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			reply, err := event.FromJSON[any, any]([]byte(arg.Payload))
+			assert.NoError(t, err)
+			assert.Equal(t, "failed", reply.Status)
+			assert.Equal(t, 403, reply.StatusCode)
+			assert.Equal(t, "rejected", reply.State)
+			return nil
+		})
+
+	expired, err := ac.ExpireApprovals(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+}
+
+func TestApprovalUsecase_Approve_InstanceCompensating(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	ac := NewApprovalUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{Topic: "orchestra-topic"}))
+
+	ctx := context.Background()
+
+	store.EXPECT().FindApproval(ctx, int32(1)).Return(sqlc.Approval{
+		ID:                 1,
+		WorkflowInstanceID: "instance-001",
+		EventID:            "event-001",
+		EventType:          "order_process",
+		ApprovedState:      "approved",
+		RejectedState:      "rejected",
+		Status:             "pending",
+	}, nil)
+	store.EXPECT().DecideApproval(ctx, gomock.Any()).Return(int64(1), nil)
+
+	// a parallel branch failed while the instance waited
+	store.EXPECT().TransitionWorkflowInstance(ctx, gomock.Any()).Return(int64(0), nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			reply, err := event.FromJSON[any, any]([]byte(arg.Payload))
+			assert.NoError(t, err)
+			assert.Equal(t, "failed", reply.Status)
+			assert.Equal(t, 409, reply.StatusCode)
+			assert.Empty(t, reply.State)
+			return nil
+		})
+
+	approval, err := ac.Approve(ctx, 1, &dto.ApprovalDecisionRequest{DecidedBy: "alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, "approved", approval.Status)
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded on the events the orchestrator emits to start a child
+// instance and to resume its parent once the child finished.
+const (
+	actionStartChild = "start_child"
+	actionChildDone  = "child_done"
+)
+
+// startChildWorkflow creates the child instance of a workflow step and emits
+// its start state. The start event goes through the outbox to the
+// orchestrator's own topic, so the child runs like any other instance.
+func (o *OrchestraUsecase) startChildWorkflow(ctx context.Context, q sqlc.Querier, gevent event.GlobalEvent[any, any], step sqlc.FindStepsByTypeAndStateRow) error {
+	if len(step.Emits) != 2 {
+		return fmt.Errorf("step %s does not emit a completed and a failed state", step.StepName)
+	}
+
+	wf, err := q.FindWorkflowByType(ctx, step.ChildWorkflow)
+	if err != nil {
+		return fmt.Errorf("find workflow %s: %w", step.ChildWorkflow, err)
+	}
+
+	childID := uuid.New().String()
+
+	err = q.CreateChildWorkflowInstance(ctx, sqlc.CreateChildWorkflowInstanceParams{
+		ID:               childID,
+		WorkflowID:       wf.ID,
+		WorkflowVersion:  wf.Version,
+		Status:           dto.IN_PROGRESS.String(),
+		ParentInstanceID: sql.NullString{String: gevent.InstanceID, Valid: true},
+		ParentEventID:    sql.NullString{String: gevent.EventID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("create child instance: %w", err)
+	}
+
+	err = o.publishStarted(ctx, q, wf.Type, sqlc.WorkflowInstance{
+		ID:               childID,
+		WorkflowVersion:  wf.Version,
+		ParentInstanceID: sql.NullString{String: gevent.InstanceID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	// the step's request is cached for the child under the orchestrator
+	start := event.NewGlobalEvent[any, any](
+		actionStartChild, dto.COMPLETE.String(), event.BasePayload[any, any]{
+			Response: gevent.Payload.Request,
+		})
+
+	start.State = step.ChildStartState
+	start.EventType = wf.Type
+	start.InstanceID = childID
+	start.StatusCode = 200
+
+	bytes, err := start.ToJSON()
+	if err != nil {
+		return fmt.Errorf("parse message: %w", err)
+	}
+
+	return enqueueMessage(ctx, q, o.topic, childID, bytes)
+}
+
+// finishChild marks a child instance completed or failed and resumes its
+// parent in the same transaction.
+func (o *OrchestraUsecase) finishChild(ctx context.Context, eventType string, child sqlc.WorkflowInstance, status string, wfiSteps []sqlc.FindWorkflowInstanceByTypeAndIDRow) error {
+	cachePayload, err := o.cache.Load(ctx, child.ID)
+	if err != nil {
+		return fmt.Errorf("load payload: %w", err)
+	}
+
+	return o.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		// only the reply that finishes the child resumes the parent
+		finished, err := q.TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+			Status:       status,
+			ID:           child.ID,
+			FromStatuses: []string{dto.IN_PROGRESS.String(), dto.COMPENSATING.String()},
+		})
+		if err != nil {
+			return err
+		}
+
+		if finished == 0 {
+			return nil
+		}
+
+		err = o.publishFinished(ctx, q, eventType, child, status, wfiSteps)
+		if err != nil {
+			return err
+		}
+
+		return o.resumeParent(ctx, q, child, status, cachePayload)
+	})
+}
+
+// resumeParent replies to the parent's workflow step as the orchestrator. It
+// emits the step's first state when the child completed and its second one
+// otherwise, failing the step so an unhandled failure compensates the parent.
+func (o *OrchestraUsecase) resumeParent(ctx context.Context, q sqlc.Querier, child sqlc.WorkflowInstance, status string, cachePayload map[string]any) error {
+	parentID := child.ParentInstanceID.String
+
+	parent, err := q.FindWorkflowInstanceWithType(ctx, parentID)
+	if err != nil {
+		return fmt.Errorf("find parent instance: %w", err)
+	}
+
+	step, err := q.FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
+		EventID:            child.ParentEventID.String,
+		WorkflowInstanceID: parentID,
+	})
+	if err != nil {
+		return fmt.Errorf("find parent step: %w", err)
+	}
+
+	if len(step.Emits) != 2 {
+		return fmt.Errorf("parent step %s does not emit a completed and a failed state", step.EventID)
+	}
+
+	state, replyStatus, statusCode := step.Emits[0], dto.COMPLETE.String(), 200
+	if status != "completed" {
+		state, replyStatus, statusCode = step.Emits[1], dto.FAILED.String(), 424
+	}
+
+	gevent := event.NewGlobalEvent[any, any](
+		actionChildDone, replyStatus, event.BasePayload[any, any]{
+			Response: map[string]any{
+				"instance_id": child.ID,
+				"status":      status,
+				"payload":     cachePayload,
+			},
+		})
+
+	gevent.Source = dto.OrchestratorService
+	gevent.State = state
+	gevent.EventType = parent.Type
+	gevent.InstanceID = parentID
+	gevent.EventID = step.EventID
+	gevent.StatusCode = statusCode
+
+	bytes, err := gevent.ToJSON()
+	if err != nil {
+		return fmt.Errorf("parse message: %w", err)
+	}
+
+	return enqueueMessage(ctx, q, o.topic, parentID, bytes)
+}
+
+// abortChildren aborts the child instances a parent started. A child that
+// already finished has resumed its parent and is left alone.
+func (o *OrchestraUsecase) abortChildren(ctx context.Context, parentID string) {
+	children, err := o.queries.FindChildWorkflowInstanceIDs(ctx, sql.NullString{String: parentID, Valid: true})
+	if err != nil {
+		log.Printf("Error finding children of instance %s: %v", parentID, err)
+		return
+	}
+
+	for _, id := range children {
+		_, err := o.AbortInstance(ctx, id)
+		if err != nil && !errors.Is(err, ErrInstanceNotAbortable) {
+			log.Printf("Error aborting child instance %s: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrchestraUsecase_processStep_WorkflowStartsChild(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{Topic: "orchestra-topic"})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventType:  "order_process",
+		State:      "order_created",
+		Status:     "success",
+		InstanceID: "instance-001",
+	}
+	step := sqlc.FindStepsByTypeAndStateRow{
+		StepID:          9,
+		StepName:        "bank_registration",
+		Service:         "orchestra-svc",
+		StepType:        "workflow",
+		ChildWorkflow:   "bank_account_registration",
+		ChildStartState: "bank_regis_created",
+		Emits:           []string{"bank_registered", "bank_registration_failed"},
+	}
+
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(9)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(9)).Return([]string{"order-svc"}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(9)).Return([]sqlc.PayloadMapping{}, nil)
+
+	var stepEventID, childID string
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+			stepEventID = arg.EventID
+			return sqlc.WorkflowInstanceStep{}, nil
+		})
+	store.EXPECT().FindWorkflowByType(ctx, "bank_account_registration").Return(sqlc.Workflow{ID: 3, Type: "bank_account_registration", Version: 2}, nil)
+	store.EXPECT().CreateChildWorkflowInstance(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateChildWorkflowInstanceParams) error {
+			childID = arg.ID
+			assert.Equal(t, int32(3), arg.WorkflowID)
+			assert.Equal(t, int32(2), arg.WorkflowVersion)
+			assert.Equal(t, "in_progress", arg.Status)
+			assert.Equal(t, sql.NullString{String: "instance-001", Valid: true}, arg.ParentInstanceID)
+			assert.Equal(t, sql.NullString{String: stepEventID, Valid: true}, arg.ParentEventID)
+			return nil
+		})
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "orchestra-topic", arg.Topic)
+			assert.Equal(t, childID, arg.MessageKey)
+
+			start, err := event.FromJSON[any, any]([]byte(arg.Payload))
+			assert.NoError(t, err)
+			assert.Equal(t, "start_child", start.Action)
+			assert.Equal(t, "bank_regis_created", start.State)
+			assert.Equal(t, "bank_account_registration", start.EventType)
+			assert.Equal(t, childID, start.InstanceID)
+			assert.Equal(t, map[string]any{"user_id": "user-001"}, start.Payload.Response)
+			return nil
+		})
+
+	err := uc.processStep(ctx, eventMsg, sqlc.WorkflowInstance{ID: "instance-001"}, step, map[string]any{
+		"order-svc": map[string]any{"user_id": "user-001"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestOrchestraUsecase_processDone_ChildResumesParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{Topic: "orchestra-topic"})
+
+	ctx := context.Background()
+	child := sqlc.WorkflowInstance{
+		ID:               "child-001",
+		Status:           "in_progress",
+		ParentInstanceID: sql.NullString{String: "instance-001", Valid: true},
+		ParentEventID:    sql.NullString{String: "event-001", Valid: true},
+	}
+
+	_, err := uc.cache.Append(ctx, "child-001", "bank-svc", map[string]any{"account_id": "acc-001"})
+	assert.NoError(t, err)
+
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "success", StepID: 1},
+	}, nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+		Status:       "completed",
+		ID:           "child-001",
+		FromStatuses: []string{"in_progress", "compensating"},
+	}).Return(int64(1), nil)
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{ID: "instance-001", Type: "order_process"}, nil)
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
+		EventID:            "event-001",
+		WorkflowInstanceID: "instance-001",
+	}).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		EventID: "event-001",
+		Status:  "in_progress",
+		Emits:   []string{"bank_registered", "bank_registration_failed"},
+	}, nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "orchestra-topic", arg.Topic)
+			assert.Equal(t, "instance-001", arg.MessageKey)
+
+			reply, err := event.FromJSON[any, any]([]byte(arg.Payload))
+			assert.NoError(t, err)
+			assert.Equal(t, "child_done", reply.Action)
+			assert.Equal(t, "success", reply.Status)
+			assert.Equal(t, "bank_registered", reply.State)
+			assert.Equal(t, "order_process", reply.EventType)
+			assert.Equal(t, "event-001", reply.EventID)
+			assert.Contains(t, arg.Payload, `"account_id":"acc-001"`)
+			return nil
+		})
+
+	err = uc.processDone(ctx, "bank_account_registration", child)
+	assert.NoError(t, err)
+}
+
+func TestOrchestraUsecase_finishAbort_ChildFailsParentStep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{Topic: "orchestra-topic"})
+
+	ctx := context.Background()
+	child := sqlc.WorkflowInstance{
+		ID:               "child-001",
+		Status:           "aborting",
+		WorkflowVersion:  1,
+		ParentInstanceID: sql.NullString{String: "instance-001", Valid: true},
+		ParentEventID:    sql.NullString{String: "event-001", Valid: true},
+	}
+	abortEvent := uc.createAbortEvent("bank_account_registration", "child-001")
+
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "compensated", StepID: 1},
+	}, nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, gomock.Any()).Return(int64(1), nil)
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{ID: "instance-001", Type: "order_process"}, nil)
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		EventID: "event-001",
+		Emits:   []string{"bank_registered", "bank_registration_failed"},
+	}, nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			reply, err := event.FromJSON[any, any]([]byte(arg.Payload))
+			assert.NoError(t, err)
+			assert.Equal(t, "failed", reply.Status)
+			assert.Equal(t, 424, reply.StatusCode)
+			assert.Equal(t, "bank_registration_failed", reply.State)
+			assert.Contains(t, arg.Payload, `"status":"aborted"`)
+			return nil
+		})
+	store.EXPECT().FindStepsByTypeAndState(ctx, gomock.Any()).Return([]sqlc.FindStepsByTypeAndStateRow{}, nil)
+
+	err := uc.finishAbort(ctx, abortEvent, child, map[string]any{})
+	assert.NoError(t, err)
+}
+
+func TestOrchestraUsecase_abortChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{Topic: "orchestra-topic"})
+
+	ctx := context.Background()
+
+	store.EXPECT().FindChildWorkflowInstanceIDs(ctx, sql.NullString{String: "instance-001", Valid: true}).Return([]string{"child-001"}, nil)
+
+	// the child already finished and resumed its parent
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "child-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{
+		ID:     "child-001",
+		Type:   "bank_account_registration",
+		Status: "completed",
+	}, nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, sqlc.TransitionWorkflowInstanceParams{
+		Status:       "aborting",
+		ID:           "child-001",
+		FromStatuses: []string{"in_progress", "compensating", "awaiting_approval"},
+	}).Return(int64(0), nil)
+
+	uc.abortChildren(ctx, "instance-001")
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+)
+
+// actionCompensate marks the events sent to compensating steps, the services
+// echo it back on their replies.
+const actionCompensate = "compensate"
+
+// compensate walks the steps that already succeeded for the instance, newest
+// first, and emits the compensating step configured for each of them.
+func (o *OrchestraUsecase) compensate(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance) error {
+	steps, err := o.queries.FindCompensableInstanceSteps(ctx, instance.ID)
+	if err != nil {
+		return fmt.Errorf("find compensable steps: %w", err)
+	}
+
+	if len(steps) == 0 {
+		return nil
+	}
+
+	// an aborting instance keeps its status so forward events stay ignored
+	if instance.Status != dto.ABORTING.String() {
+		err = o.queries.UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{
+			Status: dto.COMPENSATING.String(),
+			ID:     instance.ID,
+		})
+
+		if err != nil {
+			return fmt.Errorf("update workflow instance: %w", err)
+		}
+	}
+
+	for _, step := range steps {
+		err := o.compensateStep(ctx, eventMsg, instance, step)
+		if err != nil {
+			log.Printf("Error compensating step %d: %v", step.StepID, err)
+			continue
+		}
+	}
+
+	return nil
+}
+
+func (o *OrchestraUsecase) compensateStep(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, step sqlc.FindCompensableInstanceStepsRow) error {
+	var request any
+
+	// the compensating step receives the same request the forward step got,
+	// e.g. product_release needs the product and quantity that were reserved
+	if step.EventMessage.Valid {
+		original, err := event.FromJSON[any, any]([]byte(step.EventMessage.String))
+		if err != nil {
+			return fmt.Errorf("parse original message: %w", err)
+		}
+		request = original.Payload.Request
+	}
+
+	gevent := o.createGlobalEvent(eventMsg, request, instance.ID)
+	gevent.Action = actionCompensate
+
+	bytes, err := gevent.ToJSON()
+	if err != nil {
+		return fmt.Errorf("parse message: %w", err)
+	}
+
+	return o.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		err := o.createWorkflowInstanceStep(ctx, q, gevent, sqlc.FindStepsByTypeAndStateRow{
+			State:     eventMsg.State,
+			StepID:    step.CompensationStepID,
+			Service:   step.CompensationService,
+			StepName:  step.CompensationStepName,
+			StepTopic: step.CompensationTopic,
+		}, bytes)
+
+		if err != nil {
+			return err
+		}
+
+		err = q.UpdateWorkflowInstanceStepStatus(ctx, sqlc.UpdateWorkflowInstanceStepStatusParams{
+			Status:  dto.COMPENSATED.String(),
+			EventID: step.EventID,
+		})
+
+		if err != nil {
+			return fmt.Errorf("mark step compensated: %w", err)
+		}
+
+		return enqueueMessage(ctx, q, step.CompensationTopic, gevent.InstanceID, bytes)
+	})
+}
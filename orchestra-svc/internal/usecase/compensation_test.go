@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrchestraUsecase_compensate_NoSucceededSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	instance := sqlc.WorkflowInstance{ID: "instance-001"}
+
+	store.EXPECT().FindCompensableInstanceSteps(ctx, "instance-001").Return([]sqlc.FindCompensableInstanceStepsRow{}, nil)
+
+	err := uc.compensate(ctx, event.GlobalEvent[any, any]{State: "payment_failed"}, instance)
+	assert.NoError(t, err)
+}
+
+func TestOrchestraUsecase_compensate_ErrorCreatingCompensationStep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	instance := sqlc.WorkflowInstance{ID: "instance-001"}
+	eventMsg := event.GlobalEvent[any, any]{
+		EventType: "order_process",
+		State:     "payment_failed",
+		Status:    "error",
+	}
+
+	store.EXPECT().FindCompensableInstanceSteps(ctx, "instance-001").Return([]sqlc.FindCompensableInstanceStepsRow{
+		{
+			EventID:             "event-001",
+			StepID:              2,
+			EventMessage:        sql.NullString{String: `{"payload":{"request":{"product_id":"P-1","quantity":2}}}`, Valid: true},
+			CompensationStepID:  5,
+			CompensationService: "product-svc",
+			CompensationTopic:   "product-topic",
+		},
+	}, nil)
+	store.EXPECT().UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{
+		Status: "compensating",
+		ID:     "instance-001",
+	}).Return(nil)
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+			assert.Equal(t, int32(5), arg.StepID)
+			assert.Contains(t, arg.EventMessage.String, `"product_id":"P-1"`)
+			return sqlc.WorkflowInstanceStep{}, fmt.Errorf("error")
+		})
+
+	err := uc.compensate(ctx, eventMsg, instance)
+	assert.NoError(t, err)
+}
+
+func TestOrchestraUsecase_processDone_WaitsForInProgressSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceID: "instance-001", InstanceStepStatus: "compensated", StepID: 2},
+		{InstanceID: "instance-001", InstanceStepStatus: "in_progress", StepID: 5},
+	}, nil)
+
+	err := uc.processDone(ctx, "order_process", sqlc.WorkflowInstance{ID: "instance-001"})
+	assert.NoError(t, err)
+}
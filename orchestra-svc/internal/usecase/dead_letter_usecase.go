@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/sqlc"
+	"orchestra-svc/pkg/deadletter"
+)
+
+type replaySender interface {
+	SendMessageWithHeaders(topic, key string, value []byte, headers map[string]string) error
+}
+
+type DeadLetterUsecase struct {
+	queries  sqlc.Store
+	producer replaySender
+}
+
+func NewDeadLetterUsecase(queries sqlc.Store, producer replaySender) *DeadLetterUsecase {
+	return &DeadLetterUsecase{
+		queries:  queries,
+		producer: producer,
+	}
+}
+
+// Store saves a record read from one of the services' dead-letter topics.
+func (d *DeadLetterUsecase) Store(ctx context.Context, record deadletter.Record) error {
+	headers, err := json.Marshal(record.Headers)
+	if err != nil {
+		return err
+	}
+
+	return d.queries.CreateDeadLetter(ctx, sqlc.CreateDeadLetterParams{
+		Service:        record.Service,
+		Topic:          record.Topic,
+		KafkaPartition: record.Partition,
+		KafkaOffset:    record.Offset,
+		MessageKey:     record.Key,
+		Payload:        record.Value,
+		Headers:        headers,
+		Error:          record.Error,
+		Attempts:       int32(record.Attempts),
+		FailedAt:       record.FailedAt,
+	})
+}
+
+func (d *DeadLetterUsecase) ListDeadLetters(ctx context.Context, req *dto.DeadLetterListRequest) (*dto.DeadLetterListResponse, error) {
+	page := max(req.Page, 1)
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	filter := sqlc.CountDeadLettersParams{
+		Service: sql.NullString{String: req.Service, Valid: req.Service != ""},
+		Status:  sql.NullString{String: req.Status, Valid: req.Status != ""},
+	}
+
+	total, err := d.queries.CountDeadLetters(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("count dead letters: %w", err)
+	}
+
+	deadLetters, err := d.queries.ListDeadLetters(ctx, sqlc.ListDeadLettersParams{
+		Service: filter.Service,
+		Status:  filter.Status,
+		Limit:   int32(pageSize),
+		Offset:  int32((page - 1) * pageSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list dead letters: %w", err)
+	}
+
+	return &dto.DeadLetterListResponse{
+		DeadLetters: deadLetters,
+		Page:        page,
+		PageSize:    pageSize,
+		Total:       total,
+	}, nil
+}
+
+// Replay publishes the selected dead letters back onto their original topic
+// with the original key and headers. Unknown ids are left out of the result.
+func (d *DeadLetterUsecase) Replay(ctx context.Context, req *dto.DeadLetterReplayRequest) ([]dto.DeadLetterReplayResult, error) {
+	deadLetters, err := d.queries.FindDeadLettersByIDs(ctx, req.IDs)
+	if err != nil {
+		return nil, fmt.Errorf("find dead letters: %w", err)
+	}
+
+	results := make([]dto.DeadLetterReplayResult, 0, len(deadLetters))
+	for _, dl := range deadLetters {
+		result := dto.DeadLetterReplayResult{ID: dl.ID, Topic: dl.Topic}
+
+		err := d.replay(ctx, dl)
+		if err != nil {
+			log.Printf("Error replaying dead letter %d: %v", dl.ID, err)
+			result.Error = err.Error()
+		}
+
+		result.Replayed = err == nil
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (d *DeadLetterUsecase) replay(ctx context.Context, dl sqlc.DeadLetter) error {
+	var headers map[string]string
+	if err := json.Unmarshal(dl.Headers, &headers); err != nil {
+		return err
+	}
+
+	err := d.producer.SendMessageWithHeaders(dl.Topic, dl.MessageKey, dl.Payload, headers)
+	if err != nil {
+		return err
+	}
+
+	return d.queries.MarkDeadLetterReplayed(ctx, dl.ID)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"orchestra-svc/internal/dto"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+)
+
+type replayedMessage struct {
+	topic   string
+	key     string
+	value   []byte
+	headers map[string]string
+}
+
+type fakeReplaySender struct {
+	sent []replayedMessage
+	err  error
+}
+
+func (f *fakeReplaySender) SendMessageWithHeaders(topic, key string, value []byte, headers map[string]string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, replayedMessage{topic: topic, key: key, value: value, headers: headers})
+	return nil
+}
+
+func TestDeadLetterUsecase_Replay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	sender := &fakeReplaySender{}
+	dc := NewDeadLetterUsecase(store, sender)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindDeadLettersByIDs(ctx, []int32{1, 2}).Return([]sqlc.DeadLetter{
+		{ID: 1, Topic: "payment-topic", MessageKey: "key-1", Payload: []byte(`{"event_id":"event-001"}`), Headers: []byte(`{"trace":"abc"}`)},
+	}, nil)
+	store.EXPECT().MarkDeadLetterReplayed(ctx, int32(1)).Return(nil)
+
+	results, err := dc.Replay(ctx, &dto.DeadLetterReplayRequest{IDs: []int32{1, 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.DeadLetterReplayResult{{ID: 1, Topic: "payment-topic", Replayed: true}}, results)
+
+	assert.Len(t, sender.sent, 1)
+	assert.Equal(t, "payment-topic", sender.sent[0].topic)
+	assert.Equal(t, "key-1", sender.sent[0].key)
+	assert.Equal(t, []byte(`{"event_id":"event-001"}`), sender.sent[0].value)
+	assert.Equal(t, map[string]string{"trace": "abc"}, sender.sent[0].headers)
+}
+
+func TestDeadLetterUsecase_Replay_SendError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dc := NewDeadLetterUsecase(store, &fakeReplaySender{err: errors.New("broker down")})
+
+	ctx := context.Background()
+
+	store.EXPECT().FindDeadLettersByIDs(ctx, []int32{1}).Return([]sqlc.DeadLetter{
+		{ID: 1, Topic: "payment-topic", Headers: []byte(`{}`)},
+	}, nil)
+
+	results, err := dc.Replay(ctx, &dto.DeadLetterReplayRequest{IDs: []int32{1}})
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.DeadLetterReplayResult{{ID: 1, Topic: "payment-topic", Error: "broker down"}}, results)
+}
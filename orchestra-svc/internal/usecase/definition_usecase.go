@@ -0,0 +1,769 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/sqlc"
+	"orchestra-svc/pkg/guard"
+	"orchestra-svc/pkg/jsonpath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidDefinition = errors.New("invalid workflow definition")
+
+type DefinitionUsecase struct {
+	queries sqlc.Store
+}
+
+func NewDefinitionUsecase(q sqlc.Store) *DefinitionUsecase {
+	return &DefinitionUsecase{
+		queries: q,
+	}
+}
+
+// ValidateDefinition fills in the default emitted states of every step and
+// checks the definition for unknown topics and steps, steps without payload
+// keys or with broken mappings, invalid guards, states that can never be
+// reached and cycles between states.
+func ValidateDefinition(def *dto.WorkflowDefinition, topics []string) error {
+	var errs []error
+
+	if def.Type == "" {
+		errs = append(errs, errors.New("type is required"))
+	}
+
+	if len(def.Steps) == 0 {
+		errs = append(errs, errors.New("at least one step is required"))
+	}
+
+	if len(def.InitialStates) == 0 {
+		errs = append(errs, errors.New("at least one initial state is required"))
+	}
+
+	knownTopics := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		knownTopics[topic] = true
+	}
+
+	steps := make(map[string]*dto.StepDefinition, len(def.Steps))
+	for i := range def.Steps {
+		step := &def.Steps[i]
+
+		if step.Name == "" {
+			errs = append(errs, fmt.Errorf("step %d: name is required", i))
+			continue
+		}
+
+		if _, ok := steps[step.Name]; ok {
+			errs = append(errs, fmt.Errorf("step %s: defined more than once", step.Name))
+			continue
+		}
+
+		switch step.Type {
+		case "", dto.StepService:
+			if step.Service == "" {
+				errs = append(errs, fmt.Errorf("step %s: service is required", step.Name))
+			}
+
+			if step.Topic == "" {
+				errs = append(errs, fmt.Errorf("step %s: topic is required", step.Name))
+			} else if len(knownTopics) > 0 && !knownTopics[step.Topic] {
+				errs = append(errs, fmt.Errorf("step %s: unknown topic %q", step.Name, step.Topic))
+			}
+		case dto.StepDelay:
+			errs = append(errs, validateOrchestratorStep(step)...)
+
+			if step.DelaySeconds <= 0 {
+				errs = append(errs, fmt.Errorf("step %s: delay_seconds must be positive", step.Name))
+			}
+		case dto.StepApproval:
+			errs = append(errs, validateOrchestratorStep(step)...)
+
+			if step.ExpireSeconds < 0 {
+				errs = append(errs, fmt.Errorf("step %s: expire_seconds must not be negative", step.Name))
+			}
+
+			// approving emits the first state, rejecting the second
+			if len(step.Emits) == 0 {
+				step.Emits = []string{dto.ApprovalApproved, dto.ApprovalRejected}
+			}
+		case dto.StepWorkflow:
+			errs = append(errs, validateOrchestratorStep(step)...)
+
+			if step.Workflow == "" {
+				errs = append(errs, fmt.Errorf("step %s: workflow is required", step.Name))
+			} else if step.Workflow == def.Type {
+				errs = append(errs, fmt.Errorf("step %s: workflow must not start itself", step.Name))
+			}
+
+			if step.StartState == "" {
+				errs = append(errs, fmt.Errorf("step %s: start_state is required", step.Name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("step %s: unknown type %q", step.Name, step.Type))
+		}
+
+		if step.DelaySeconds != 0 && step.Type != dto.StepDelay {
+			errs = append(errs, fmt.Errorf("step %s: delay_seconds is only for delay steps", step.Name))
+		}
+
+		if step.ExpireSeconds != 0 && step.Type != dto.StepApproval {
+			errs = append(errs, fmt.Errorf("step %s: expire_seconds is only for approval steps", step.Name))
+		}
+
+		if (step.Workflow != "" || step.StartState != "") && step.Type != dto.StepWorkflow {
+			errs = append(errs, fmt.Errorf("step %s: workflow and start_state are only for workflow steps", step.Name))
+		}
+
+		// the first state is emitted on success, the second on failure
+		if (step.Type == dto.StepApproval || step.Type == dto.StepWorkflow) && len(step.Emits) != 2 {
+			errs = append(errs, fmt.Errorf("step %s: %s steps emit exactly two states", step.Name, step.Type))
+		}
+
+		if step.TimeoutSeconds < 0 {
+			errs = append(errs, fmt.Errorf("step %s: timeout_seconds must not be negative", step.Name))
+		}
+
+		switch step.OnTimeout {
+		case "", dto.TimeoutFail, dto.TimeoutRetry:
+		default:
+			errs = append(errs, fmt.Errorf("step %s: unknown on_timeout %q", step.Name, step.OnTimeout))
+		}
+
+		if step.Retry != nil {
+			errs = append(errs, validateRetry(step.Name, step.Retry)...)
+		}
+
+		errs = append(errs, validateMappings(step.Name, step.Mappings)...)
+
+		if len(step.Emits) == 0 {
+			step.Emits = []string{step.Name + "_success", step.Name + "_failed"}
+		}
+
+		steps[step.Name] = step
+	}
+
+	used := make(map[string]bool, len(steps))
+	for _, step := range def.Steps {
+		if step.Compensation == "" {
+			continue
+		}
+
+		compensation, ok := steps[step.Compensation]
+		if !ok {
+			errs = append(errs, fmt.Errorf("step %s: unknown compensation step %q", step.Name, step.Compensation))
+			continue
+		}
+
+		if compensation.Type != "" && compensation.Type != dto.StepService {
+			errs = append(errs, fmt.Errorf("step %s: compensation step %q must be a service step", step.Name, step.Compensation))
+		}
+
+		used[step.Compensation] = true
+	}
+
+	triggers := make(map[string][]string, len(def.States))
+	forward := make(map[string]bool, len(steps))
+	for i, state := range def.States {
+		if state.State == "" {
+			errs = append(errs, fmt.Errorf("state %d: state is required", i))
+			continue
+		}
+
+		if _, ok := triggers[state.State]; ok {
+			errs = append(errs, fmt.Errorf("state %s: defined more than once", state.State))
+			continue
+		}
+
+		if len(state.Steps) == 0 {
+			errs = append(errs, fmt.Errorf("state %s: no steps", state.State))
+		}
+
+		for _, name := range state.Steps {
+			if _, ok := steps[name]; !ok {
+				errs = append(errs, fmt.Errorf("state %s: unknown step %q", state.State, name))
+				continue
+			}
+
+			forward[name] = true
+			used[name] = true
+		}
+
+		errs = append(errs, validateGuards(state)...)
+
+		triggers[state.State] = state.Steps
+	}
+
+	// a join step is emitted by whichever required state arrives last, so
+	// each of them has to trigger it
+	for _, step := range def.Steps {
+		for _, state := range step.Requires {
+			if !slices.Contains(triggers[state], step.Name) {
+				errs = append(errs, fmt.Errorf("step %s: required state %q does not trigger it", step.Name, state))
+			}
+		}
+	}
+
+	// compensating steps reuse the forward step's request, other service
+	// steps build their request from the cached payloads
+	for _, step := range def.Steps {
+		if step.Name == "" {
+			continue
+		}
+
+		if !used[step.Name] {
+			errs = append(errs, fmt.Errorf("step %s: not used by any state or compensation", step.Name))
+		}
+
+		isService := step.Type == "" || step.Type == dto.StepService
+		if forward[step.Name] && isService && len(step.PayloadKeys) == 0 && len(step.Mappings) == 0 {
+			errs = append(errs, fmt.Errorf("step %s: no payload keys or mappings", step.Name))
+		}
+	}
+
+	for _, state := range def.InitialStates {
+		if _, ok := triggers[state]; !ok {
+			errs = append(errs, fmt.Errorf("initial state %s: no steps", state))
+		}
+	}
+
+	// workflow_aborted is emitted by the orchestrator itself when an instance is aborted
+	reached := reachableStates(append(slices.Clone(def.InitialStates), dto.WorkflowAborted), triggers, steps)
+	for _, state := range def.States {
+		if state.State != "" && !reached[state.State] {
+			errs = append(errs, fmt.Errorf("state %s: unreachable from initial states", state.State))
+		}
+	}
+
+	if cycle := findStateCycle(triggers, steps); len(cycle) > 0 {
+		errs = append(errs, fmt.Errorf("cycle detected: %s", strings.Join(cycle, " -> ")))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w:\n%w", ErrInvalidDefinition, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func reachableStates(initial []string, triggers map[string][]string, steps map[string]*dto.StepDefinition) map[string]bool {
+	reached := make(map[string]bool)
+	queue := append([]string{}, initial...)
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		if reached[state] {
+			continue
+		}
+		reached[state] = true
+
+		for _, name := range triggers[state] {
+			step, ok := steps[name]
+			if !ok {
+				continue
+			}
+			queue = append(queue, step.Emits...)
+
+			// a failing saga emits the compensating step's outcome as well
+			if compensation, ok := steps[step.Compensation]; ok {
+				queue = append(queue, compensation.Emits...)
+			}
+		}
+	}
+
+	return reached
+}
+
+// findStateCycle returns the first cycle found between states, where a state
+// leads to every state emitted by the steps it triggers.
+func findStateCycle(triggers map[string][]string, steps map[string]*dto.StepDefinition) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	color := make(map[string]int, len(triggers))
+	var path []string
+
+	var visit func(state string) []string
+	visit = func(state string) []string {
+		color[state] = visiting
+		path = append(path, state)
+
+		for _, name := range triggers[state] {
+			step, ok := steps[name]
+			if !ok {
+				continue
+			}
+
+			for _, next := range step.Emits {
+				if _, ok := triggers[next]; !ok {
+					continue
+				}
+
+				switch color[next] {
+				case visiting:
+					for i, s := range path {
+						if s == next {
+							return append(append([]string{}, path[i:]...), next)
+						}
+					}
+				case unvisited:
+					if cycle := visit(next); cycle != nil {
+						return cycle
+					}
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		color[state] = done
+		return nil
+	}
+
+	states := make([]string, 0, len(triggers))
+	for state := range triggers {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	for _, state := range states {
+		if color[state] == unvisited {
+			if cycle := visit(state); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// Import stores the definition as a new version of the workflow. Instances
+// already running keep resolving their steps against the version they
+// started on.
+func (d *DefinitionUsecase) Import(ctx context.Context, def *dto.WorkflowDefinition, topics []string) error {
+	err := ValidateDefinition(def, topics)
+	if err != nil {
+		return err
+	}
+
+	// a child workflow has to exist before a parent can start it
+	for _, step := range def.Steps {
+		if step.Type != dto.StepWorkflow {
+			continue
+		}
+
+		_, err := d.queries.FindWorkflowByType(ctx, step.Workflow)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w:\nstep %s: unknown workflow %q", ErrInvalidDefinition, step.Name, step.Workflow)
+		}
+		if err != nil {
+			return fmt.Errorf("find workflow %s: %w", step.Workflow, err)
+		}
+	}
+
+	return d.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		wf, err := q.UpsertWorkflow(ctx, sqlc.UpsertWorkflowParams{
+			Type:        def.Type,
+			Description: def.Description,
+		})
+
+		if err != nil {
+			return fmt.Errorf("upsert workflow: %w", err)
+		}
+
+		ids := make(map[string]int32, len(def.Steps))
+		for _, step := range def.Steps {
+			id, err := d.createStep(ctx, q, step, wf.Version)
+			if err != nil {
+				return fmt.Errorf("create step %s: %w", step.Name, err)
+			}
+			ids[step.Name] = id
+
+			for _, key := range step.PayloadKeys {
+				err = q.CreatePayloadKey(ctx, sqlc.CreatePayloadKeyParams{
+					StepID: id,
+					Key:    key,
+				})
+
+				if err != nil {
+					return fmt.Errorf("create payload key %s: %w", step.Name, err)
+				}
+			}
+
+			for _, mapping := range step.Mappings {
+				defaultValue, err := json.Marshal(mapping.Default)
+				if err != nil {
+					return fmt.Errorf("marshal mapping default %s: %w", step.Name, err)
+				}
+
+				err = q.CreatePayloadMapping(ctx, sqlc.CreatePayloadMappingParams{
+					StepID:       id,
+					Target:       mapping.Target,
+					Source:       mapping.Source,
+					DefaultValue: defaultValue,
+					Required:     mapping.Required,
+				})
+
+				if err != nil {
+					return fmt.Errorf("create payload mapping %s: %w", step.Name, err)
+				}
+			}
+
+			for _, state := range step.Requires {
+				err = q.CreateStepDependency(ctx, sqlc.CreateStepDependencyParams{
+					StepID: id,
+					State:  state,
+				})
+
+				if err != nil {
+					return fmt.Errorf("create step dependency %s: %w", step.Name, err)
+				}
+			}
+		}
+
+		for _, step := range def.Steps {
+			if step.Compensation == "" {
+				continue
+			}
+
+			err = q.UpdateStepCompensation(ctx, sqlc.UpdateStepCompensationParams{
+				CompensationStepID: sql.NullInt32{Int32: ids[step.Compensation], Valid: true},
+				ID:                 ids[step.Name],
+			})
+
+			if err != nil {
+				return fmt.Errorf("update compensation %s: %w", step.Name, err)
+			}
+		}
+
+		for _, state := range def.States {
+			for _, name := range state.Steps {
+				err = q.CreateStateAction(ctx, sqlc.CreateStateActionParams{
+					Type:    def.Type,
+					Version: wf.Version,
+					State:   state.State,
+					StepID:  ids[name],
+					Guard:   state.Guards[name],
+				})
+
+				if err != nil {
+					return fmt.Errorf("create state action %s: %w", state.State, err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// validateOrchestratorStep checks a step the orchestrator runs itself, which
+// publishes nothing and replies as orchestra-svc.
+func validateOrchestratorStep(step *dto.StepDefinition) []error {
+	var errs []error
+
+	if step.Topic != "" {
+		errs = append(errs, fmt.Errorf("step %s: %s steps have no topic", step.Name, step.Type))
+	}
+
+	if step.Service == "" {
+		step.Service = dto.OrchestratorService
+	} else if step.Service != dto.OrchestratorService {
+		errs = append(errs, fmt.Errorf("step %s: %s steps are run by %s", step.Name, step.Type, dto.OrchestratorService))
+	}
+
+	if step.Retry != nil {
+		errs = append(errs, fmt.Errorf("step %s: %s steps cannot be retried", step.Name, step.Type))
+	}
+
+	if step.TimeoutSeconds != 0 {
+		errs = append(errs, fmt.Errorf("step %s: %s steps have no timeout", step.Name, step.Type))
+	}
+
+	return errs
+}
+
+func validateRetry(name string, retry *dto.RetryDefinition) []error {
+	var errs []error
+
+	if retry.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("step %s: retry max_attempts must be at least 1", name))
+	}
+
+	if retry.BaseDelayMs < 0 {
+		errs = append(errs, fmt.Errorf("step %s: retry base_delay_ms must not be negative", name))
+	}
+
+	for _, code := range retry.StatusCodes {
+		if code < 100 || code > 599 {
+			errs = append(errs, fmt.Errorf("step %s: invalid retry status code %d", name, code))
+		}
+	}
+
+	return errs
+}
+
+func validateGuards(state dto.StateDefinition) []error {
+	var errs []error
+
+	for _, name := range state.Steps {
+		expr, ok := state.Guards[name]
+		if !ok {
+			continue
+		}
+
+		if _, err := guard.Parse(expr); err != nil {
+			errs = append(errs, fmt.Errorf("state %s: guard of step %s: %w", state.State, name, err))
+		}
+	}
+
+	var unknown []string
+	for name := range state.Guards {
+		if !slices.Contains(state.Steps, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		errs = append(errs, fmt.Errorf("state %s: guard for step %q it does not trigger", state.State, name))
+	}
+
+	return errs
+}
+
+func validateMappings(name string, mappings []dto.MappingDefinition) []error {
+	var errs []error
+
+	targets := make(map[string]bool, len(mappings))
+	for i, mapping := range mappings {
+		if mapping.Target == "" || mapping.Source == "" {
+			errs = append(errs, fmt.Errorf("step %s: mapping %d needs a target and a source", name, i))
+			continue
+		}
+
+		if _, err := jsonpath.Parse(mapping.Source); err != nil {
+			errs = append(errs, fmt.Errorf("step %s: mapping source: %w", name, err))
+		}
+
+		target, err := jsonpath.Parse(mapping.Target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("step %s: mapping target: %w", name, err))
+		} else if target.HasIndex() {
+			errs = append(errs, fmt.Errorf("step %s: mapping target %q must not contain an index", name, mapping.Target))
+		}
+
+		if targets[mapping.Target] {
+			errs = append(errs, fmt.Errorf("step %s: mapping target %q defined more than once", name, mapping.Target))
+		}
+		targets[mapping.Target] = true
+	}
+
+	return errs
+}
+
+func (d *DefinitionUsecase) createStep(ctx context.Context, q sqlc.Querier, step dto.StepDefinition, version int32) (int32, error) {
+	if step.OnTimeout == "" {
+		step.OnTimeout = dto.TimeoutFail
+	}
+
+	if step.Type == "" {
+		step.Type = dto.StepService
+	}
+
+	retry := dto.RetryDefinition{MaxAttempts: 1, BaseDelayMs: 1000}
+	if step.Retry != nil {
+		retry = *step.Retry
+	}
+
+	if len(retry.StatusCodes) == 0 {
+		retry.StatusCodes = dto.DefaultRetryableStatusCodes
+	}
+
+	created, err := q.CreateStep(ctx, sqlc.CreateStepParams{
+		Name:                 step.Name,
+		Description:          step.Description,
+		Service:              step.Service,
+		Topic:                step.Topic,
+		Emits:                step.Emits,
+		TimeoutSeconds:       step.TimeoutSeconds,
+		TimeoutAction:        step.OnTimeout,
+		MaxAttempts:          retry.MaxAttempts,
+		RetryBaseDelayMs:     retry.BaseDelayMs,
+		RetryableStatusCodes: retry.StatusCodes,
+		Version:              version,
+		Type:                 step.Type,
+		DelaySeconds:         step.DelaySeconds,
+		ExpireSeconds:        step.ExpireSeconds,
+		ChildWorkflow:        step.Workflow,
+		ChildStartState:      step.StartState,
+	})
+	return created.ID, err
+}
+
+func (d *DefinitionUsecase) Export(ctx context.Context, workflowType string) (*dto.WorkflowDefinition, error) {
+	wf, err := d.queries.FindWorkflowByType(ctx, workflowType)
+	if err != nil {
+		return nil, fmt.Errorf("find workflow: %w", err)
+	}
+
+	return d.exportVersion(ctx, wf)
+}
+
+// exportVersion reads the definition as it was at wf.Version.
+func (d *DefinitionUsecase) exportVersion(ctx context.Context, wf sqlc.Workflow) (*dto.WorkflowDefinition, error) {
+	steps, err := d.queries.FindStepsByWorkflowType(ctx, sqlc.FindStepsByWorkflowTypeParams{
+		Type:    wf.Type,
+		Version: wf.Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find steps: %w", err)
+	}
+
+	actions, err := d.queries.FindStateActionsByType(ctx, sqlc.FindStateActionsByTypeParams{
+		Type:    wf.Type,
+		Version: wf.Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find state actions: %w", err)
+	}
+
+	names := make(map[int32]string, len(steps))
+	for _, step := range steps {
+		names[step.ID] = step.Name
+	}
+
+	def := &dto.WorkflowDefinition{
+		Type:        wf.Type,
+		Description: wf.Description,
+		Version:     wf.Version,
+	}
+
+	emitted := make(map[string]bool)
+	for _, step := range steps {
+		keys, err := d.queries.FindPayloadKeysByStepID(ctx, step.ID)
+		if err != nil {
+			return nil, fmt.Errorf("find payload keys %s: %w", step.Name, err)
+		}
+
+		requires, err := d.queries.FindStepDependenciesByStepID(ctx, step.ID)
+		if err != nil {
+			return nil, fmt.Errorf("find step dependencies %s: %w", step.Name, err)
+		}
+
+		mappings, err := d.queries.FindPayloadMappingsByStepID(ctx, step.ID)
+		if err != nil {
+			return nil, fmt.Errorf("find payload mappings %s: %w", step.Name, err)
+		}
+
+		sd := dto.StepDefinition{
+			Name:        step.Name,
+			Description: step.Description,
+			Service:     step.Service,
+			Topic:       step.Topic,
+			PayloadKeys: keys,
+			Requires:    requires,
+			Emits:       step.Emits,
+		}
+
+		for _, mapping := range mappings {
+			md := dto.MappingDefinition{
+				Target:   mapping.Target,
+				Source:   mapping.Source,
+				Required: mapping.Required,
+			}
+
+			err = json.Unmarshal(mapping.DefaultValue, &md.Default)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshal mapping default %s: %w", step.Name, err)
+			}
+
+			sd.Mappings = append(sd.Mappings, md)
+		}
+
+		if step.Type != dto.StepService {
+			sd.Type = step.Type
+			sd.DelaySeconds = step.DelaySeconds
+			sd.ExpireSeconds = step.ExpireSeconds
+			sd.Workflow = step.ChildWorkflow
+			sd.StartState = step.ChildStartState
+		}
+
+		if step.CompensationStepID.Valid {
+			sd.Compensation = names[step.CompensationStepID.Int32]
+		}
+
+		if step.TimeoutSeconds > 0 {
+			sd.TimeoutSeconds = step.TimeoutSeconds
+			sd.OnTimeout = step.TimeoutAction
+		}
+
+		if step.MaxAttempts > 1 {
+			sd.Retry = &dto.RetryDefinition{
+				MaxAttempts: step.MaxAttempts,
+				BaseDelayMs: step.RetryBaseDelayMs,
+				StatusCodes: step.RetryableStatusCodes,
+			}
+		}
+
+		for _, state := range step.Emits {
+			emitted[state] = true
+		}
+
+		def.Steps = append(def.Steps, sd)
+	}
+
+	index := make(map[string]int)
+	for _, action := range actions {
+		i, ok := index[action.State]
+		if !ok {
+			i = len(def.States)
+			index[action.State] = i
+			def.States = append(def.States, dto.StateDefinition{State: action.State})
+		}
+		def.States[i].Steps = append(def.States[i].Steps, action.StepName)
+
+		if action.Guard != "" {
+			if def.States[i].Guards == nil {
+				def.States[i].Guards = make(map[string]string)
+			}
+			def.States[i].Guards[action.StepName] = action.Guard
+		}
+	}
+
+	// states no step emits can only be started from outside the orchestrator
+	for _, state := range def.States {
+		if !emitted[state.State] && state.State != dto.WorkflowAborted {
+			def.InitialStates = append(def.InitialStates, state.State)
+		}
+	}
+
+	return def, nil
+}
+
+func (d *DefinitionUsecase) ExportAll(ctx context.Context) ([]dto.WorkflowDefinition, error) {
+	workflows, err := d.queries.ListWorkflows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list workflows: %w", err)
+	}
+
+	defs := make([]dto.WorkflowDefinition, 0, len(workflows))
+	for _, wf := range workflows {
+		def, err := d.Export(ctx, wf.Type)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, *def)
+	}
+
+	return defs, nil
+}
@@ -0,0 +1,426 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"orchestra-svc/internal/dto"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var definitionTopics = []string{"user-topic", "product-topic", "order-topic"}
+
+func newTestDefinition() *dto.WorkflowDefinition {
+	return &dto.WorkflowDefinition{
+		Type:          "order_process",
+		Description:   "order process",
+		InitialStates: []string{"order_created"},
+		Steps: []dto.StepDefinition{
+			{Name: "user_validation", Service: "user-svc", Topic: "user-topic", PayloadKeys: []string{"order-svc"}},
+			{Name: "product_reservation", Service: "product-svc", Topic: "product-topic", PayloadKeys: []string{"order-svc"}, Compensation: "product_release"},
+			{Name: "product_release", Service: "product-svc", Topic: "product-topic"},
+			{Name: "order_update", Service: "order-svc", Topic: "order-topic", PayloadKeys: []string{"order-svc"}, Emits: []string{"order_updated"}},
+		},
+		States: []dto.StateDefinition{
+			{State: "order_created", Steps: []string{"user_validation"}},
+			{State: "user_validation_success", Steps: []string{"product_reservation"}},
+			{State: "product_reservation_success", Steps: []string{"order_update"}},
+			{State: "product_release_success", Steps: []string{"order_update"}},
+		},
+	}
+}
+
+func TestValidateDefinition(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mutate   func(def *dto.WorkflowDefinition)
+		expected []string
+	}{
+		{
+			name:   "Valid definition",
+			mutate: func(def *dto.WorkflowDefinition) {},
+		},
+		{
+			name: "Aborted state is reachable",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.States = append(def.States, dto.StateDefinition{State: dto.WorkflowAborted, Steps: []string{"order_update"}})
+			},
+		},
+		{
+			name: "Unknown topic",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[0].Topic = "users-topic"
+			},
+			expected: []string{`step user_validation: unknown topic "users-topic"`},
+		},
+		{
+			name: "Step without payload keys",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[1].PayloadKeys = nil
+			},
+			expected: []string{"step product_reservation: no payload keys"},
+		},
+		{
+			name: "Step with mappings only",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[1].PayloadKeys = nil
+				def.Steps[1].Mappings = []dto.MappingDefinition{{Target: "ref_id", Source: "$.order-svc.ref_id", Required: true}}
+			},
+		},
+		{
+			name: "Invalid mappings",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[0].Mappings = []dto.MappingDefinition{
+					{Target: "items[0]", Source: "$.order-svc.items"},
+					{Target: "amount", Source: "$.product-svc..amount"},
+					{Target: "amount", Source: "$.order-svc.amount"},
+					{Source: "$.order-svc.ref_id"},
+				}
+			},
+			expected: []string{
+				`step user_validation: mapping target "items[0]" must not contain an index`,
+				"step user_validation: mapping source: invalid json path",
+				`step user_validation: mapping target "amount" defined more than once`,
+				"step user_validation: mapping 3 needs a target and a source",
+			},
+		},
+		{
+			name: "Unknown timeout action",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[0].TimeoutSeconds = 30
+				def.Steps[0].OnTimeout = "skip"
+			},
+			expected: []string{`step user_validation: unknown on_timeout "skip"`},
+		},
+		{
+			name: "Invalid retry policy",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[2].Retry = &dto.RetryDefinition{MaxAttempts: 0, StatusCodes: []int32{700}}
+			},
+			expected: []string{
+				"step product_release: retry max_attempts must be at least 1",
+				"step product_release: invalid retry status code 700",
+			},
+		},
+		{
+			name: "Delay step",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps = append(def.Steps, dto.StepDefinition{Name: "cooling_off", Type: dto.StepDelay, DelaySeconds: 900, Emits: []string{"cooling_off_done"}})
+				def.States[1].Steps = []string{"cooling_off"}
+				def.States = append(def.States, dto.StateDefinition{State: "cooling_off_done", Steps: []string{"product_reservation"}})
+			},
+		},
+		{
+			name: "Approval step",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps = append(def.Steps, dto.StepDefinition{Name: "manual_review", Type: dto.StepApproval, ExpireSeconds: 3600})
+				def.States[1].Steps = []string{"manual_review"}
+				def.States = append(def.States,
+					dto.StateDefinition{State: "approved", Steps: []string{"product_reservation"}},
+					dto.StateDefinition{State: "rejected", Steps: []string{"order_update"}},
+				)
+			},
+		},
+		{
+			name: "Invalid approval steps",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[0].ExpireSeconds = 60
+				def.Steps = append(def.Steps,
+					dto.StepDefinition{Name: "manual_review", Type: dto.StepApproval, Emits: []string{"approved"}, TimeoutSeconds: 60},
+					dto.StepDefinition{Name: "risk_review", Type: dto.StepApproval, ExpireSeconds: -1, Service: "user-svc"},
+				)
+				def.States[1].Steps = []string{"manual_review", "risk_review"}
+			},
+			expected: []string{
+				"step user_validation: expire_seconds is only for approval steps",
+				"step manual_review: approval steps emit exactly two states",
+				"step manual_review: approval steps have no timeout",
+				"step risk_review: expire_seconds must not be negative",
+				"step risk_review: approval steps are run by orchestra-svc",
+			},
+		},
+		{
+			name: "Workflow step",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps = append(def.Steps, dto.StepDefinition{Name: "bank_registration", Type: dto.StepWorkflow, Workflow: "bank_account_registration", StartState: "bank_regis_created", PayloadKeys: []string{"order-svc"}, Emits: []string{"bank_registered", "bank_registration_failed"}})
+				def.States[1].Steps = []string{"bank_registration"}
+				def.States = append(def.States,
+					dto.StateDefinition{State: "bank_registered", Steps: []string{"product_reservation"}},
+					dto.StateDefinition{State: "bank_registration_failed", Steps: []string{"order_update"}},
+				)
+			},
+		},
+		{
+			name: "Invalid workflow steps",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[0].StartState = "bank_regis_created"
+				def.Steps = append(def.Steps,
+					dto.StepDefinition{Name: "bank_registration", Type: dto.StepWorkflow, Emits: []string{"bank_registered"}},
+					dto.StepDefinition{Name: "reorder", Type: dto.StepWorkflow, Workflow: "order_process", StartState: "order_created", Emits: []string{"reordered", "reorder_failed"}},
+				)
+				def.States[1].Steps = []string{"bank_registration", "reorder"}
+			},
+			expected: []string{
+				"step user_validation: workflow and start_state are only for workflow steps",
+				"step bank_registration: workflow is required",
+				"step bank_registration: start_state is required",
+				"step bank_registration: workflow steps emit exactly two states",
+				"step reorder: workflow must not start itself",
+			},
+		},
+		{
+			name: "Invalid step types",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[0].Type = "manual"
+				def.Steps[1].Compensation = "cooling_off"
+				def.Steps[2].DelaySeconds = 60
+				def.Steps = append(def.Steps, dto.StepDefinition{Name: "cooling_off", Type: dto.StepDelay, Topic: "order-topic", Emits: []string{"order_created"}})
+			},
+			expected: []string{
+				`step user_validation: unknown type "manual"`,
+				"step product_release: delay_seconds is only for delay steps",
+				"step cooling_off: delay steps have no topic",
+				"step cooling_off: delay_seconds must be positive",
+				`step product_reservation: compensation step "cooling_off" must be a service step`,
+			},
+		},
+		{
+			name: "Guarded steps",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.States[1].Guards = map[string]string{"product_reservation": `$.order-svc.type != "digital"`}
+			},
+		},
+		{
+			name: "Invalid guards",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.States[1].Guards = map[string]string{
+					"product_reservation": "$.order-svc.amount >",
+					"order_update":        "$.order-svc.amount > 1000",
+				}
+			},
+			expected: []string{
+				"state user_validation_success: guard of step product_reservation: invalid guard",
+				`state user_validation_success: guard for step "order_update" it does not trigger`,
+			},
+		},
+		{
+			name: "Unreachable state",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.States = append(def.States, dto.StateDefinition{State: "payment_success", Steps: []string{"order_update"}})
+			},
+			expected: []string{"state payment_success: unreachable from initial states"},
+		},
+		{
+			name: "Unknown step",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.States[0].Steps = []string{"user_check"}
+			},
+			expected: []string{`state order_created: unknown step "user_check"`},
+		},
+		{
+			name: "Join step triggered by every required state",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.States[0].Steps = []string{"user_validation", "product_reservation"}
+				def.States[1].Steps = []string{"order_update"}
+				def.Steps[3].Requires = []string{"user_validation_success", "product_reservation_success"}
+			},
+		},
+		{
+			name: "Join step not triggered by a required state",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[3].Requires = []string{"user_validation_success", "product_reservation_success"}
+			},
+			expected: []string{`step order_update: required state "user_validation_success" does not trigger it`},
+		},
+		{
+			name: "Cycle between states",
+			mutate: func(def *dto.WorkflowDefinition) {
+				def.Steps[3].Emits = []string{"order_created"}
+			},
+			expected: []string{"cycle detected: order_created -> user_validation_success -> product_reservation_success -> order_created"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			def := newTestDefinition()
+			tc.mutate(def)
+
+			err := ValidateDefinition(def, definitionTopics)
+
+			if len(tc.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidDefinition)
+			for _, msg := range tc.expected {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestValidateDefinition_DefaultEmits(t *testing.T) {
+	def := newTestDefinition()
+
+	err := ValidateDefinition(def, definitionTopics)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user_validation_success", "user_validation_failed"}, def.Steps[0].Emits)
+	assert.Equal(t, []string{"order_updated"}, def.Steps[3].Emits)
+}
+
+func TestDefinitionUsecase_Import_InvalidDefinition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dc := NewDefinitionUsecase(store)
+
+	def := newTestDefinition()
+	def.Type = ""
+
+	err := dc.Import(context.Background(), def, definitionTopics)
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+}
+
+func TestDefinitionUsecase_Import_UnknownChildWorkflow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dc := NewDefinitionUsecase(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowByType(ctx, "bank_account_registration").Return(sqlc.Workflow{}, sql.ErrNoRows)
+
+	def := newTestDefinition()
+	def.Steps = append(def.Steps, dto.StepDefinition{Name: "bank_registration", Type: dto.StepWorkflow, Workflow: "bank_account_registration", StartState: "bank_regis_created", Emits: []string{"bank_registered", "bank_registration_failed"}})
+	def.States[0].Steps = []string{"bank_registration"}
+	def.States = append(def.States, dto.StateDefinition{State: "bank_registered", Steps: []string{"user_validation"}})
+
+	err := dc.Import(ctx, def, definitionTopics)
+	assert.ErrorIs(t, err, ErrInvalidDefinition)
+	assert.ErrorContains(t, err, `step bank_registration: unknown workflow "bank_account_registration"`)
+}
+
+func TestDefinitionUsecase_Import_RollsBackOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dc := NewDefinitionUsecase(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().ExecTx(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, fn func(sqlc.Querier) error) error {
+		return fn(store)
+	})
+	store.EXPECT().UpsertWorkflow(ctx, sqlc.UpsertWorkflowParams{
+		Type:        "order_process",
+		Description: "order process",
+	}).Return(sqlc.Workflow{ID: 1, Version: 1}, nil)
+	store.EXPECT().CreateStep(ctx, gomock.Any()).Return(sqlc.Step{}, fmt.Errorf("error"))
+
+	err := dc.Import(ctx, newTestDefinition(), definitionTopics)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrInvalidDefinition))
+}
+
+func TestDefinitionUsecase_Import_CreatesNewVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dc := NewDefinitionUsecase(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().ExecTx(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, fn func(sqlc.Querier) error) error {
+		return fn(store)
+	})
+	store.EXPECT().UpsertWorkflow(ctx, gomock.Any()).Return(sqlc.Workflow{ID: 1, Type: "order_process", Version: 3}, nil)
+
+	var nextID int32 = 10
+	store.EXPECT().CreateStep(ctx, gomock.Any()).Times(4).DoAndReturn(func(_ context.Context, arg sqlc.CreateStepParams) (sqlc.Step, error) {
+		assert.Equal(t, int32(3), arg.Version)
+		nextID++
+		return sqlc.Step{ID: nextID, Name: arg.Name, Version: arg.Version}, nil
+	})
+	store.EXPECT().CreatePayloadKey(ctx, gomock.Any()).Times(3).Return(nil)
+	store.EXPECT().CreatePayloadMapping(ctx, sqlc.CreatePayloadMappingParams{
+		StepID:       14,
+		Target:       "status",
+		Source:       "$.order-svc.status",
+		DefaultValue: []byte(`"PENDING"`),
+	}).Return(nil)
+	store.EXPECT().UpdateStepCompensation(ctx, sqlc.UpdateStepCompensationParams{
+		CompensationStepID: sql.NullInt32{Int32: 13, Valid: true},
+		ID:                 12,
+	}).Return(nil)
+	store.EXPECT().CreateStateAction(ctx, sqlc.CreateStateActionParams{Type: "order_process", Version: 3, State: "order_created", StepID: 11}).Return(nil)
+	store.EXPECT().CreateStateAction(ctx, sqlc.CreateStateActionParams{Type: "order_process", Version: 3, State: "user_validation_success", StepID: 12, Guard: "$.order-svc.amount > 0"}).Return(nil)
+	store.EXPECT().CreateStateAction(ctx, sqlc.CreateStateActionParams{Type: "order_process", Version: 3, State: "product_reservation_success", StepID: 14}).Return(nil)
+	store.EXPECT().CreateStateAction(ctx, sqlc.CreateStateActionParams{Type: "order_process", Version: 3, State: "product_release_success", StepID: 14}).Return(nil)
+
+	def := newTestDefinition()
+	def.Steps[3].Mappings = []dto.MappingDefinition{{Target: "status", Source: "$.order-svc.status", Default: "PENDING"}}
+	def.States[1].Guards = map[string]string{"product_reservation": "$.order-svc.amount > 0"}
+
+	err := dc.Import(ctx, def, definitionTopics)
+	assert.NoError(t, err)
+}
+
+func TestDefinitionUsecase_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dc := NewDefinitionUsecase(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{Type: "order_process", Description: "order process", Version: 2}, nil)
+	store.EXPECT().FindStepsByWorkflowType(ctx, sqlc.FindStepsByWorkflowTypeParams{Type: "order_process", Version: 2}).Return([]sqlc.Step{
+		{ID: 1, Name: "user_validation", Service: "user-svc", Topic: "user-topic", Emits: []string{"user_validation_success"}},
+		{ID: 2, Name: "product_reservation", Service: "product-svc", Topic: "product-topic", Emits: []string{"product_reservation_success"}, CompensationStepID: sql.NullInt32{Int32: 3, Valid: true}},
+		{ID: 3, Name: "product_release", Service: "product-svc", Topic: "product-topic", Emits: []string{"product_release_success"}},
+	}, nil)
+	store.EXPECT().FindStateActionsByType(ctx, sqlc.FindStateActionsByTypeParams{Type: "order_process", Version: 2}).Return([]sqlc.FindStateActionsByTypeRow{
+		{State: "order_created", StepName: "user_validation"},
+		{State: "user_validation_success", StepName: "product_reservation", Guard: "$.order-svc.amount > 0"},
+	}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(1)).Return([]string{"order-svc"}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(2)).Return([]string{"order-svc"}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(3)).Return([]string{}, nil)
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(1)).Return([]string{}, nil)
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(2)).Return([]string{"order_created"}, nil)
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(3)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(1)).Return([]sqlc.PayloadMapping{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(2)).Return([]sqlc.PayloadMapping{
+		{StepID: 2, Target: "amount", Source: "$.product-svc.amount", DefaultValue: []byte("0"), Required: true},
+	}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(3)).Return([]sqlc.PayloadMapping{}, nil)
+
+	def, err := dc.Export(ctx, "order_process")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), def.Version)
+	assert.Equal(t, []string{"order_created"}, def.InitialStates)
+	assert.Equal(t, "product_release", def.Steps[1].Compensation)
+	assert.Equal(t, []string{"order_created"}, def.Steps[1].Requires)
+	assert.Equal(t, []dto.MappingDefinition{
+		{Target: "amount", Source: "$.product-svc.amount", Default: float64(0), Required: true},
+	}, def.Steps[1].Mappings)
+	assert.Empty(t, def.Steps[0].Mappings)
+	assert.Equal(t, []dto.StateDefinition{
+		{State: "order_created", Steps: []string{"user_validation"}},
+		{State: "user_validation_success", Steps: []string{"product_reservation"}, Guards: map[string]string{"product_reservation": "$.order-svc.amount > 0"}},
+	}, def.States)
+}
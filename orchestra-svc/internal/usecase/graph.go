@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"orchestra-svc/internal/dto"
+	"strings"
+)
+
+const (
+	GraphMermaid = "mermaid"
+	GraphDot     = "dot"
+)
+
+var (
+	ErrWorkflowNotFound   = errors.New("workflow not found")
+	ErrUnknownGraphFormat = errors.New("unknown graph format, use mermaid or dot")
+)
+
+// instancePath is what an instance went through: the states it logged and
+// the latest status of every step it ran.
+type instancePath struct {
+	states map[string]bool
+	steps  map[string]string
+}
+
+// Graph renders the latest version of a workflow with its states, the steps
+// they trigger and the compensation of each step.
+func (d *DefinitionUsecase) Graph(ctx context.Context, workflowType, format string) (string, error) {
+	if !isGraphFormat(format) {
+		return "", ErrUnknownGraphFormat
+	}
+
+	def, err := d.Export(ctx, workflowType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrWorkflowNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return renderGraph(def, format, nil), nil
+}
+
+// InstanceGraph renders the version an instance is pinned to and highlights
+// the states it reached and its steps coloured by status.
+func (d *DefinitionUsecase) InstanceGraph(ctx context.Context, instanceID, format string) (string, error) {
+	if !isGraphFormat(format) {
+		return "", ErrUnknownGraphFormat
+	}
+
+	instance, err := d.queries.FindWorkflowInstanceWithType(ctx, instanceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrInstanceNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("find instance: %w", err)
+	}
+
+	wf, err := d.queries.FindWorkflowByType(ctx, instance.Type)
+	if err != nil {
+		return "", fmt.Errorf("find workflow: %w", err)
+	}
+	wf.Version = instance.WorkflowVersion
+
+	def, err := d.exportVersion(ctx, wf)
+	if err != nil {
+		return "", err
+	}
+
+	steps, err := d.queries.FindInstanceStepsWithStep(ctx, instanceID)
+	if err != nil {
+		return "", fmt.Errorf("find instance steps: %w", err)
+	}
+
+	logs, err := d.queries.FindProcessLogsByInstanceID(ctx, instanceID)
+	if err != nil {
+		return "", fmt.Errorf("find process logs: %w", err)
+	}
+
+	path := &instancePath{
+		states: make(map[string]bool, len(logs)),
+		steps:  make(map[string]string, len(steps)),
+	}
+	for _, entry := range logs {
+		path.states[entry.State] = true
+	}
+	// steps are ordered by start, so the latest attempt wins
+	for _, step := range steps {
+		path.steps[step.StepName] = step.Status
+	}
+
+	return renderGraph(def, format, path), nil
+}
+
+func isGraphFormat(format string) bool {
+	return format == GraphMermaid || format == GraphDot
+}
+
+type graphEdge struct {
+	from, to, label string
+	dashed          bool
+}
+
+type graphNode struct {
+	id, name, label string
+	isState         bool
+}
+
+func renderGraph(def *dto.WorkflowDefinition, format string, path *instancePath) string {
+	var nodes []graphNode
+	var edges []graphEdge
+	seen := make(map[string]bool)
+
+	addState := func(state string) string {
+		id := nodeID("s", state)
+		if !seen[id] {
+			seen[id] = true
+			nodes = append(nodes, graphNode{id: id, name: state, label: state, isState: true})
+		}
+		return id
+	}
+
+	for _, state := range def.InitialStates {
+		addState(state)
+	}
+
+	for _, state := range def.States {
+		from := addState(state.State)
+		for _, name := range state.Steps {
+			edges = append(edges, graphEdge{from: from, to: nodeID("t", name), label: state.Guards[name]})
+		}
+	}
+
+	for _, step := range def.Steps {
+		id := nodeID("t", step.Name)
+		label := fmt.Sprintf("%s\n%s / %s", step.Name, step.Service, step.Topic)
+		switch step.Type {
+		case dto.StepDelay:
+			label = fmt.Sprintf("%s\ndelay %ds", step.Name, step.DelaySeconds)
+		case dto.StepApproval:
+			label = fmt.Sprintf("%s\napproval", step.Name)
+		case dto.StepWorkflow:
+			label = fmt.Sprintf("%s\nworkflow %s", step.Name, step.Workflow)
+		}
+		nodes = append(nodes, graphNode{id: id, name: step.Name, label: label})
+
+		for _, state := range step.Emits {
+			edges = append(edges, graphEdge{from: id, to: addState(state)})
+		}
+
+		if step.Compensation != "" {
+			edges = append(edges, graphEdge{from: id, to: nodeID("t", step.Compensation), label: "compensation", dashed: true})
+		}
+	}
+
+	status := func(n graphNode) string {
+		if path == nil || n.isState {
+			return ""
+		}
+		return path.steps[n.name]
+	}
+	reached := func(n graphNode) bool {
+		return path != nil && n.isState && path.states[n.name]
+	}
+
+	if format == GraphDot {
+		return renderDot(def.Type, nodes, edges, status, reached)
+	}
+	return renderMermaid(nodes, edges, status, reached)
+}
+
+func renderMermaid(nodes []graphNode, edges []graphEdge, status func(graphNode) string, reached func(graphNode) bool) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	for _, n := range nodes {
+		label := strings.ReplaceAll(mermaidEscape(n.label), "\n", "<br/>")
+		if n.isState {
+			fmt.Fprintf(&b, "    %s([\"%s\"])\n", n.id, label)
+		} else {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", n.id, label)
+		}
+	}
+
+	for _, e := range edges {
+		switch {
+		case e.dashed:
+			fmt.Fprintf(&b, "    %s -. %s .-> %s\n", e.from, e.label, e.to)
+		case e.label != "":
+			fmt.Fprintf(&b, "    %s -- \"%s\" --> %s\n", e.from, mermaidEscape(e.label), e.to)
+		default:
+			fmt.Fprintf(&b, "    %s --> %s\n", e.from, e.to)
+		}
+	}
+
+	classes := make(map[string][]string)
+	var order []string
+	for _, n := range nodes {
+		class := statusClass(status(n))
+		if reached(n) {
+			class = "reached"
+		}
+		if class == "" {
+			continue
+		}
+		if _, ok := classes[class]; !ok {
+			order = append(order, class)
+		}
+		classes[class] = append(classes[class], n.id)
+	}
+
+	for _, class := range order {
+		fmt.Fprintf(&b, "    classDef %s %s\n", class, mermaidStyles[class])
+		fmt.Fprintf(&b, "    class %s %s\n", strings.Join(classes[class], ","), class)
+	}
+
+	return b.String()
+}
+
+func renderDot(name string, nodes []graphNode, edges []graphEdge, status func(graphNode) string, reached func(graphNode) bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", name)
+	b.WriteString("    rankdir=LR;\n")
+
+	for _, n := range nodes {
+		attrs := []string{fmt.Sprintf("label=%q", n.label)}
+		if n.isState {
+			attrs = append(attrs, "shape=ellipse")
+		} else {
+			attrs = append(attrs, "shape=box")
+		}
+
+		if class := statusClass(status(n)); class != "" {
+			attrs = append(attrs, "style=filled", fmt.Sprintf("fillcolor=%q", dotColors[class]))
+		}
+		if reached(n) {
+			attrs = append(attrs, "style=bold", fmt.Sprintf("color=%q", dotColors["reached"]))
+		}
+
+		fmt.Fprintf(&b, "    %q [%s];\n", n.id, strings.Join(attrs, ", "))
+	}
+
+	for _, e := range edges {
+		var attrs []string
+		if e.label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", e.label))
+		}
+		if e.dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "    %q -> %q;\n", e.from, e.to)
+		} else {
+			fmt.Fprintf(&b, "    %q -> %q [%s];\n", e.from, e.to, strings.Join(attrs, ", "))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func statusClass(status string) string {
+	switch status {
+	case "":
+		return ""
+	case dto.COMPLETE.String():
+		return "success"
+	case dto.SKIPPED.String():
+		return "skipped"
+	case dto.COMPENSATED.String():
+		return "compensated"
+	case dto.IN_PROGRESS.String(), dto.RETRY_SCHEDULED.String():
+		return "running"
+	}
+
+	if dto.IsFailureStatus(status) {
+		return "failed"
+	}
+	return "running"
+}
+
+var mermaidStyles = map[string]string{
+	"reached":     "stroke:#1565c0,stroke-width:3px",
+	"success":     "fill:#c8e6c9,stroke:#2e7d32",
+	"skipped":     "fill:#eeeeee,stroke:#9e9e9e",
+	"compensated": "fill:#ffe0b2,stroke:#ef6c00",
+	"running":     "fill:#fff9c4,stroke:#f9a825",
+	"failed":      "fill:#ffcdd2,stroke:#c62828",
+}
+
+var dotColors = map[string]string{
+	"reached":     "#1565c0",
+	"success":     "#c8e6c9",
+	"skipped":     "#eeeeee",
+	"compensated": "#ffe0b2",
+	"running":     "#fff9c4",
+	"failed":      "#ffcdd2",
+}
+
+// nodeID keeps letters, digits and underscores so the id is valid in both formats.
+func nodeID(prefix, name string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte('_')
+	for _, r := range name {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRenderGraph_Mermaid(t *testing.T) {
+	def := newTestDefinition()
+	def.States[1].Guards = map[string]string{"product_reservation": `$.order-svc.type != "digital"`}
+	assert.NoError(t, ValidateDefinition(def, definitionTopics))
+
+	graph := renderGraph(def, GraphMermaid, nil)
+
+	assert.Contains(t, graph, "flowchart LR\n")
+	assert.Contains(t, graph, `s_order_created(["order_created"])`)
+	assert.Contains(t, graph, `t_user_validation["user_validation<br/>user-svc / user-topic"]`)
+	assert.Contains(t, graph, "s_order_created --> t_user_validation")
+	assert.Contains(t, graph, "t_user_validation --> s_user_validation_success")
+	assert.Contains(t, graph, `s_user_validation_success -- "$.order-svc.type != #quot;digital#quot;" --> t_product_reservation`)
+	assert.Contains(t, graph, "t_product_reservation -. compensation .-> t_product_release")
+	assert.NotContains(t, graph, "classDef")
+}
+
+func TestRenderGraph_DotWithInstancePath(t *testing.T) {
+	def := newTestDefinition()
+	assert.NoError(t, ValidateDefinition(def, definitionTopics))
+
+	graph := renderGraph(def, GraphDot, &instancePath{
+		states: map[string]bool{"order_created": true, "user_validation_success": true},
+		steps:  map[string]string{"user_validation": "success", "product_reservation": "timeout"},
+	})
+
+	assert.Contains(t, graph, `digraph "order_process" {`)
+	assert.Contains(t, graph, `"t_product_reservation" -> "t_product_release" [label="compensation", style=dashed];`)
+	assert.Contains(t, graph, `"t_user_validation" [label="user_validation\nuser-svc / user-topic", shape=box, style=filled, fillcolor="#c8e6c9"];`)
+	assert.Contains(t, graph, `"t_product_reservation" [label="product_reservation\nproduct-svc / product-topic", shape=box, style=filled, fillcolor="#ffcdd2"];`)
+	assert.Contains(t, graph, `"s_order_created" [label="order_created", shape=ellipse, style=bold, color="#1565c0"];`)
+	assert.Contains(t, graph, `"s_order_updated" [label="order_updated", shape=ellipse];`)
+}
+
+func TestDefinitionUsecase_Graph_UnknownFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dc := NewDefinitionUsecase(mockdb.NewMockStore(ctrl))
+
+	_, err := dc.Graph(context.Background(), "order_process", "svg")
+	assert.ErrorIs(t, err, ErrUnknownGraphFormat)
+}
+
+func TestDefinitionUsecase_Graph_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dc := NewDefinitionUsecase(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{}, sql.ErrNoRows)
+
+	_, err := dc.Graph(ctx, "order_process", GraphMermaid)
+	assert.ErrorIs(t, err, ErrWorkflowNotFound)
+}
+
+func TestDefinitionUsecase_InstanceGraph_UsesPinnedVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dc := NewDefinitionUsecase(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{
+		ID:              "instance-001",
+		Type:            "order_process",
+		WorkflowVersion: 1,
+	}, nil)
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{Type: "order_process", Version: 3}, nil)
+	store.EXPECT().FindStepsByWorkflowType(ctx, sqlc.FindStepsByWorkflowTypeParams{Type: "order_process", Version: 1}).Return([]sqlc.Step{
+		{ID: 1, Name: "user_validation", Service: "user-svc", Topic: "user-topic", Emits: []string{"user_validation_success"}},
+	}, nil)
+	store.EXPECT().FindStateActionsByType(ctx, sqlc.FindStateActionsByTypeParams{Type: "order_process", Version: 1}).Return([]sqlc.FindStateActionsByTypeRow{
+		{State: "order_created", StepName: "user_validation"},
+	}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(1)).Return([]string{"order-svc"}, nil)
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(1)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(1)).Return([]sqlc.PayloadMapping{}, nil)
+	store.EXPECT().FindInstanceStepsWithStep(ctx, "instance-001").Return([]sqlc.FindInstanceStepsWithStepRow{
+		{StepName: "user_validation", Status: dto.IN_PROGRESS.String()},
+	}, nil)
+	store.EXPECT().FindProcessLogsByInstanceID(ctx, "instance-001").Return([]sqlc.ProcessLog{{State: "order_created"}}, nil)
+
+	graph, err := dc.InstanceGraph(ctx, "instance-001", GraphMermaid)
+	assert.NoError(t, err)
+	assert.Contains(t, graph, "class t_user_validation running")
+	assert.Contains(t, graph, "class s_order_created reached")
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+	"orchestra-svc/pkg/guard"
+	"time"
+)
+
+// applyGuards returns the steps whose guard holds for the cached payload.
+// Steps left out are recorded as skipped together with their guard, so
+// operators can see why a branch was not taken.
+func (o *OrchestraUsecase) applyGuards(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, steps []sqlc.FindStepsByTypeAndStateRow, cachePayload map[string]any) ([]sqlc.FindStepsByTypeAndStateRow, []sqlc.FindStepsByTypeAndStateRow, error) {
+	var doc any
+	taken := make([]sqlc.FindStepsByTypeAndStateRow, 0, len(steps))
+	var skipped []sqlc.FindStepsByTypeAndStateRow
+
+	for _, step := range steps {
+		if step.Guard == "" {
+			taken = append(taken, step)
+			continue
+		}
+
+		if doc == nil {
+			var err error
+			doc, err = toJSONDocument(cachePayload)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if evalGuard(step.Guard, doc) {
+			taken = append(taken, step)
+			continue
+		}
+
+		log.Printf("Step %d skipped, guard %q does not hold", step.StepID, step.Guard)
+
+		err := o.skipGuardedStep(ctx, eventMsg, instance, step)
+		if err != nil {
+			return nil, nil, fmt.Errorf("skip step %d: %w", step.StepID, err)
+		}
+
+		skipped = append(skipped, step)
+	}
+
+	return taken, skipped, nil
+}
+
+// continueSkipped runs the steps that follow the success state of each
+// skipped step, as if the step had been sent and had succeeded. The instance
+// is finished when none of them leads anywhere.
+func (o *OrchestraUsecase) continueSkipped(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, skipped []sqlc.FindStepsByTypeAndStateRow, cachePayload map[string]any) error {
+	var continued bool
+	for _, step := range skipped {
+		if len(step.Emits) == 0 {
+			continue
+		}
+
+		steps, err := o.queries.FindStepsByTypeAndState(ctx, sqlc.FindStepsByTypeAndStateParams{
+			Type:    eventMsg.EventType,
+			Version: instance.WorkflowVersion,
+			State:   step.Emits[0],
+		})
+		if err != nil {
+			return fmt.Errorf("find steps after %d: %w", step.StepID, err)
+		}
+
+		if len(steps) == 0 {
+			continue
+		}
+
+		gevent := o.createGlobalEvent(eventMsg, eventMsg.Payload.Request, instance.ID)
+		gevent.Source = step.Service
+		gevent.State = step.Emits[0]
+		gevent.Status = dto.SKIPPED.String()
+
+		// joins waiting on the skipped step count its state as reached
+		err = o.logDB(ctx, gevent)
+		if err != nil {
+			log.Println("Error logging to db: ", err)
+		}
+
+		taken, next, err := o.applyGuards(ctx, gevent, instance, steps, cachePayload)
+		if err != nil {
+			return fmt.Errorf("apply guards: %w", err)
+		}
+
+		if len(taken) == 0 {
+			err = o.continueSkipped(ctx, gevent, instance, next, cachePayload)
+		} else {
+			err = o.runSteps(ctx, gevent, instance, taken, cachePayload)
+		}
+		if err != nil {
+			return err
+		}
+
+		continued = true
+	}
+
+	if !continued {
+		return o.finishSteps(ctx, eventMsg, instance)
+	}
+
+	return nil
+}
+
+// evalGuard treats a guard that no longer parses as false, definitions are
+// validated on import so this only happens for rows edited by hand.
+func evalGuard(expr string, doc any) bool {
+	g, err := guard.Parse(expr)
+	if err != nil {
+		log.Printf("Error parsing guard %q: %v", expr, err)
+		return false
+	}
+
+	return g.Eval(doc)
+}
+
+func (o *OrchestraUsecase) skipGuardedStep(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, step sqlc.FindStepsByTypeAndStateRow) error {
+	gevent := o.createGlobalEvent(eventMsg, nil, instance.ID)
+
+	bytes, err := gevent.ToJSON()
+	if err != nil {
+		return fmt.Errorf("parse message: %w", err)
+	}
+
+	now := time.Now()
+	_, err = o.queries.CreateWorkflowInstanceStep(ctx, sqlc.CreateWorkflowInstanceStepParams{
+		WorkflowInstanceID: instance.ID,
+		EventID:            gevent.EventID,
+		Status:             dto.SKIPPED.String(),
+		StepID:             step.StepID,
+		EventMessage:       sql.NullString{String: string(bytes), Valid: true},
+		StartedAt:          sql.NullTime{Time: now, Valid: true},
+		CompletedAt:        sql.NullTime{Time: now, Valid: true},
+		Guard:              step.Guard,
+		GuardResult:        sql.NullBool{Bool: false, Valid: true},
+	})
+	return err
+}
+
+// guardResult is stored on the steps that were taken, it stays NULL for
+// steps without a guard.
+func guardResult(step sqlc.FindStepsByTypeAndStateRow) sql.NullBool {
+	return sql.NullBool{Bool: true, Valid: step.Guard != ""}
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrchestraUsecase_processSteps_TakesGuardedBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventType:  "order_process",
+		State:      "user_validation_success",
+		InstanceID: "instance-001",
+	}
+	cachePayload := map[string]any{
+		"order-svc": map[string]any{"amount": 2500, "type": "digital"},
+	}
+
+	store.EXPECT().FindStepsByTypeAndState(ctx, gomock.Any()).Return([]sqlc.FindStepsByTypeAndStateRow{
+		{StepID: 2, StepTopic: "product-topic", Guard: `$.order-svc.type != "digital"`},
+		{StepID: 7, StepTopic: "approval-topic", Guard: "$.order-svc.amount > 1000"},
+	}, nil)
+
+	var skipped, taken sqlc.CreateWorkflowInstanceStepParams
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+			if arg.StepID == 2 {
+				skipped = arg
+			} else {
+				taken = arg
+			}
+			return sqlc.WorkflowInstanceStep{}, nil
+		})
+
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(7)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(7)).Return([]string{"order-svc"}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(7)).Return([]sqlc.PayloadMapping{}, nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "approval-topic", arg.Topic)
+			return nil
+		})
+
+	err := uc.processSteps(ctx, eventMsg, sqlc.WorkflowInstance{ID: "instance-001"}, cachePayload)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "skipped", skipped.Status)
+	assert.Equal(t, `$.order-svc.type != "digital"`, skipped.Guard)
+	assert.Equal(t, sql.NullBool{Bool: false, Valid: true}, skipped.GuardResult)
+
+	assert.Equal(t, "in_progress", taken.Status)
+	assert.Equal(t, "$.order-svc.amount > 1000", taken.Guard)
+	assert.Equal(t, sql.NullBool{Bool: true, Valid: true}, taken.GuardResult)
+}
+
+func TestOrchestraUsecase_processSteps_AllBranchesGuardedOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventType:  "order_process",
+		State:      "payment_success",
+		Status:     "success",
+		InstanceID: "instance-001",
+	}
+
+	store.EXPECT().FindStepsByTypeAndState(ctx, gomock.Any()).Return([]sqlc.FindStepsByTypeAndStateRow{
+		{StepID: 6, Guard: "$.order-svc.notify"},
+	}, nil)
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Return(sqlc.WorkflowInstanceStep{}, nil)
+
+	// nothing left to run, so the instance is finished
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "success", StepID: 4},
+		{InstanceStepStatus: "skipped", StepID: 6},
+	}, nil)
+	store.EXPECT().UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{
+		Status: "completed",
+		ID:     "instance-001",
+	}).Return(nil)
+
+	err := uc.processSteps(ctx, eventMsg, sqlc.WorkflowInstance{ID: "instance-001"}, map[string]any{})
+	assert.NoError(t, err)
+}
+
+func TestOrchestraUsecase_processSteps_ContinuesPastGuardedOutSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventType:  "order_process",
+		State:      "payment_success",
+		Status:     "success",
+		InstanceID: "instance-001",
+	}
+
+	store.EXPECT().FindStepsByTypeAndState(ctx, sqlc.FindStepsByTypeAndStateParams{
+		Type:  "order_process",
+		State: "payment_success",
+	}).Return([]sqlc.FindStepsByTypeAndStateRow{
+		{StepID: 6, Service: "notification-svc", Guard: "$.order-svc.notify", Emits: []string{"notify_success"}},
+	}, nil)
+	store.EXPECT().FindStepsByTypeAndState(ctx, sqlc.FindStepsByTypeAndStateParams{
+		Type:  "order_process",
+		State: "notify_success",
+	}).Return([]sqlc.FindStepsByTypeAndStateRow{
+		{StepID: 8, StepTopic: "shipping-topic"},
+	}, nil)
+
+	var created []sqlc.CreateWorkflowInstanceStepParams
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+			created = append(created, arg)
+			return sqlc.WorkflowInstanceStep{}, nil
+		})
+
+	// the skipped step's success state is reached for the steps joining on it
+	store.EXPECT().CreateProcessLog(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateProcessLogParams) error {
+			assert.Equal(t, "notify_success", arg.State)
+			assert.Equal(t, "skipped", arg.Status)
+			return nil
+		})
+
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(8)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(8)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(8)).Return([]sqlc.PayloadMapping{}, nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "shipping-topic", arg.Topic)
+			return nil
+		})
+
+	// the saga is not finished while the next step runs
+	store.EXPECT().UpdateWorkflowInstance(ctx, gomock.Any()).Times(0)
+
+	err := uc.processSteps(ctx, eventMsg, sqlc.WorkflowInstance{ID: "instance-001"}, map[string]any{})
+	assert.NoError(t, err)
+
+	assert.Len(t, created, 2)
+	assert.Equal(t, "skipped", created[0].Status)
+	assert.Equal(t, int32(8), created[1].StepID)
+	assert.Equal(t, "in_progress", created[1].Status)
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"orchestra-svc/internal/repository/sqlc"
+)
+
+var (
+	eventsProcessed  = expvar.NewInt("events_processed")
+	eventsDuplicated = expvar.NewInt("events_duplicate_skipped")
+)
+
+type InboxUsecase struct {
+	queries sqlc.Store
+}
+
+func NewInboxUsecase(queries sqlc.Store) *InboxUsecase {
+	return &InboxUsecase{queries: queries}
+}
+
+// Handle runs fn unless the event was already processed successfully from
+// the same source. Events without an id are always handled.
+//
+// The event is claimed in a transaction that fn gets as a Store and must do
+// all of its writes through, so the event is recorded exactly when fn's
+// changes commit. A second delivery of the same event blocks on the claim
+// until the first one finishes and is then skipped, or runs fn itself when
+// the first one failed and rolled back.
+func (i *InboxUsecase) Handle(ctx context.Context, eventID, source, state string, fn func(context.Context, sqlc.Store) error) error {
+	return i.queries.ExecTxStore(ctx, func(s sqlc.Store) error {
+		if eventID == "" {
+			return fn(ctx, s)
+		}
+
+		claimed, err := s.ClaimProcessedEvent(ctx, sqlc.ClaimProcessedEventParams{
+			EventID: eventID,
+			Source:  source,
+			State:   state,
+		})
+		if err != nil {
+			return err
+		}
+
+		if claimed == 0 {
+			eventsDuplicated.Add(1)
+			log.Printf("Skipping duplicate event %s from %s (%s)", eventID, source, state)
+			return nil
+		}
+
+		if err := fn(ctx, s); err != nil {
+			return err
+		}
+
+		eventsProcessed.Add(1)
+
+		return nil
+	})
+}
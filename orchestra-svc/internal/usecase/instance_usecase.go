@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/cache"
+	"orchestra-svc/internal/repository/sqlc"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var ErrInstanceNotFound = errors.New("workflow instance not found")
+
+type InstanceUsecase struct {
+	queries sqlc.Store
+	cache   cache.PayloadStore
+	// oc is only used for its per-instance lock, recovery rewrites the
+	// state ProcessWorkflow works on
+	oc *OrchestraUsecase
+}
+
+func NewInstanceUsecase(q sqlc.Store, c cache.PayloadStore, oc *OrchestraUsecase) *InstanceUsecase {
+	return &InstanceUsecase{
+		queries: q,
+		cache:   c,
+		oc:      oc,
+	}
+}
+
+func (i *InstanceUsecase) ListInstances(ctx context.Context, req *dto.InstanceListRequest) (*dto.InstanceListResponse, error) {
+	page := max(req.Page, 1)
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	filter := sqlc.CountWorkflowInstancesParams{
+		Type:        sql.NullString{String: req.Type, Valid: req.Type != ""},
+		Status:      sql.NullString{String: req.Status, Valid: req.Status != ""},
+		CreatedFrom: sql.NullTime{Time: req.From, Valid: !req.From.IsZero()},
+		CreatedTo:   sql.NullTime{Time: req.To, Valid: !req.To.IsZero()},
+	}
+
+	total, err := i.queries.CountWorkflowInstances(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("count instances: %w", err)
+	}
+
+	instances, err := i.queries.ListWorkflowInstances(ctx, sqlc.ListWorkflowInstancesParams{
+		Type:        filter.Type,
+		Status:      filter.Status,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+		Limit:       int32(pageSize),
+		Offset:      int32((page - 1) * pageSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list instances: %w", err)
+	}
+
+	return &dto.InstanceListResponse{
+		Instances: instances,
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+	}, nil
+}
+
+func (i *InstanceUsecase) GetInstance(ctx context.Context, id string) (*dto.InstanceDetailResponse, error) {
+	instance, err := i.findInstance(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := i.queries.FindInstanceStepsWithStep(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("find instance steps: %w", err)
+	}
+
+	logs, err := i.queries.FindProcessLogsByInstanceID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("find process logs: %w", err)
+	}
+
+	return &dto.InstanceDetailResponse{
+		Instance:    instance,
+		Steps:       steps,
+		ProcessLogs: logs,
+	}, nil
+}
+
+func (i *InstanceUsecase) GetInstancePayload(ctx context.Context, id string) (map[string]any, error) {
+	_, err := i.findInstance(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := i.cache.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("load payload: %w", err)
+	}
+
+	return payload, nil
+}
+
+func (i *InstanceUsecase) findInstance(ctx context.Context, id string) (sqlc.FindWorkflowInstanceWithTypeRow, error) {
+	instance, err := i.queries.FindWorkflowInstanceWithType(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return instance, ErrInstanceNotFound
+	}
+
+	if err != nil {
+		return instance, fmt.Errorf("find instance: %w", err)
+	}
+
+	return instance, nil
+}
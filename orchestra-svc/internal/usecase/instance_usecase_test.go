@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInstanceUsecase_ListInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	ic := NewInstanceUsecase(store, cache.NewPayloadCache(), nil)
+
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	filter := sqlc.CountWorkflowInstancesParams{
+		Type:        sql.NullString{String: "order_process", Valid: true},
+		CreatedFrom: sql.NullTime{Time: from, Valid: true},
+	}
+
+	store.EXPECT().CountWorkflowInstances(ctx, filter).Return(int64(45), nil)
+	store.EXPECT().ListWorkflowInstances(ctx, sqlc.ListWorkflowInstancesParams{
+		Type:        filter.Type,
+		CreatedFrom: filter.CreatedFrom,
+		Limit:       20,
+		Offset:      40,
+	}).Return([]sqlc.ListWorkflowInstancesRow{{ID: "instance-001", Type: "order_process", Status: "completed"}}, nil)
+
+	response, err := ic.ListInstances(ctx, &dto.InstanceListRequest{
+		Type: "order_process",
+		From: from,
+		Page: 3,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(45), response.Total)
+	assert.Equal(t, 20, response.PageSize)
+	assert.Len(t, response.Instances, 1)
+}
+
+func TestInstanceUsecase_GetInstance_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	ic := NewInstanceUsecase(store, cache.NewPayloadCache(), nil)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{}, sql.ErrNoRows)
+
+	_, err := ic.GetInstance(ctx, "instance-001")
+	assert.ErrorIs(t, err, ErrInstanceNotFound)
+}
+
+func TestInstanceUsecase_GetInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	ic := NewInstanceUsecase(store, cache.NewPayloadCache(), nil)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{ID: "instance-001"}, nil)
+	store.EXPECT().FindInstanceStepsWithStep(ctx, "instance-001").Return([]sqlc.FindInstanceStepsWithStepRow{
+		{EventID: "event-001", StepName: "user_validation"},
+		{EventID: "event-002", StepName: "product_reservation"},
+	}, nil)
+	store.EXPECT().FindProcessLogsByInstanceID(ctx, "instance-001").Return([]sqlc.ProcessLog{{State: "order_created"}}, nil)
+
+	response, err := ic.GetInstance(ctx, "instance-001")
+	assert.NoError(t, err)
+	assert.Equal(t, "instance-001", response.Instance.ID)
+	assert.Len(t, response.Steps, 2)
+	assert.Len(t, response.ProcessLogs, 1)
+}
+
+func TestInstanceUsecase_GetInstancePayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	c := cache.NewPayloadCache()
+	ic := NewInstanceUsecase(store, c, nil)
+
+	ctx := context.Background()
+
+	_, err := c.Append(ctx, "instance-001", "order-svc", map[string]any{"order_id": "O-1"})
+	assert.NoError(t, err)
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{ID: "instance-001"}, nil)
+
+	payload, err := ic.GetInstancePayload(ctx, "instance-001")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"order_id": "O-1"}, payload["order-svc"])
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orchestra-svc/internal/repository/sqlc"
+)
+
+var (
+	// errJoinPending means a join step is still waiting for a required state
+	// or was already emitted by another predecessor.
+	errJoinPending = errors.New("join step is waiting for its predecessors")
+	// errJoinFailed means a sibling branch failed, so the join step will never run.
+	errJoinFailed = errors.New("join step predecessor failed")
+)
+
+// awaitJoin decides whether a step that requires several predecessor states
+// may be emitted for the instance. It locks the instance row so that
+// predecessors replying at the same time cannot both emit the step.
+func (o *OrchestraUsecase) awaitJoin(ctx context.Context, q sqlc.Querier, instanceID string, stepID int32, requires []string) error {
+	_, err := q.LockWorkflowInstance(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("lock instance: %w", err)
+	}
+
+	emitted, err := q.CheckIfInstanceStepExistsForStep(ctx, sqlc.CheckIfInstanceStepExistsForStepParams{
+		WorkflowInstanceID: instanceID,
+		StepID:             stepID,
+	})
+	if err != nil {
+		return fmt.Errorf("check join step: %w", err)
+	}
+
+	if emitted {
+		return errJoinPending
+	}
+
+	failed, err := q.CountFailedInstanceSteps(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("count failed steps: %w", err)
+	}
+
+	if failed > 0 {
+		return errJoinFailed
+	}
+
+	succeeded, err := q.CountSucceededStates(ctx, sqlc.CountSucceededStatesParams{
+		WorkflowInstanceID: instanceID,
+		States:             requires,
+	})
+	if err != nil {
+		return fmt.Errorf("count succeeded states: %w", err)
+	}
+
+	if int(succeeded) < len(requires) {
+		return errJoinPending
+	}
+
+	return nil
+}
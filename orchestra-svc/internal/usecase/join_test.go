@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+)
+
+func TestOrchestraUsecase_processStep_Join(t *testing.T) {
+	requires := []string{"user_validation_success", "product_reservation_success"}
+
+	testCases := []struct {
+		name        string
+		emitted     bool
+		failed      int64
+		succeeded   int64
+		expectedErr error
+	}{
+		{
+			name:        "Waits for the remaining predecessor",
+			succeeded:   1,
+			expectedErr: errJoinPending,
+		},
+		{
+			name:      "Emits once every predecessor succeeded",
+			succeeded: 2,
+		},
+		{
+			name:        "Skips a step another predecessor already emitted",
+			emitted:     true,
+			expectedErr: errJoinPending,
+		},
+		{
+			name:        "Fails fast when a predecessor failed",
+			failed:      1,
+			expectedErr: errJoinFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			passThroughTx(store)
+			uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+			ctx := context.Background()
+			instance := sqlc.WorkflowInstance{ID: "instance-001"}
+			step := sqlc.FindStepsByTypeAndStateRow{StepID: 4, StepTopic: "payment-topic"}
+
+			store.EXPECT().FindStepDependenciesByStepID(ctx, int32(4)).Return(requires, nil)
+			store.EXPECT().FindPayloadKeysByStepID(ctx, int32(4)).Return([]string{}, nil)
+			store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(4)).Return([]sqlc.PayloadMapping{}, nil)
+			store.EXPECT().LockWorkflowInstance(ctx, "instance-001").Return("instance-001", nil)
+			store.EXPECT().CheckIfInstanceStepExistsForStep(ctx, sqlc.CheckIfInstanceStepExistsForStepParams{
+				WorkflowInstanceID: "instance-001",
+				StepID:             4,
+			}).Return(tc.emitted, nil)
+
+			if !tc.emitted {
+				store.EXPECT().CountFailedInstanceSteps(ctx, "instance-001").Return(tc.failed, nil)
+			}
+
+			if !tc.emitted && tc.failed == 0 {
+				store.EXPECT().CountSucceededStates(ctx, sqlc.CountSucceededStatesParams{
+					WorkflowInstanceID: "instance-001",
+					States:             requires,
+				}).Return(tc.succeeded, nil)
+			}
+
+			if tc.expectedErr == nil {
+				store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Return(sqlc.WorkflowInstanceStep{}, nil)
+				store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).Return(nil)
+			}
+
+			err := uc.processStep(ctx, event.GlobalEvent[any, any]{}, instance, step, map[string]any{})
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
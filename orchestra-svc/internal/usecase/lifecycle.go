@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+	"time"
+)
+
+// publishLifecycle enqueues a lifecycle event to the workflow events topic in
+// the transaction of the change it reports. Nothing is published when no
+// topic is configured.
+func (o *OrchestraUsecase) publishLifecycle(ctx context.Context, q sqlc.Querier, le event.LifecycleEvent) error {
+	if o.eventsTopic == "" {
+		return nil
+	}
+
+	bytes, err := le.ToJSON()
+	if err != nil {
+		return fmt.Errorf("parse lifecycle event: %w", err)
+	}
+
+	return enqueueMessage(ctx, q, o.eventsTopic, le.InstanceID, bytes)
+}
+
+func (o *OrchestraUsecase) publishStarted(ctx context.Context, q sqlc.Querier, workflowType string, instance sqlc.WorkflowInstance) error {
+	return o.publishLifecycle(ctx, q, newInstanceEvent(event.LifecycleStarted, workflowType, dto.IN_PROGRESS.String(), instance))
+}
+
+// publishStepCompleted reports a forward step that replied with success. A
+// compensating step succeeding is not progress and is left to publishFinished.
+func (o *OrchestraUsecase) publishStepCompleted(ctx context.Context, q sqlc.Querier, eventMsg event.GlobalEvent[any, any], instanceStep sqlc.WorkflowInstanceStep) error {
+	if o.eventsTopic == "" || eventMsg.Status != dto.COMPLETE.String() || eventMsg.Action == actionCompensate {
+		return nil
+	}
+
+	instance, err := q.FindWorkflowInstanceByID(ctx, eventMsg.InstanceID)
+	if err != nil {
+		return fmt.Errorf("find workflow instance: %w", err)
+	}
+
+	step, err := q.FindStepByID(ctx, instanceStep.StepID)
+	if err != nil {
+		return fmt.Errorf("find step: %w", err)
+	}
+
+	le := event.NewLifecycleEvent(event.LifecycleStepCompleted, eventMsg.EventType, eventMsg.InstanceID, instance.Status)
+	le.Step = &event.LifecycleStep{
+		ID:         step.ID,
+		Name:       step.Name,
+		Service:    step.Service,
+		Status:     eventMsg.Status,
+		StatusCode: int32(eventMsg.StatusCode),
+	}
+	if instanceStep.StartedAt.Valid {
+		le.DurationMs = time.Since(instanceStep.StartedAt.Time).Milliseconds()
+	}
+
+	return o.publishLifecycle(ctx, q, le)
+}
+
+// publishFinished reports the terminal status processDone settled on. A
+// failed instance names its failing step and reports the rollback first, but
+// only once every compensating step it sent has replied with success.
+func (o *OrchestraUsecase) publishFinished(ctx context.Context, q sqlc.Querier, workflowType string, instance sqlc.WorkflowInstance, status string, wfiSteps []sqlc.FindWorkflowInstanceByTypeAndIDRow) error {
+	if o.eventsTopic == "" {
+		return nil
+	}
+
+	if status == "completed" {
+		return o.publishLifecycle(ctx, q, newInstanceEvent(event.LifecycleCompleted, workflowType, status, instance))
+	}
+
+	var failed *sqlc.FindWorkflowInstanceByTypeAndIDRow
+	var compensated, compensationFailed bool
+	for i, value := range wfiSteps {
+		if value.IsCompensation {
+			if value.InstanceStepStatus != dto.COMPLETE.String() {
+				compensationFailed = true
+			}
+			continue
+		}
+
+		if failed == nil && dto.IsFailureStatus(value.InstanceStepStatus) {
+			failed = &wfiSteps[i]
+		}
+
+		if value.InstanceStepStatus == dto.COMPENSATED.String() {
+			compensated = true
+		}
+	}
+
+	if compensated && !compensationFailed {
+		err := o.publishLifecycle(ctx, q, newInstanceEvent(event.LifecycleCompensated, workflowType, dto.COMPENSATED.String(), instance))
+		if err != nil {
+			return err
+		}
+	}
+
+	le := newInstanceEvent(event.LifecycleFailed, workflowType, status, instance)
+
+	if failed != nil {
+		step, err := q.FindStepByID(ctx, failed.StepID)
+		if err != nil {
+			return fmt.Errorf("find step: %w", err)
+		}
+
+		le.Step = &event.LifecycleStep{
+			ID:      step.ID,
+			Name:    step.Name,
+			Service: step.Service,
+			Status:  failed.InstanceStepStatus,
+		}
+	}
+
+	return o.publishLifecycle(ctx, q, le)
+}
+
+// publishAborted reports an instance that finished aborting.
+func (o *OrchestraUsecase) publishAborted(ctx context.Context, q sqlc.Querier, workflowType string, instance sqlc.WorkflowInstance) error {
+	return o.publishLifecycle(ctx, q, newInstanceEvent(event.LifecycleAborted, workflowType, dto.ABORTED.String(), instance))
+}
+
+func newInstanceEvent(eventType, workflowType, status string, instance sqlc.WorkflowInstance) event.LifecycleEvent {
+	le := event.NewLifecycleEvent(eventType, workflowType, instance.ID, status)
+	le.WorkflowVersion = instance.WorkflowVersion
+	le.ParentInstanceID = instance.ParentInstanceID.String
+
+	if instance.CreatedAt.Valid {
+		le.DurationMs = time.Since(instance.CreatedAt.Time).Milliseconds()
+	}
+
+	return le
+}
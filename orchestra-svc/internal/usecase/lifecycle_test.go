@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func expectLifecycleEvents(t *testing.T, store *mockdb.MockStore, events *[]event.LifecycleEvent) {
+	store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "workflow-events", arg.Topic)
+
+			var le event.LifecycleEvent
+			assert.NoError(t, json.Unmarshal([]byte(arg.Payload), &le))
+			assert.Equal(t, le.InstanceID, arg.MessageKey)
+			*events = append(*events, le)
+			return nil
+		}).AnyTimes()
+}
+
+func TestOrchestraUsecase_getOrCreateWorkflowInstance_PublishesStarted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{EventsTopic: "workflow-events"})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		InstanceID: "instance-001",
+		State:      event.ORDER_CREATED.String(),
+	}
+
+	var events []event.LifecycleEvent
+	expectLifecycleEvents(t, store, &events)
+	store.EXPECT().CreateWorkflowInstance(ctx, gomock.Any()).Return(sqlc.WorkflowInstance{ID: "instance-001", WorkflowVersion: 3, Status: "in_progress"}, nil)
+
+	_, err := uc.getOrCreateWorkflowInstance(ctx, eventMsg, sqlc.Workflow{ID: 1, Type: "order_process", Version: 3})
+	assert.NoError(t, err)
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, event.LifecycleSchemaVersion, events[0].SchemaVersion)
+	assert.Equal(t, "started", events[0].Type)
+	assert.Equal(t, "order_process", events[0].WorkflowType)
+	assert.Equal(t, int32(3), events[0].WorkflowVersion)
+	assert.Equal(t, "in_progress", events[0].Status)
+}
+
+func TestOrchestraUsecase_handleInstanceStep_PublishesStepCompleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{EventsTopic: "workflow-events"})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventID:    "event-001",
+		EventType:  "order_process",
+		InstanceID: "instance-001",
+		Status:     "success",
+		StatusCode: 200,
+	}
+
+	var events []event.LifecycleEvent
+	expectLifecycleEvents(t, store, &events)
+	store.EXPECT().FindInstanceStepByEventID(ctx, "event-001").Return(sqlc.WorkflowInstanceStep{
+		EventID:   "event-001",
+		StepID:    2,
+		Status:    "in_progress",
+		StartedAt: sql.NullTime{Time: time.Now().Add(-2 * time.Second), Valid: true},
+	}, nil)
+	store.EXPECT().UpdateWorkflowInstanceStep(ctx, gomock.Any()).Return(nil)
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "compensating"}, nil)
+	store.EXPECT().FindStepByID(ctx, int32(2)).Return(sqlc.Step{ID: 2, Name: "payment", Service: "payment-svc"}, nil)
+
+	err := uc.handleInstanceStep(ctx, eventMsg)
+	assert.NoError(t, err)
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, "step_completed", events[0].Type)
+	assert.Equal(t, "compensating", events[0].Status)
+	assert.Equal(t, &event.LifecycleStep{ID: 2, Name: "payment", Service: "payment-svc", Status: "success", StatusCode: 200}, events[0].Step)
+	assert.GreaterOrEqual(t, events[0].DurationMs, int64(2000))
+
+	// a compensating step replying is not reported as progress
+	eventMsg.EventID = "event-002"
+	eventMsg.Action = "compensate"
+	store.EXPECT().FindInstanceStepByEventID(ctx, "event-002").Return(sqlc.WorkflowInstanceStep{EventID: "event-002", StepID: 5, Status: "in_progress"}, nil)
+	store.EXPECT().UpdateWorkflowInstanceStep(ctx, gomock.Any()).Return(nil)
+
+	err = uc.handleInstanceStep(ctx, eventMsg)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestOrchestraUsecase_processDone_PublishesCompensatedAndFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{EventsTopic: "workflow-events"})
+
+	ctx := context.Background()
+	instance := sqlc.WorkflowInstance{
+		ID:              "instance-001",
+		WorkflowVersion: 2,
+		Status:          "compensating",
+		CreatedAt:       sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+	}
+
+	var events []event.LifecycleEvent
+	expectLifecycleEvents(t, store, &events)
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "compensated", StepID: 1},
+		{InstanceStepStatus: "failed", StepID: 2},
+		{InstanceStepStatus: "success", StepID: 3},
+		{InstanceStepStatus: "success", StepID: 7, IsCompensation: true},
+	}, nil)
+	store.EXPECT().UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{Status: "failed", ID: "instance-001"}).Return(nil)
+	store.EXPECT().FindStepByID(ctx, int32(2)).Return(sqlc.Step{ID: 2, Name: "payment", Service: "payment-svc"}, nil)
+
+	err := uc.processDone(ctx, "order_process", instance)
+	assert.NoError(t, err)
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, "compensated", events[0].Type)
+	assert.Equal(t, "compensated", events[0].Status)
+	assert.Equal(t, "failed", events[1].Type)
+	assert.Equal(t, "failed", events[1].Status)
+	assert.Equal(t, &event.LifecycleStep{ID: 2, Name: "payment", Service: "payment-svc", Status: "failed"}, events[1].Step)
+	assert.GreaterOrEqual(t, events[1].DurationMs, int64(60000))
+}
+
+func TestOrchestraUsecase_finishAbort_PublishesAborted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{EventsTopic: "workflow-events"})
+
+	ctx := context.Background()
+	instance := sqlc.WorkflowInstance{ID: "instance-001", Status: "aborting", WorkflowVersion: 2}
+
+	var events []event.LifecycleEvent
+	expectLifecycleEvents(t, store, &events)
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "compensated", StepID: 1},
+	}, nil)
+	store.EXPECT().TransitionWorkflowInstance(ctx, gomock.Any()).Return(int64(1), nil)
+	store.EXPECT().FindStepsByTypeAndState(ctx, gomock.Any()).Return([]sqlc.FindStepsByTypeAndStateRow{}, nil)
+
+	err := uc.finishAbort(ctx, uc.createAbortEvent("order_process", "instance-001"), instance, map[string]any{})
+	assert.NoError(t, err)
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, "aborted", events[0].Type)
+	assert.Equal(t, "aborted", events[0].Status)
+	assert.Equal(t, "order_process", events[0].WorkflowType)
+}
+
+func TestOrchestraUsecase_processDone_CompensationFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{EventsTopic: "workflow-events"})
+
+	ctx := context.Background()
+	instance := sqlc.WorkflowInstance{ID: "instance-001", Status: "compensating"}
+
+	var events []event.LifecycleEvent
+	expectLifecycleEvents(t, store, &events)
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "compensated", StepID: 1},
+		{InstanceStepStatus: "failed", StepID: 2},
+		{InstanceStepStatus: "error", StepID: 7, IsCompensation: true},
+	}, nil)
+	store.EXPECT().UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{Status: "failed", ID: "instance-001"}).Return(nil)
+	store.EXPECT().FindStepByID(ctx, int32(2)).Return(sqlc.Step{ID: 2, Name: "payment", Service: "payment-svc"}, nil)
+
+	err := uc.processDone(ctx, "order_process", instance)
+	assert.NoError(t, err)
+
+	// the rollback did not go through, only the failure is reported
+	assert.Len(t, events, 1)
+	assert.Equal(t, "failed", events[0].Type)
+	assert.Equal(t, &event.LifecycleStep{ID: 2, Name: "payment", Service: "payment-svc", Status: "failed"}, events[0].Step)
+}
@@ -4,34 +4,71 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/google/uuid"
 	"log"
 	"orchestra-svc/internal/dto"
 	"orchestra-svc/internal/dto/event"
 	"orchestra-svc/internal/repository/cache"
 	"orchestra-svc/internal/repository/sqlc"
 	"orchestra-svc/pkg"
-	"orchestra-svc/pkg/producer"
+	"orchestra-svc/pkg/keylock"
 	"time"
 )
 
 type OrchestraUsecase struct {
-	queries  sqlc.Store
-	producer *producer.KafkaProducer
-	cache    *cache.PayloadCacher
+	queries sqlc.Store
+	cache   cache.PayloadStore
+	// instances serialises the work on one instance: processing a reply,
+	// aborting, resending a step and recovering it from the logs
+	instances *keylock.Locker
+	// topic is the orchestrator's own topic, child workflows are started
+	// and resume their parent through it
+	topic string
+	// eventsTopic receives the lifecycle events of every instance, none are
+	// published when it is empty
+	eventsTopic string
 }
 
-func NewOrchestraUsecase(q sqlc.Store, p *producer.KafkaProducer, c *cache.PayloadCacher) *OrchestraUsecase {
+// OrchestraOptions holds the settings of an OrchestraUsecase, new settings
+// are added here so existing callers keep compiling.
+type OrchestraOptions struct {
+	// Topic is the orchestrator's own topic
+	Topic string
+	// EventsTopic receives the lifecycle events of every instance
+	EventsTopic string
+}
+
+func NewOrchestraUsecase(q sqlc.Store, c cache.PayloadStore, opts OrchestraOptions) *OrchestraUsecase {
 	return &OrchestraUsecase{
-		queries:  q,
-		producer: p,
-		cache:    c,
+		queries:     q,
+		cache:       c,
+		instances:   keylock.New(),
+		topic:       opts.Topic,
+		eventsTopic: opts.EventsTopic,
 	}
 }
 
+// WithStore returns a copy of the usecase that runs its queries and payload
+// writes on s, sharing the instance locks with o. Handing it a store bound to
+// a transaction makes everything it processes commit or roll back together.
+func (o *OrchestraUsecase) WithStore(s sqlc.Store) *OrchestraUsecase {
+	uc := *o
+	uc.queries = s
+	uc.cache = o.cache.WithStore(s)
+	return &uc
+}
+
 func (o *OrchestraUsecase) ProcessWorkflow(ctx context.Context, eventMsg event.GlobalEvent[any, any]) error {
+	unlock := o.instances.Lock(eventMsg.InstanceID)
+	defer unlock()
+
+	return o.processWorkflow(ctx, eventMsg)
+}
 
+// processWorkflow is ProcessWorkflow for callers already holding the
+// instance's lock.
+func (o *OrchestraUsecase) processWorkflow(ctx context.Context, eventMsg event.GlobalEvent[any, any]) error {
 	log.Println("Processing workflow: ", eventMsg.EventType)
 	log.Println("Processing state: ", eventMsg.State)
 
@@ -41,9 +78,18 @@ func (o *OrchestraUsecase) ProcessWorkflow(ctx context.Context, eventMsg event.G
 		log.Println("Error logging to db: ", err)
 	}
 
-	cachePayload, err := o.getCachePayload(eventMsg.InstanceID, eventMsg.Source, eventMsg.Payload.Response)
-	if err != nil {
-		return err
+	// a timeout the sweeper raised carries no response of the step's service
+	var cachePayload map[string]any
+	if eventMsg.Status == dto.TIMEOUT.String() {
+		cachePayload, err = o.cache.Load(ctx, eventMsg.InstanceID)
+		if err != nil {
+			return fmt.Errorf("load payload: %w", err)
+		}
+	} else {
+		cachePayload, err = o.getCachePayload(ctx, eventMsg.InstanceID, eventMsg.Source, eventMsg.Payload.Response)
+		if err != nil {
+			return err
+		}
 	}
 
 	wf, err := o.queries.FindWorkflowByType(ctx, eventMsg.EventType)
@@ -51,7 +97,20 @@ func (o *OrchestraUsecase) ProcessWorkflow(ctx context.Context, eventMsg event.G
 		return fmt.Errorf("find workflow: %w", err)
 	}
 
+	scheduled, err := o.scheduleRetry(ctx, &eventMsg)
+	if err != nil {
+		log.Println("Error scheduling retry: ", err)
+	}
+
+	if scheduled {
+		return nil
+	}
+
 	err = o.handleInstanceStep(ctx, eventMsg)
+	if errors.Is(err, errStepTimedOut) {
+		log.Println("Ignoring late reply for timed out step: ", eventMsg.EventID)
+		return nil
+	}
 	if err != nil {
 		log.Println("Error handling instance step: ", err)
 	}
@@ -62,18 +121,19 @@ func (o *OrchestraUsecase) ProcessWorkflow(ctx context.Context, eventMsg event.G
 		return err
 	}
 
+	if instance.Status == dto.ABORTING.String() || instance.Status == dto.ABORTED.String() {
+		return o.processAborted(ctx, eventMsg, instance, cachePayload)
+	}
+
 	return o.processSteps(ctx, eventMsg, instance, cachePayload)
 }
 
-func (o *OrchestraUsecase) getCachePayload(instanceID string, source string, response any) (map[string]any, error) {
-	cachePayload, ok := o.cache.Get(instanceID)
-	if !ok || cachePayload == nil {
-		cachePayload = make(map[string]any)
+func (o *OrchestraUsecase) getCachePayload(ctx context.Context, instanceID string, source string, response any) (map[string]any, error) {
+	cachePayload, err := o.cache.Append(ctx, instanceID, source, response)
+	if err != nil {
+		return nil, fmt.Errorf("cache payload: %w", err)
 	}
 
-	cachePayload[source] = response
-
-	o.cache.Set(instanceID, cachePayload)
 	return cachePayload, nil
 }
 
@@ -85,6 +145,12 @@ func (o *OrchestraUsecase) handleInstanceStep(ctx context.Context, eventMsg even
 		return err
 	}
 
+	// the sweeper already failed this step, a reply arriving now is stale
+	// unless an operator resolves the step by hand
+	if instanceStep.Status == dto.TIMEOUT.String() && eventMsg.Status != dto.TIMEOUT.String() && !isOperatorAction(eventMsg.Action) {
+		return errStepTimedOut
+	}
+
 	if eventMsg.StatusCode >= 500 {
 		log.Println("err server 500: ", instanceStep.StepID)
 	}
@@ -103,33 +169,58 @@ func (o *OrchestraUsecase) handleInstanceStep(ctx context.Context, eventMsg even
 	log.Println("step id: ", instanceStep.StepID)
 	log.Println("process time: ", time.Since(instanceStep.StartedAt.Time))
 
-	return o.queries.UpdateWorkflowInstanceStep(ctx, sqlc.UpdateWorkflowInstanceStepParams{
-		Status:       eventMsg.Status,
-		StatusCode:   sql.NullInt32{Int32: int32(eventMsg.StatusCode), Valid: true},
-		Response:     sql.NullString{String: string(responseMsg), Valid: true},
-		EventMessage: sql.NullString{String: string(eventMsgBytes), Valid: true},
-		StartedAt:    instanceStep.StartedAt,
-		CompletedAt:  sql.NullTime{Time: time.Now(), Valid: true},
-		EventID:      eventMsg.EventID,
+	return o.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		err := q.UpdateWorkflowInstanceStep(ctx, sqlc.UpdateWorkflowInstanceStepParams{
+			Status:       eventMsg.Status,
+			StatusCode:   sql.NullInt32{Int32: int32(eventMsg.StatusCode), Valid: true},
+			Response:     sql.NullString{String: string(responseMsg), Valid: true},
+			EventMessage: sql.NullString{String: string(eventMsgBytes), Valid: true},
+			StartedAt:    instanceStep.StartedAt,
+			CompletedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+			EventID:      eventMsg.EventID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return o.publishStepCompleted(ctx, q, eventMsg, instanceStep)
 	})
 }
 
 func (o *OrchestraUsecase) getOrCreateWorkflowInstance(ctx context.Context, eventMsg event.GlobalEvent[any, any], wf sqlc.Workflow) (sqlc.WorkflowInstance, error) {
+	// a child instance is created by the step of its parent that starts it
+	if eventMsg.Action == actionStartChild {
+		return o.queries.FindWorkflowInstanceByID(ctx, eventMsg.InstanceID)
+	}
+
 	if eventMsg.State == event.ORDER_CREATED.String() || eventMsg.State == event.ORDER_CANCEL.String() || eventMsg.State == event.BANK_REGIS_CREATED.String() {
-		return o.queries.CreateWorkflowInstance(ctx, sqlc.CreateWorkflowInstanceParams{
-			ID:         eventMsg.InstanceID,
-			WorkflowID: wf.ID,
-			Status:     dto.IN_PROGRESS.String(),
+		var instance sqlc.WorkflowInstance
+		err := o.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+			var err error
+			instance, err = q.CreateWorkflowInstance(ctx, sqlc.CreateWorkflowInstanceParams{
+				ID:              eventMsg.InstanceID,
+				WorkflowID:      wf.ID,
+				WorkflowVersion: wf.Version,
+				Status:          dto.IN_PROGRESS.String(),
+			})
+			if err != nil {
+				return err
+			}
+
+			return o.publishStarted(ctx, q, wf.Type, instance)
 		})
+		return instance, err
 	}
 
 	return o.queries.FindWorkflowInstanceByID(ctx, eventMsg.InstanceID)
 }
 
 func (o *OrchestraUsecase) processSteps(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, cachePayload map[string]any) error {
+	// resolve against the version the instance started on, not the latest one
 	steps, err := o.queries.FindStepsByTypeAndState(ctx, sqlc.FindStepsByTypeAndStateParams{
-		Type:  eventMsg.EventType,
-		State: eventMsg.State,
+		Type:    eventMsg.EventType,
+		Version: instance.WorkflowVersion,
+		State:   eventMsg.State,
 	})
 
 	if err != nil {
@@ -138,31 +229,85 @@ func (o *OrchestraUsecase) processSteps(ctx context.Context, eventMsg event.Glob
 
 	// handle if steps is empty, its mean all the step has done
 	if len(steps) == 0 {
-		err := o.processDone(ctx, eventMsg.EventType, instance.ID)
+		return o.finishSteps(ctx, eventMsg, instance)
+	}
+
+	taken, skipped, err := o.applyGuards(ctx, eventMsg, instance, steps, cachePayload)
+	if err != nil {
+		return fmt.Errorf("apply guards: %w", err)
+	}
+
+	// the saga goes on past the skipped steps rather than ending here
+	if len(taken) == 0 {
+		return o.continueSkipped(ctx, eventMsg, instance, skipped, cachePayload)
+	}
 
-		log.Println("-> process done <-")
+	return o.runSteps(ctx, eventMsg, instance, taken, cachePayload)
+}
 
+// finishSteps ends the instance once a state leads to no more steps.
+func (o *OrchestraUsecase) finishSteps(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance) error {
+	// a failure state nobody handles explicitly rolls back what already succeeded
+	if dto.IsFailureStatus(eventMsg.Status) {
+		err := o.compensate(ctx, eventMsg, instance)
 		if err != nil {
-			return fmt.Errorf("process done: %w", err)
+			log.Println("Error compensating instance: ", err)
 		}
+	}
 
-		return nil
+	err := o.processDone(ctx, eventMsg.EventType, instance)
+
+	log.Println("-> process done <-")
+
+	if err != nil {
+		return fmt.Errorf("process done: %w", err)
 	}
 
+	return nil
+}
+
+func (o *OrchestraUsecase) runSteps(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, steps []sqlc.FindStepsByTypeAndStateRow, cachePayload map[string]any) error {
+	var stepFailed bool
 	for _, step := range steps {
 		err := o.processStep(ctx, eventMsg, instance, step, cachePayload)
+		if errors.Is(err, errJoinPending) {
+			log.Printf("Step %d waiting for its predecessors", step.StepID)
+			continue
+		}
+		if errors.Is(err, errJoinFailed) {
+			stepFailed = true
+			continue
+		}
+		if errors.Is(err, errMappingFailed) {
+			log.Printf("Step %d not sent: %v", step.StepID, err)
+			stepFailed = true
+			continue
+		}
 		if err != nil {
 			log.Printf("Error processing step %d: %v", step.StepID, err)
 			continue
 		}
 	}
 
+	// a sibling branch failed or a request could not be built, roll back
+	// what already succeeded instead of carrying on
+	if stepFailed {
+		err := o.compensate(ctx, eventMsg, instance)
+		if err != nil {
+			log.Println("Error compensating instance: ", err)
+		}
+
+		return o.processDone(ctx, eventMsg.EventType, instance)
+	}
+
 	return nil
 }
 
-func (o *OrchestraUsecase) processDone(ctx context.Context, eventType, instanceID string) error {
+func (o *OrchestraUsecase) processDone(ctx context.Context, eventType string, instance sqlc.WorkflowInstance) error {
 	var hasFailed bool
 
+	instanceID := instance.ID
+
 	wfiSteps, err := o.queries.FindWorkflowInstanceByTypeAndID(ctx, sqlc.FindWorkflowInstanceByTypeAndIDParams{
 		Type:               eventType,
 		WorkflowInstanceID: instanceID,
@@ -172,22 +317,39 @@ func (o *OrchestraUsecase) processDone(ctx context.Context, eventType, instanceI
 		return fmt.Errorf("find workflow instance by type and id: %w", err)
 	}
 
+	// wait until parallel branches and compensations have replied
+	for _, value := range wfiSteps {
+		if value.InstanceStepStatus == dto.IN_PROGRESS.String() || value.InstanceStepStatus == dto.RETRY_SCHEDULED.String() {
+			log.Printf("Step %d still in progress", value.StepID)
+			return nil
+		}
+	}
+
 	for _, value := range wfiSteps {
-		if value.InstanceStepStatus != "success" {
+		if value.InstanceStepStatus != "success" && value.InstanceStepStatus != dto.SKIPPED.String() {
 			hasFailed = true
 			log.Printf("Step %d failed", value.StepID)
 		}
 	}
 
+	status := "completed"
 	if hasFailed {
-		err = o.queries.UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{
-			Status: "failed",
-			ID:     wfiSteps[0].InstanceID,
-		})
+		status = "failed"
+	}
+
+	if instance.ParentInstanceID.Valid {
+		err = o.finishChild(ctx, eventType, instance, status, wfiSteps)
 	} else {
-		err = o.queries.UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{
-			Status: "completed",
-			ID:     wfiSteps[0].InstanceID,
+		err = o.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+			err := q.UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{
+				Status: status,
+				ID:     instanceID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return o.publishFinished(ctx, q, eventType, instance, status, wfiSteps)
 		})
 	}
 
@@ -195,19 +357,36 @@ func (o *OrchestraUsecase) processDone(ctx context.Context, eventType, instanceI
 		return fmt.Errorf("update workflow instance: %w", err)
 	}
 
+	err = o.cache.Expire(ctx, instanceID)
+	if err != nil {
+		log.Println("Error expire cache payload: ", err)
+	}
+
 	return nil
 }
 
 func (o *OrchestraUsecase) processStep(ctx context.Context, eventMsg event.GlobalEvent[any, any], instance sqlc.WorkflowInstance, step sqlc.FindStepsByTypeAndStateRow, cachePayload map[string]any) error {
+	requires, err := o.queries.FindStepDependenciesByStepID(ctx, step.StepID)
+
+	if err != nil {
+		return fmt.Errorf("find step dependencies: %w", err)
+	}
+
 	keys, err := o.queries.FindPayloadKeysByStepID(ctx, step.StepID)
 
 	if err != nil {
 		return fmt.Errorf("find payload keys: %w", err)
 	}
 
-	basePayload, err := o.mergePayloads(keys, cachePayload)
+	mappings, err := o.queries.FindPayloadMappingsByStepID(ctx, step.StepID)
+
 	if err != nil {
-		return fmt.Errorf("merge payloads: %w", err)
+		return fmt.Errorf("find payload mappings: %w", err)
+	}
+
+	basePayload, mappingErr := o.buildRequest(keys, mappings, cachePayload)
+	if mappingErr != nil && !errors.Is(mappingErr, errMappingFailed) {
+		return fmt.Errorf("build request: %w", mappingErr)
 	}
 
 	gevent := o.createGlobalEvent(eventMsg, basePayload, instance.ID)
@@ -217,12 +396,30 @@ func (o *OrchestraUsecase) processStep(ctx context.Context, eventMsg event.Globa
 		return fmt.Errorf("parse message: %w", err)
 	}
 
-	err = o.createWorkflowInstanceStep(ctx, gevent, step, bytes)
+	err = o.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		if len(requires) > 0 {
+			err := o.awaitJoin(ctx, q, instance.ID, step.StepID, requires)
+			if err != nil {
+				return err
+			}
+		}
+
+		if mappingErr != nil {
+			return o.failInstanceStep(ctx, q, gevent, step, bytes, mappingErr)
+		}
+
+		err := o.createWorkflowInstanceStep(ctx, q, gevent, step, bytes)
+		if err != nil {
+			return err
+		}
+
+		return o.dispatchStep(ctx, q, gevent, step, bytes)
+	})
 	if err != nil {
 		return err
 	}
 
-	return o.producer.SendMessage(step.StepTopic, uuid.New().String(), bytes)
+	return mappingErr
 }
 
 func (o *OrchestraUsecase) mergePayloads(keys []string, cachePayload map[string]any) (any, error) {
@@ -253,14 +450,16 @@ func (o *OrchestraUsecase) createGlobalEvent(eventMsg event.GlobalEvent[any, any
 	return gevent
 }
 
-func (o *OrchestraUsecase) createWorkflowInstanceStep(ctx context.Context, gevent event.GlobalEvent[any, any], step sqlc.FindStepsByTypeAndStateRow, eventMessage []byte) error {
-	_, err := o.queries.CreateWorkflowInstanceStep(ctx, sqlc.CreateWorkflowInstanceStepParams{
+func (o *OrchestraUsecase) createWorkflowInstanceStep(ctx context.Context, q sqlc.Querier, gevent event.GlobalEvent[any, any], step sqlc.FindStepsByTypeAndStateRow, eventMessage []byte) error {
+	_, err := q.CreateWorkflowInstanceStep(ctx, sqlc.CreateWorkflowInstanceStepParams{
 		WorkflowInstanceID: gevent.InstanceID,
 		EventID:            gevent.EventID,
 		Status:             dto.IN_PROGRESS.String(),
 		StepID:             step.StepID,
 		EventMessage:       sql.NullString{String: string(eventMessage), Valid: true},
 		StartedAt:          sql.NullTime{Time: time.Now(), Valid: true},
+		Guard:              step.Guard,
+		GuardResult:        guardResult(step),
 	})
 	return err
 }
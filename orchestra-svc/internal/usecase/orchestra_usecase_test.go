@@ -5,18 +5,26 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"orchestra-svc/internal/dto"
 	"orchestra-svc/internal/dto/event"
 	"orchestra-svc/internal/repository/cache"
 	mockdb "orchestra-svc/internal/repository/mock"
 	"orchestra-svc/internal/repository/sqlc"
-	producer2 "orchestra-svc/pkg/producer"
 	"testing"
 )
 
-var producerTest *producer2.KafkaProducer
+func passThroughTx(store *mockdb.MockStore) {
+	store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(sqlc.Querier) error) error {
+			return fn(store)
+		}).AnyTimes()
+}
 
-func init() {
-	producerTest, _ = producer2.NewKafkaProducer([]string{"localhost:29092"}, "orchestra-topic-test")
+func passThroughTxStore(store *mockdb.MockStore) {
+	store.EXPECT().ExecTxStore(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(sqlc.Store) error) error {
+			return fn(store)
+		}).AnyTimes()
 }
 
 func TestOrchestraUsecase_ProcessWorkflow(t *testing.T) {
@@ -25,7 +33,7 @@ func TestOrchestraUsecase_ProcessWorkflow(t *testing.T) {
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	ctx := context.Background()
 
@@ -80,7 +88,7 @@ func TestOrchestraUsecase_ProcessWorkflow_ErrorFindingWorkflow(t *testing.T) {
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	ctx := context.Background()
 	eventMsg := event.GlobalEvent[any, any]{
@@ -107,7 +115,7 @@ func TestOrchestraUsecase_ProcessWorkflow_ErrorHandlingInstanceStep(t *testing.T
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	ctx := context.Background()
 	eventMsg := event.GlobalEvent[any, any]{
@@ -133,13 +141,13 @@ func TestOrchestraUsecase_getCachePayload_CacheMiss(t *testing.T) {
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	instanceID := "instance-001"
 	source := "source-1"
 	response := "response-1"
 
-	result, _ := uc.getCachePayload(instanceID, source, response)
+	result, _ := uc.getCachePayload(context.Background(), instanceID, source, response)
 	expected := map[string]any{"source-1": "response-1"}
 
 	assert.Equal(t, expected, result)
@@ -150,14 +158,14 @@ func TestOrchestraUsecase_getCachePayload_CacheHit(t *testing.T) {
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	instanceID := "instance-002"
 	source := "source-2"
 	response := "response-2"
 	cacher.Set(instanceID, map[string]any{"source-2": "old-response"})
 
-	result, _ := uc.getCachePayload(instanceID, source, response)
+	result, _ := uc.getCachePayload(context.Background(), instanceID, source, response)
 	expected := map[string]any{"source-2": "response-2"}
 
 	assert.Equal(t, expected, result)
@@ -168,7 +176,7 @@ func TestOrchestraUsecase_handleInstanceStep_ErrorFindingInstanceStep(t *testing
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	ctx := context.Background()
 	eventMsg := event.GlobalEvent[any, any]{
@@ -186,8 +194,9 @@ func TestOrchestraUsecase_processStep_ErrorCreatingWorkflowInstanceStep(t *testi
 	defer ctrl.Finish()
 
 	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	ctx := context.Background()
 	eventMsg := event.GlobalEvent[any, any]{}
@@ -195,20 +204,75 @@ func TestOrchestraUsecase_processStep_ErrorCreatingWorkflowInstanceStep(t *testi
 	step := sqlc.FindStepsByTypeAndStateRow{}
 	cachePayload := map[string]any{}
 
+	store.EXPECT().FindStepDependenciesByStepID(ctx, gomock.Any()).Return([]string{}, nil)
 	store.EXPECT().FindPayloadKeysByStepID(ctx, gomock.Any()).Return([]string{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, gomock.Any()).Return([]sqlc.PayloadMapping{}, nil)
 	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Return(sqlc.WorkflowInstanceStep{}, fmt.Errorf("error"))
 
 	err := uc.processStep(ctx, eventMsg, instance, step, cachePayload)
 	assert.Error(t, err)
 }
 
+func TestOrchestraUsecase_getOrCreateWorkflowInstance_PinsVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	cacher := cache.NewPayloadCache()
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		InstanceID: "instance-001",
+		State:      event.ORDER_CREATED.String(),
+	}
+
+	store.EXPECT().CreateWorkflowInstance(ctx, sqlc.CreateWorkflowInstanceParams{
+		ID:              "instance-001",
+		WorkflowID:      1,
+		WorkflowVersion: 3,
+		Status:          dto.IN_PROGRESS.String(),
+	}).Return(sqlc.WorkflowInstance{ID: "instance-001", WorkflowVersion: 3}, nil)
+
+	instance, err := uc.getOrCreateWorkflowInstance(ctx, eventMsg, sqlc.Workflow{ID: 1, Version: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), instance.WorkflowVersion)
+}
+
+func TestOrchestraUsecase_processSteps_UsesPinnedVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	cacher := cache.NewPayloadCache()
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventType:  "order_process",
+		State:      "user_validation_success",
+		InstanceID: "instance-001",
+	}
+
+	// the workflow has moved on to a newer version since the instance started
+	store.EXPECT().FindStepsByTypeAndState(ctx, sqlc.FindStepsByTypeAndStateParams{
+		Type:    "order_process",
+		Version: 1,
+		State:   "user_validation_success",
+	}).Return(nil, fmt.Errorf("error"))
+
+	err := uc.processSteps(ctx, eventMsg, sqlc.WorkflowInstance{ID: "instance-001", WorkflowVersion: 1}, map[string]any{})
+	assert.Error(t, err)
+}
+
 func TestOrchestraUsecase_mergePayloads_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	keys := []string{"key1", "key2"}
 	cachePayload := map[string]any{
@@ -232,7 +296,7 @@ func TestOrchestraUsecase_createWorkflowInstanceStep_Error(t *testing.T) {
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	ctx := context.Background()
 	gevent := event.GlobalEvent[any, any]{}
@@ -241,7 +305,7 @@ func TestOrchestraUsecase_createWorkflowInstanceStep_Error(t *testing.T) {
 
 	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Return(sqlc.WorkflowInstanceStep{}, fmt.Errorf("error"))
 
-	err := uc.createWorkflowInstanceStep(ctx, gevent, step, eventMessage)
+	err := uc.createWorkflowInstanceStep(ctx, store, gevent, step, eventMessage)
 	assert.Error(t, err)
 }
 func TestOrchestraUsecase_logDB_Error(t *testing.T) {
@@ -250,7 +314,7 @@ func TestOrchestraUsecase_logDB_Error(t *testing.T) {
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	ctx := context.Background()
 	eventMsg := event.GlobalEvent[any, any]{}
@@ -267,7 +331,7 @@ func TestOrchestraUsecase_getCachePayload(t *testing.T) {
 
 	store := mockdb.NewMockStore(ctrl)
 	cacher := cache.NewPayloadCache()
-	uc := NewOrchestraUsecase(store, producerTest, cacher)
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
 
 	tests := []struct {
 		name       string
@@ -301,9 +365,39 @@ func TestOrchestraUsecase_getCachePayload(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupCache()
 
-			result, _ := uc.getCachePayload(tt.instanceID, tt.source, tt.response)
+			result, _ := uc.getCachePayload(context.Background(), tt.instanceID, tt.source, tt.response)
 
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestOrchestraUsecase_processDone_ExpiresCachePayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	cacher := cache.NewPayloadCache()
+	uc := NewOrchestraUsecase(store, cacher, OrchestraOptions{})
+
+	ctx := context.Background()
+	cacher.Set("instance-001", map[string]any{"order-svc": "response"})
+
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, sqlc.FindWorkflowInstanceByTypeAndIDParams{
+		Type:               "order_process",
+		WorkflowInstanceID: "instance-001",
+	}).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceID: "instance-001", InstanceStepStatus: "success", StepID: 1},
+	}, nil)
+	store.EXPECT().UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{
+		Status: "completed",
+		ID:     "instance-001",
+	}).Return(nil)
+
+	err := uc.processDone(ctx, "order_process", sqlc.WorkflowInstance{ID: "instance-001"})
+	assert.NoError(t, err)
+
+	_, ok := cacher.Get("instance-001")
+	assert.False(t, ok)
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/repository/sqlc"
+	"orchestra-svc/pkg/producer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// outboxLeaseTTL is how long a replica keeps relaying the outbox after it
+// last took the lease. Another replica takes over once it lapses.
+const outboxLeaseTTL = 30 * time.Second
+
+type OutboxUsecase struct {
+	queries  sqlc.Store
+	producer *producer.KafkaProducer
+	// owner identifies this replica on the relay lease
+	owner string
+}
+
+func NewOutboxUsecase(queries sqlc.Store, producer *producer.KafkaProducer) *OutboxUsecase {
+	return &OutboxUsecase{
+		queries:  queries,
+		producer: producer,
+		owner:    uuid.New().String(),
+	}
+}
+
+// RelayPending publishes pending outbox messages in insert order and marks
+// each one sent once it is published. Only the replica holding the relay
+// lease publishes, so the messages of a key are never sent out of order by
+// two replicas at once. It stops at the first failed publish so later
+// messages are not delivered ahead of it.
+func (o *OutboxUsecase) RelayPending(ctx context.Context, limit int) (int, error) {
+	leased, err := o.queries.AcquireOutboxLease(ctx, sqlc.AcquireOutboxLeaseParams{
+		Owner:      o.owner,
+		TtlSeconds: int32(outboxLeaseTTL / time.Second),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("acquire outbox lease: %w", err)
+	}
+
+	if leased == 0 {
+		return 0, nil
+	}
+
+	// leave the rest to the next run rather than outlive the lease
+	deadline := time.Now().Add(outboxLeaseTTL / 2)
+
+	messages, err := o.queries.FindPendingOutboxMessages(ctx, int32(limit))
+	if err != nil {
+		return 0, err
+	}
+
+	var sent int
+	for _, msg := range messages {
+		if time.Now().After(deadline) {
+			break
+		}
+
+		err := o.producer.SendMessage(msg.Topic, msg.MessageKey, []byte(msg.Payload))
+		if err != nil {
+			log.Printf("Error publishing outbox message %d: %v", msg.ID, err)
+
+			return sent, o.queries.MarkOutboxMessageFailed(ctx, sqlc.MarkOutboxMessageFailedParams{
+				LastError: sql.NullString{String: err.Error(), Valid: true},
+				ID:        msg.ID,
+			})
+		}
+
+		// a message published but not marked is sent again on the next run
+		err = o.queries.MarkOutboxMessageSent(ctx, msg.ID)
+		if err != nil {
+			return sent, err
+		}
+
+		sent++
+	}
+
+	return sent, nil
+}
+
+// enqueueMessage keys the message by instance ID so every event of a saga
+// lands on the same partition and is consumed in order.
+func enqueueMessage(ctx context.Context, q sqlc.Querier, topic, instanceID string, value []byte) error {
+	return q.CreateOutboxMessage(ctx, sqlc.CreateOutboxMessageParams{
+		Topic:      topic,
+		MessageKey: instanceID,
+		Payload:    string(value),
+	})
+}
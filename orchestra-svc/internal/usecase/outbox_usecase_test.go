@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrchestraUsecase_processStep_EnqueuesMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{EventType: "order_process", State: "order_created"}
+	instance := sqlc.WorkflowInstance{ID: "instance-001"}
+	step := sqlc.FindStepsByTypeAndStateRow{StepID: 1, StepTopic: "user-topic"}
+	cachePayload := map[string]any{"order-svc": map[string]any{"order_id": "O-1"}}
+
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(1)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(1)).Return([]string{"order-svc"}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(1)).Return([]sqlc.PayloadMapping{}, nil)
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Return(sqlc.WorkflowInstanceStep{}, nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "user-topic", arg.Topic)
+			assert.Contains(t, arg.Payload, `"instance_id":"instance-001"`)
+			return nil
+		})
+
+	err := uc.processStep(ctx, eventMsg, instance, step, cachePayload)
+	assert.NoError(t, err)
+}
+
+func TestOrchestraUsecase_processStep_ErrorEnqueuingMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+
+	store.EXPECT().FindStepDependenciesByStepID(ctx, gomock.Any()).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, gomock.Any()).Return([]string{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, gomock.Any()).Return([]sqlc.PayloadMapping{}, nil)
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).Return(sqlc.WorkflowInstanceStep{}, nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).Return(fmt.Errorf("error"))
+
+	err := uc.processStep(ctx, event.GlobalEvent[any, any]{}, sqlc.WorkflowInstance{}, sqlc.FindStepsByTypeAndStateRow{}, map[string]any{})
+	assert.Error(t, err)
+}
+
+func TestOutboxUsecase_RelayPending_NoMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	oc := NewOutboxUsecase(store, nil)
+
+	ctx := context.Background()
+
+	store.EXPECT().AcquireOutboxLease(ctx, gomock.Any()).Return(int64(1), nil)
+	store.EXPECT().FindPendingOutboxMessages(ctx, int32(100)).Return([]sqlc.Outbox{}, nil)
+
+	sent, err := oc.RelayPending(ctx, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestOutboxUsecase_RelayPending_LeaseHeldElsewhere(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	oc := NewOutboxUsecase(store, nil)
+
+	ctx := context.Background()
+
+	// another replica is relaying, so nothing is read or sent here
+	store.EXPECT().AcquireOutboxLease(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.AcquireOutboxLeaseParams) (int64, error) {
+			assert.NotEmpty(t, arg.Owner)
+			assert.Equal(t, int32(30), arg.TtlSeconds)
+			return 0, nil
+		})
+
+	sent, err := oc.RelayPending(ctx, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
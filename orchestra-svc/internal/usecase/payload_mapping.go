@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+	"orchestra-svc/pkg/jsonpath"
+	"strings"
+	"time"
+)
+
+// errMappingFailed means a required request field could not be resolved, so
+// the step is recorded as failed instead of being sent to its service.
+var errMappingFailed = errors.New("payload mapping failed")
+
+// buildRequest merges the payloads named by keys and then applies the
+// mappings on top, reading each source from the cached responses keyed by
+// service.
+func (o *OrchestraUsecase) buildRequest(keys []string, mappings []sqlc.PayloadMapping, cachePayload map[string]any) (any, error) {
+	request, err := o.mergePayloads(keys, cachePayload)
+	if err != nil {
+		return nil, fmt.Errorf("merge payloads: %w", err)
+	}
+
+	if len(mappings) == 0 {
+		return request, nil
+	}
+
+	target, ok := request.(map[string]any)
+	if !ok {
+		target = make(map[string]any)
+	}
+
+	doc, err := toJSONDocument(cachePayload)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, mapping := range mappings {
+		source, err := jsonpath.Parse(mapping.Source)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %s: %w", mapping.Target, err)
+		}
+
+		value, found := source.Get(doc)
+		if !found || value == nil {
+			err = json.Unmarshal(mapping.DefaultValue, &value)
+			if err != nil {
+				return nil, fmt.Errorf("mapping %s default: %w", mapping.Target, err)
+			}
+		}
+
+		if value == nil {
+			if mapping.Required {
+				missing = append(missing, fmt.Sprintf("%s (from %s)", mapping.Target, mapping.Source))
+			}
+			continue
+		}
+
+		path, err := jsonpath.Parse(mapping.Target)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %s: %w", mapping.Target, err)
+		}
+
+		err = path.Set(target, value)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %s: %w", mapping.Target, err)
+		}
+	}
+
+	if len(missing) > 0 {
+		return target, fmt.Errorf("%w: missing required %s", errMappingFailed, strings.Join(missing, ", "))
+	}
+
+	return target, nil
+}
+
+// toJSONDocument round trips the cache so paths see plain JSON values.
+func toJSONDocument(cachePayload map[string]any) (any, error) {
+	bytes, err := json.Marshal(cachePayload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal cache payload: %w", err)
+	}
+
+	var doc any
+	err = json.Unmarshal(bytes, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal cache payload: %w", err)
+	}
+
+	return doc, nil
+}
+
+// failInstanceStep records a step whose request could not be built, keeping
+// the partial request and the reason so an operator can see what was missing.
+func (o *OrchestraUsecase) failInstanceStep(ctx context.Context, q sqlc.Querier, gevent event.GlobalEvent[any, any], step sqlc.FindStepsByTypeAndStateRow, eventMessage []byte, reason error) error {
+	response, err := json.Marshal(map[string]string{"error": reason.Error()})
+	if err != nil {
+		return fmt.Errorf("marshal response: %w", err)
+	}
+
+	now := time.Now()
+
+	_, err = q.CreateWorkflowInstanceStep(ctx, sqlc.CreateWorkflowInstanceStepParams{
+		WorkflowInstanceID: gevent.InstanceID,
+		EventID:            gevent.EventID,
+		Status:             dto.FAILED.String(),
+		StepID:             step.StepID,
+		EventMessage:       sql.NullString{String: string(eventMessage), Valid: true},
+		StartedAt:          sql.NullTime{Time: now, Valid: true},
+		CompletedAt:        sql.NullTime{Time: now, Valid: true},
+		Guard:              step.Guard,
+		GuardResult:        guardResult(step),
+		StatusCode:         sql.NullInt32{Int32: http.StatusUnprocessableEntity, Valid: true},
+		Response:           sql.NullString{String: string(response), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("create workflow instance step: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newMappingCache() map[string]any {
+	return map[string]any{
+		"order-svc":   map[string]any{"ref_id": "R-1", "amount": map[string]any{"Float64": 0, "Valid": false}},
+		"product-svc": map[string]any{"amount": 25.5},
+	}
+}
+
+func TestOrchestraUsecase_buildRequest(t *testing.T) {
+	uc := NewOrchestraUsecase(nil, cache.NewPayloadCache(), OrchestraOptions{})
+
+	request, err := uc.buildRequest([]string{"order-svc", "product-svc"}, []sqlc.PayloadMapping{
+		{Target: "amount", Source: "$.product-svc.amount", DefaultValue: []byte("null"), Required: true},
+		{Target: "payment.currency", Source: "$.order-svc.currency", DefaultValue: []byte(`"IDR"`)},
+		{Target: "note", Source: "$.order-svc.note", DefaultValue: []byte("null")},
+	}, newMappingCache())
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"ref_id":  "R-1",
+		"amount":  25.5,
+		"payment": map[string]any{"currency": "IDR"},
+	}, request)
+}
+
+func TestOrchestraUsecase_buildRequest_MissingRequired(t *testing.T) {
+	uc := NewOrchestraUsecase(nil, cache.NewPayloadCache(), OrchestraOptions{})
+
+	_, err := uc.buildRequest(nil, []sqlc.PayloadMapping{
+		{Target: "account_bank_id", Source: "$.user-svc.account_bank_id", DefaultValue: []byte("null"), Required: true},
+	}, newMappingCache())
+	assert.ErrorIs(t, err, errMappingFailed)
+	assert.Contains(t, err.Error(), "account_bank_id (from $.user-svc.account_bank_id)")
+}
+
+func TestOrchestraUsecase_processStep_FailsOnMissingField(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{EventType: "order_process", State: "product_reservation_success"}
+	instance := sqlc.WorkflowInstance{ID: "instance-001"}
+	step := sqlc.FindStepsByTypeAndStateRow{StepID: 4, StepTopic: "payment-topic"}
+
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(4)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(4)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(4)).Return([]sqlc.PayloadMapping{
+		{Target: "account_bank_id", Source: "$.user-svc.account_bank_id", DefaultValue: []byte("null"), Required: true},
+	}, nil)
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+			assert.Equal(t, "failed", arg.Status)
+			assert.Equal(t, int32(422), arg.StatusCode.Int32)
+			assert.Contains(t, arg.Response.String, "account_bank_id")
+			return sqlc.WorkflowInstanceStep{}, nil
+		})
+
+	err := uc.processStep(ctx, eventMsg, instance, step, newMappingCache())
+	assert.ErrorIs(t, err, errMappingFailed)
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+)
+
+// replayedReply is the last reply a step accepted according to the logs.
+type replayedReply struct {
+	log   sqlc.ProcessLog
+	event event.GlobalEvent[any, any]
+}
+
+// RecoverInstance replays the process logs of an instance in order to rebuild
+// its payload cache and the outcome of every step that received a reply. The
+// differences against the stored steps are returned, and unless dryRun is set
+// the steps are corrected and the cache of a running instance is restored.
+func (i *InstanceUsecase) RecoverInstance(ctx context.Context, id string, dryRun bool) (*dto.InstanceRecoverResponse, error) {
+	// the repair must not interleave with a reply processed for the instance
+	if !dryRun {
+		unlock := i.oc.instances.Lock(id)
+		defer unlock()
+	}
+
+	instance, err := i.findInstance(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := i.queries.FindProcessLogsByInstanceID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("find process logs: %w", err)
+	}
+
+	steps, err := i.queries.FindInstanceStepByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("find instance steps: %w", err)
+	}
+
+	payload, sources, replies, err := replayProcessLogs(logs)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffInstanceSteps(steps, replies)
+
+	response := &dto.InstanceRecoverResponse{
+		ID:       id,
+		DryRun:   dryRun,
+		Replayed: len(logs),
+		Payload:  payload,
+		Changes:  changes,
+	}
+
+	if dryRun {
+		return response, nil
+	}
+
+	err = i.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		for _, change := range changes {
+			reply := replies[change.EventID]
+
+			err := q.UpdateWorkflowInstanceStep(ctx, sqlc.UpdateWorkflowInstanceStepParams{
+				Status:       change.ReplayedStatus,
+				StatusCode:   sql.NullInt32{Int32: change.ReplayedStatusCode, Valid: true},
+				Response:     sql.NullString{String: responseJSON(reply.event), Valid: true},
+				EventMessage: sql.NullString{String: reply.log.EventMessage, Valid: true},
+				StartedAt:    startedAt(steps, change.EventID),
+				CompletedAt:  reply.log.CreatedAt,
+				EventID:      change.EventID,
+			})
+			if err != nil {
+				return fmt.Errorf("update step %s: %w", change.EventID, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// finished instances have already expired their cache
+	if isRunningStatus(instance.Status) {
+		err = i.restoreCache(ctx, id, sources, payload)
+		if err != nil {
+			return nil, err
+		}
+		response.CacheRestored = true
+	}
+
+	return response, nil
+}
+
+// replayProcessLogs follows ProcessWorkflow: every logged event is cached
+// under its source, and a step keeps the last reply it accepted. Replies
+// arriving after a timeout are ignored unless an operator resolved the step.
+func replayProcessLogs(logs []sqlc.ProcessLog) (map[string]any, []string, map[string]replayedReply, error) {
+	payload := make(map[string]any)
+	var sources []string
+	replies := make(map[string]replayedReply)
+
+	for _, entry := range logs {
+		var gevent event.GlobalEvent[any, any]
+		err := json.Unmarshal([]byte(entry.EventMessage), &gevent)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("parse process log %d: %w", entry.ID, err)
+		}
+
+		if _, ok := payload[gevent.Source]; !ok {
+			sources = append(sources, gevent.Source)
+		}
+		payload[gevent.Source] = gevent.Payload.Response
+
+		previous, ok := replies[entry.EventID]
+		if ok && previous.event.Status == dto.TIMEOUT.String() && gevent.Status != dto.TIMEOUT.String() && !isOperatorAction(gevent.Action) {
+			continue
+		}
+
+		replies[entry.EventID] = replayedReply{log: entry, event: gevent}
+	}
+
+	return payload, sources, replies, nil
+}
+
+func diffInstanceSteps(steps []sqlc.WorkflowInstanceStep, replies map[string]replayedReply) []dto.StepRecoveryDiff {
+	changes := []dto.StepRecoveryDiff{}
+
+	for _, step := range steps {
+		reply, ok := replies[step.EventID]
+		if !ok {
+			continue
+		}
+
+		// a pending retry supersedes the reply that scheduled it
+		if step.Status == dto.RETRY_SCHEDULED.String() {
+			continue
+		}
+
+		statusCode := int32(reply.event.StatusCode)
+		responseChanged := step.Response.String != responseJSON(reply.event)
+
+		if step.Status == reply.event.Status && step.StatusCode.Int32 == statusCode && !responseChanged {
+			continue
+		}
+
+		changes = append(changes, dto.StepRecoveryDiff{
+			EventID:            step.EventID,
+			StepID:             step.StepID,
+			CurrentStatus:      step.Status,
+			ReplayedStatus:     reply.event.Status,
+			CurrentStatusCode:  step.StatusCode.Int32,
+			ReplayedStatusCode: statusCode,
+			ResponseChanged:    responseChanged,
+		})
+	}
+
+	return changes
+}
+
+func (i *InstanceUsecase) restoreCache(ctx context.Context, id string, sources []string, payload map[string]any) error {
+	err := i.cache.Expire(ctx, id)
+	if err != nil {
+		return fmt.Errorf("expire cache: %w", err)
+	}
+
+	for _, source := range sources {
+		_, err = i.cache.Append(ctx, id, source, payload[source])
+		if err != nil {
+			return fmt.Errorf("restore cache: %w", err)
+		}
+	}
+
+	log.Printf("Restored cache of instance %s from %d sources", id, len(sources))
+
+	return nil
+}
+
+func responseJSON(gevent event.GlobalEvent[any, any]) string {
+	bytes, err := json.Marshal(gevent.Payload.Response)
+	if err != nil {
+		return ""
+	}
+	return string(bytes)
+}
+
+func startedAt(steps []sqlc.WorkflowInstanceStep, eventID string) sql.NullTime {
+	for _, step := range steps {
+		if step.EventID == eventID {
+			return step.StartedAt
+		}
+	}
+	return sql.NullTime{}
+}
+
+func isRunningStatus(status string) bool {
+	switch status {
+	case dto.IN_PROGRESS.String(), dto.COMPENSATING.String(), dto.ABORTING.String():
+		return true
+	}
+	return false
+}
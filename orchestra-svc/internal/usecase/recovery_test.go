@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newRecoveryLogs() []sqlc.ProcessLog {
+	created := sql.NullTime{Time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Valid: true}
+	return []sqlc.ProcessLog{
+		{ID: 1, EventID: "event-000", Status: "success", CreatedAt: created,
+			EventMessage: `{"event_id":"event-000","source":"order-svc","status":"success","status_code":200,"payload":{"response":{"ref_id":"R-1"}}}`},
+		{ID: 2, EventID: "event-001", Status: "success", CreatedAt: created,
+			EventMessage: `{"event_id":"event-001","source":"user-svc","status":"success","status_code":200,"payload":{"response":{"account_bank_id":"B-1"}}}`},
+		{ID: 3, EventID: "event-002", Status: "timeout", CreatedAt: created,
+			EventMessage: `{"event_id":"event-002","source":"payment-svc","status":"timeout","status_code":408,"payload":{"response":null}}`},
+		{ID: 4, EventID: "event-002", Status: "success", CreatedAt: created,
+			EventMessage: `{"event_id":"event-002","source":"payment-svc","status":"success","status_code":200,"payload":{"response":{"id":"P-1"}}}`},
+	}
+}
+
+func TestInstanceUsecase_RecoverInstance_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	ic := NewInstanceUsecase(store, cache.NewPayloadCache(), nil)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{ID: "instance-001", Status: "in_progress"}, nil)
+	store.EXPECT().FindProcessLogsByInstanceID(ctx, "instance-001").Return(newRecoveryLogs(), nil)
+	store.EXPECT().FindInstanceStepByID(ctx, "instance-001").Return([]sqlc.WorkflowInstanceStep{
+		{EventID: "event-001", StepID: 1, Status: "in_progress"},
+		{EventID: "event-002", StepID: 4, Status: "timeout", StatusCode: sql.NullInt32{Int32: 408, Valid: true}, Response: sql.NullString{String: "null", Valid: true}},
+		{EventID: "event-003", StepID: 5, Status: "in_progress"},
+	}, nil)
+
+	response, err := ic.RecoverInstance(ctx, "instance-001", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, response.Replayed)
+	assert.False(t, response.CacheRestored)
+
+	// the late payment reply was ignored after the timeout
+	assert.Len(t, response.Changes, 1)
+	assert.Equal(t, "event-001", response.Changes[0].EventID)
+	assert.Equal(t, "in_progress", response.Changes[0].CurrentStatus)
+	assert.Equal(t, "success", response.Changes[0].ReplayedStatus)
+	assert.True(t, response.Changes[0].ResponseChanged)
+
+	assert.Equal(t, map[string]any{"ref_id": "R-1"}, response.Payload["order-svc"])
+	assert.Equal(t, map[string]any{"id": "P-1"}, response.Payload["payment-svc"])
+}
+
+func TestInstanceUsecase_RecoverInstance_RepairsStepsAndCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	cacher := cache.NewPayloadCache()
+	ic := NewInstanceUsecase(store, cacher, NewOrchestraUsecase(store, cacher, OrchestraOptions{}))
+
+	ctx := context.Background()
+	started := sql.NullTime{Time: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), Valid: true}
+
+	store.EXPECT().FindWorkflowInstanceWithType(ctx, "instance-001").Return(sqlc.FindWorkflowInstanceWithTypeRow{ID: "instance-001", Status: "in_progress"}, nil)
+	store.EXPECT().FindProcessLogsByInstanceID(ctx, "instance-001").Return(newRecoveryLogs()[:2], nil)
+	store.EXPECT().FindInstanceStepByID(ctx, "instance-001").Return([]sqlc.WorkflowInstanceStep{
+		{EventID: "event-001", StepID: 1, Status: "in_progress", StartedAt: started},
+	}, nil)
+	store.EXPECT().UpdateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.UpdateWorkflowInstanceStepParams) error {
+			assert.Equal(t, "event-001", arg.EventID)
+			assert.Equal(t, "success", arg.Status)
+			assert.Equal(t, int32(200), arg.StatusCode.Int32)
+			assert.Equal(t, `{"account_bank_id":"B-1"}`, arg.Response.String)
+			assert.Equal(t, started, arg.StartedAt)
+			return nil
+		})
+
+	response, err := ic.RecoverInstance(ctx, "instance-001", false)
+	assert.NoError(t, err)
+	assert.True(t, response.CacheRestored)
+
+	payload, err := cacher.Load(ctx, "instance-001")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"account_bank_id": "B-1"}, payload["user-svc"])
+	assert.Equal(t, map[string]any{"ref_id": "R-1"}, payload["order-svc"])
+}
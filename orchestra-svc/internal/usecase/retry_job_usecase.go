@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxRetryJobSteps caps how many steps a single bulk retry selects. A
+	// filter matching more is rejected rather than silently truncated.
+	maxRetryJobSteps = 1000
+	// retryJobClaimTimeout is how long a claimed step may stay unfinished
+	// before another run claims it again, e.g. after a crash mid-batch.
+	retryJobClaimTimeout = 5 * time.Minute
+)
+
+var (
+	ErrRetryJobNotFound = errors.New("retry job not found")
+	ErrNoStepsToRetry   = errors.New("no failed steps match the filter")
+	ErrInvalidRetryJob  = errors.New("invalid retry job")
+)
+
+type RetryJobUsecase struct {
+	queries  sqlc.Store
+	rc       *RetryUsecase
+	interval time.Duration
+	rate     int
+	// next is when each running job may resend its next step, so its rate
+	// holds across runs as well as within one
+	next map[string]time.Time
+}
+
+// NewRetryJobUsecase runs bulk retries every interval, resending rate steps
+// per second for jobs that do not set their own rate.
+func NewRetryJobUsecase(queries sqlc.Store, rc *RetryUsecase, interval time.Duration, rate int) *RetryJobUsecase {
+	return &RetryJobUsecase{
+		queries:  queries,
+		rc:       rc,
+		interval: interval,
+		rate:     rate,
+	}
+}
+
+// CreateRetryJob selects the failed steps matching the request and queues
+// them for RunRetryJobs.
+func (j *RetryJobUsecase) CreateRetryJob(ctx context.Context, req *dto.RetryJobRequest) (*dto.RetryJobResponse, error) {
+	if err := validateRetryJob(req); err != nil {
+		return nil, err
+	}
+
+	rate := req.Rate
+	if rate <= 0 {
+		rate = j.rate
+	}
+
+	steps, err := j.queries.FindFailedInstanceSteps(ctx, sqlc.FindFailedInstanceStepsParams{
+		Status:        sql.NullString{String: req.Status, Valid: req.Status != ""},
+		Type:          sql.NullString{String: req.Type, Valid: req.Type != ""},
+		Step:          sql.NullString{String: req.Step, Valid: req.Step != ""},
+		Service:       sql.NullString{String: req.Service, Valid: req.Service != ""},
+		StatusCodeMin: sql.NullInt32{Int32: req.StatusCodeMin, Valid: req.StatusCodeMin != 0},
+		StatusCodeMax: sql.NullInt32{Int32: req.StatusCodeMax, Valid: req.StatusCodeMax != 0},
+		StartedFrom:   sql.NullTime{Time: req.From, Valid: !req.From.IsZero()},
+		StartedTo:     sql.NullTime{Time: req.To, Valid: !req.To.IsZero()},
+		Limit:         maxRetryJobSteps + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find failed steps: %w", err)
+	}
+
+	if len(steps) == 0 {
+		return nil, ErrNoStepsToRetry
+	}
+
+	if len(steps) > maxRetryJobSteps {
+		return nil, fmt.Errorf("%w: more than %d steps match the filter, narrow it down", ErrInvalidRetryJob, maxRetryJobSteps)
+	}
+
+	filter, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+
+	err = j.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		err := q.CreateRetryJob(ctx, sqlc.CreateRetryJobParams{
+			ID:     id,
+			Filter: filter,
+			Rate:   int32(rate),
+			Total:  int32(len(steps)),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, step := range steps {
+			err := q.CreateRetryJobItem(ctx, sqlc.CreateRetryJobItemParams{
+				JobID:              id,
+				EventID:            step.EventID,
+				WorkflowInstanceID: step.WorkflowInstanceID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create retry job: %w", err)
+	}
+
+	return j.GetRetryJob(ctx, id)
+}
+
+func validateRetryJob(req *dto.RetryJobRequest) error {
+	if req.Status != "" && req.Status != dto.ERROR.String() && req.Status != dto.TIMEOUT.String() {
+		return fmt.Errorf("%w: status must be %s or %s", ErrInvalidRetryJob, dto.ERROR, dto.TIMEOUT)
+	}
+
+	if req.StatusCodeMin != 0 && req.StatusCodeMax != 0 && req.StatusCodeMin > req.StatusCodeMax {
+		return fmt.Errorf("%w: status_code_min is above status_code_max", ErrInvalidRetryJob)
+	}
+
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidRetryJob)
+	}
+
+	if req.Rate < 0 {
+		return fmt.Errorf("%w: rate must be positive", ErrInvalidRetryJob)
+	}
+
+	return nil
+}
+
+// GetRetryJob returns a job with the outcome of each of its steps.
+func (j *RetryJobUsecase) GetRetryJob(ctx context.Context, id string) (*dto.RetryJobResponse, error) {
+	job, err := j.queries.FindRetryJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRetryJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find retry job: %w", err)
+	}
+
+	items, err := j.queries.FindRetryJobItems(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("find retry job items: %w", err)
+	}
+
+	return &dto.RetryJobResponse{
+		Job:     job,
+		Pending: job.Total - job.Succeeded - job.Failed,
+		Items:   items,
+	}, nil
+}
+
+// RunRetryJobs resends the next steps of every running job, as many per job
+// as its rate allows in one interval. The steps of a job are spaced evenly
+// rather than sent back to back.
+func (j *RetryJobUsecase) RunRetryJobs(ctx context.Context) (int, error) {
+	jobs, err := j.queries.FindRunningRetryJobs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("find running retry jobs: %w", err)
+	}
+
+	next := make(map[string]time.Time, len(jobs))
+	defer func() { j.next = next }()
+
+	var retried int
+	for _, job := range jobs {
+		rate := max(job.Rate, 1)
+		gap := time.Second / time.Duration(rate)
+		at := j.next[job.ID]
+		next[job.ID] = at
+
+		batch := max(int(float64(rate)*j.interval.Seconds()), 1)
+
+		// a step reclaimed after it was resent is no longer failed, so the
+		// second attempt is recorded as an error instead of resending it
+		items, err := j.queries.ClaimRetryJobItems(ctx, sqlc.ClaimRetryJobItemsParams{
+			JobID:       job.ID,
+			StaleBefore: sql.NullTime{Time: time.Now().Add(-retryJobClaimTimeout), Valid: true},
+			Limit:       int32(batch),
+		})
+		if err != nil {
+			log.Printf("Error claiming steps of retry job %s: %v", job.ID, err)
+			continue
+		}
+
+		for _, item := range items {
+			// the claimed steps left behind are taken back once their claim
+			// times out
+			if err := waitUntil(ctx, at); err != nil {
+				return retried, err
+			}
+			at = time.Now().Add(gap)
+			next[job.ID] = at
+
+			status, reason := dto.COMPLETE.String(), ""
+
+			_, err := j.rc.RetryFailedInstanceStep(ctx, &dto.RetryRequest{
+				EventID:    item.EventID,
+				InstanceID: item.WorkflowInstanceID,
+			})
+			if err != nil {
+				status, reason = dto.ERROR.String(), err.Error()
+			} else {
+				retried++
+			}
+
+			err = j.queries.FinishRetryJobItem(ctx, sqlc.FinishRetryJobItemParams{
+				ID:     item.ID,
+				Status: status,
+				Error:  reason,
+			})
+			if err != nil {
+				log.Printf("Error recording retry of step %s: %v", item.EventID, err)
+			}
+		}
+
+		if err := j.queries.RefreshRetryJobProgress(ctx, job.ID); err != nil {
+			log.Printf("Error refreshing retry job %s: %v", job.ID, err)
+		}
+	}
+
+	return retried, nil
+}
+
+// waitUntil blocks until t or until ctx is done.
+func waitUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newRetryJobUsecase(store *mockdb.MockStore) *RetryJobUsecase {
+	rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+	return NewRetryJobUsecase(store, rc, 2*time.Second, 10)
+}
+
+func TestRetryJobUsecase_CreateRetryJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	jc := newRetryJobUsecase(store)
+	passThroughTx(store)
+
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store.EXPECT().FindFailedInstanceSteps(ctx, sqlc.FindFailedInstanceStepsParams{
+		Type:          sql.NullString{String: "order_process", Valid: true},
+		Service:       sql.NullString{String: "payment-svc", Valid: true},
+		StatusCodeMin: sql.NullInt32{Int32: 500, Valid: true},
+		StatusCodeMax: sql.NullInt32{Int32: 599, Valid: true},
+		StartedFrom:   sql.NullTime{Time: from, Valid: true},
+		Limit:         maxRetryJobSteps + 1,
+	}).Return([]sqlc.FindFailedInstanceStepsRow{
+		{EventID: "event-001", WorkflowInstanceID: "instance-001"},
+		{EventID: "event-002", WorkflowInstanceID: "instance-002"},
+	}, nil)
+
+	var jobID string
+	store.EXPECT().CreateRetryJob(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateRetryJobParams) error {
+			jobID = arg.ID
+			assert.Equal(t, int32(10), arg.Rate)
+			assert.Equal(t, int32(2), arg.Total)
+			assert.Contains(t, string(arg.Filter), `"service":"payment-svc"`)
+			return nil
+		})
+	store.EXPECT().CreateRetryJobItem(ctx, gomock.Any()).Return(nil).Times(2)
+	store.EXPECT().FindRetryJob(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, id string) (sqlc.RetryJob, error) {
+			assert.Equal(t, jobID, id)
+			return sqlc.RetryJob{ID: id, Status: "running", Total: 2}, nil
+		})
+	store.EXPECT().FindRetryJobItems(ctx, gomock.Any()).Return([]sqlc.RetryJobItem{
+		{EventID: "event-001", Status: "pending"},
+		{EventID: "event-002", Status: "pending"},
+	}, nil)
+
+	response, err := jc.CreateRetryJob(ctx, &dto.RetryJobRequest{
+		Type:          "order_process",
+		Service:       "payment-svc",
+		StatusCodeMin: 500,
+		StatusCodeMax: 599,
+		From:          from,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), response.Pending)
+	assert.Len(t, response.Items, 2)
+}
+
+func TestRetryJobUsecase_CreateRetryJob_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	jc := newRetryJobUsecase(store)
+
+	ctx := context.Background()
+
+	for _, req := range []dto.RetryJobRequest{
+		{Status: "success"},
+		{StatusCodeMin: 500, StatusCodeMax: 400},
+		{From: time.Now(), To: time.Now().Add(-time.Hour)},
+		{Rate: -1},
+	} {
+		_, err := jc.CreateRetryJob(ctx, &req)
+		assert.ErrorIs(t, err, ErrInvalidRetryJob)
+	}
+
+	store.EXPECT().FindFailedInstanceSteps(ctx, gomock.Any()).Return([]sqlc.FindFailedInstanceStepsRow{}, nil)
+
+	_, err := jc.CreateRetryJob(ctx, &dto.RetryJobRequest{Type: "order_process"})
+	assert.ErrorIs(t, err, ErrNoStepsToRetry)
+
+	// a filter matching more steps than one job takes is not truncated
+	store.EXPECT().FindFailedInstanceSteps(ctx, gomock.Any()).Return(make([]sqlc.FindFailedInstanceStepsRow, maxRetryJobSteps+1), nil)
+
+	_, err = jc.CreateRetryJob(ctx, &dto.RetryJobRequest{Type: "order_process"})
+	assert.ErrorIs(t, err, ErrInvalidRetryJob)
+}
+
+func TestRetryJobUsecase_RunRetryJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	jc := newRetryJobUsecase(store)
+	passThroughTx(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindRunningRetryJobs(ctx).Return([]sqlc.RetryJob{{ID: "job-1", Rate: 3}}, nil)
+	// 3 steps per second over a 2s interval
+	store.EXPECT().ClaimRetryJobItems(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.ClaimRetryJobItemsParams) ([]sqlc.RetryJobItem, error) {
+			assert.Equal(t, "job-1", arg.JobID)
+			assert.Equal(t, int32(6), arg.Limit)
+			// steps claimed by a run that never finished them are taken back
+			assert.WithinDuration(t, time.Now().Add(-retryJobClaimTimeout), arg.StaleBefore.Time, time.Second)
+			return []sqlc.RetryJobItem{
+				{ID: 1, EventID: "event-001", WorkflowInstanceID: "instance-001"},
+				{ID: 2, EventID: "event-002", WorkflowInstanceID: "instance-002"},
+			}, nil
+		})
+
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
+		EventID:            "event-001",
+		WorkflowInstanceID: "instance-001",
+	}).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		EventID:            "event-001",
+		WorkflowInstanceID: "instance-001",
+		Status:             "error",
+		Topic:              "payment-topic",
+		EventMessage:       sql.NullString{String: paymentEventMessage, Valid: true},
+	}, nil)
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "in_progress"}, nil)
+	store.EXPECT().UpdateWorkflowInstanceStep(ctx, gomock.Any()).Return(nil)
+	store.EXPECT().DeleteProcessedEvents(ctx, "event-001").Return(nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).Return(nil)
+
+	// already retried by someone else
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
+		EventID:            "event-002",
+		WorkflowInstanceID: "instance-002",
+	}).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{EventID: "event-002", Status: "success"}, nil)
+
+	store.EXPECT().FinishRetryJobItem(ctx, sqlc.FinishRetryJobItemParams{ID: 1, Status: "success"}).Return(nil)
+	store.EXPECT().FinishRetryJobItem(ctx, sqlc.FinishRetryJobItemParams{ID: 2, Status: "error", Error: "step is not in failed state"}).Return(nil)
+	store.EXPECT().RefreshRetryJobProgress(ctx, "job-1").Return(nil)
+
+	start := time.Now()
+	retried, err := jc.RunRetryJobs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, retried)
+	// the two steps are a third of a second apart, not sent back to back
+	assert.GreaterOrEqual(t, time.Since(start), time.Second/3)
+}
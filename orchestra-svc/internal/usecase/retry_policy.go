@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+	"slices"
+	"time"
+)
+
+const maxRetryDelay = time.Hour
+
+// scheduleRetry parks a failed step as retry_scheduled when its step allows
+// another attempt for the reply's status code. Once the attempts are used up
+// the reply is escalated to error so the failure path takes over.
+func (o *OrchestraUsecase) scheduleRetry(ctx context.Context, eventMsg *event.GlobalEvent[any, any]) (bool, error) {
+	if !dto.IsFailureStatus(eventMsg.Status) {
+		return false, nil
+	}
+
+	policy, err := o.queries.FindInstanceStepRetryPolicy(ctx, eventMsg.EventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("find retry policy: %w", err)
+	}
+
+	if policy.Status != dto.IN_PROGRESS.String() || policy.MaxAttempts <= 1 {
+		return false, nil
+	}
+
+	// an aborted instance compensates instead of retrying
+	if policy.InstanceStatus == dto.ABORTING.String() {
+		return false, nil
+	}
+
+	if !slices.Contains(policy.RetryableStatusCodes, int32(eventMsg.StatusCode)) {
+		return false, nil
+	}
+
+	if policy.Attempts >= policy.MaxAttempts {
+		log.Printf("Step %s failed after %d attempts", eventMsg.EventID, policy.Attempts)
+		eventMsg.Status = dto.ERROR.String()
+		return false, nil
+	}
+
+	response, err := json.Marshal(eventMsg.Payload.Response)
+	if err != nil {
+		return false, fmt.Errorf("parse response: %w", err)
+	}
+
+	delay := retryDelay(time.Duration(policy.RetryBaseDelayMs)*time.Millisecond, policy.Attempts)
+
+	err = o.queries.ScheduleInstanceStepRetry(ctx, sqlc.ScheduleInstanceStepRetryParams{
+		StatusCode:  sql.NullInt32{Int32: int32(eventMsg.StatusCode), Valid: true},
+		Response:    sql.NullString{String: string(response), Valid: true},
+		NextRetryAt: sql.NullTime{Time: time.Now().Add(delay), Valid: true},
+		EventID:     eventMsg.EventID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("schedule retry: %w", err)
+	}
+
+	log.Printf("Retry %d/%d of step %s scheduled in %s", policy.Attempts+1, policy.MaxAttempts, eventMsg.EventID, delay)
+
+	return true, nil
+}
+
+func retryDelay(base time.Duration, attempt int32) time.Duration {
+	delay := base
+	for i := int32(1); i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRetryDelay(t *testing.T) {
+	testCases := []struct {
+		name     string
+		base     time.Duration
+		attempt  int32
+		expected time.Duration
+	}{
+		{name: "First attempt", base: time.Second, attempt: 1, expected: time.Second},
+		{name: "Third attempt", base: time.Second, attempt: 3, expected: 4 * time.Second},
+		{name: "Capped", base: time.Minute, attempt: 30, expected: time.Hour},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, retryDelay(tc.base, tc.attempt))
+		})
+	}
+}
+
+func failedPaymentEvent(statusCode int) event.GlobalEvent[any, any] {
+	return event.GlobalEvent[any, any]{
+		EventID:    "event-001",
+		InstanceID: "instance-001",
+		EventType:  "order_process",
+		State:      "payment_failed",
+		Status:     "failed",
+		StatusCode: statusCode,
+		Source:     "payment-svc",
+	}
+}
+
+func TestOrchestraUsecase_scheduleRetry(t *testing.T) {
+	testCases := []struct {
+		name           string
+		statusCode     int
+		attempts       int32
+		schedule       bool
+		expectedStatus string
+	}{
+		{name: "Retryable status code", statusCode: 503, attempts: 1, schedule: true, expectedStatus: "failed"},
+		{name: "Non retryable status code", statusCode: 400, attempts: 1, expectedStatus: "failed"},
+		{name: "Attempts exhausted", statusCode: 503, attempts: 3, expectedStatus: "error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+			ctx := context.Background()
+			eventMsg := failedPaymentEvent(tc.statusCode)
+
+			store.EXPECT().FindInstanceStepRetryPolicy(ctx, "event-001").Return(sqlc.FindInstanceStepRetryPolicyRow{
+				Status:               "in_progress",
+				Attempts:             tc.attempts,
+				MaxAttempts:          3,
+				RetryBaseDelayMs:     1000,
+				RetryableStatusCodes: []int32{429, 503},
+			}, nil)
+
+			if tc.schedule {
+				store.EXPECT().ScheduleInstanceStepRetry(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, arg sqlc.ScheduleInstanceStepRetryParams) error {
+						assert.Equal(t, "event-001", arg.EventID)
+						assert.Equal(t, int32(503), arg.StatusCode.Int32)
+						assert.WithinDuration(t, time.Now().Add(time.Second), arg.NextRetryAt.Time, time.Second)
+						return nil
+					})
+			}
+
+			scheduled, err := uc.scheduleRetry(ctx, &eventMsg)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.schedule, scheduled)
+			assert.Equal(t, tc.expectedStatus, eventMsg.Status)
+		})
+	}
+}
+
+func TestOrchestraUsecase_ProcessWorkflow_RetryScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+
+	store.EXPECT().CreateProcessLog(ctx, gomock.Any()).Return(nil)
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{ID: 1, Type: "order_process"}, nil)
+	store.EXPECT().FindInstanceStepRetryPolicy(ctx, "event-001").Return(sqlc.FindInstanceStepRetryPolicyRow{
+		Status:               "in_progress",
+		Attempts:             1,
+		MaxAttempts:          3,
+		RetryableStatusCodes: []int32{503},
+	}, nil)
+	store.EXPECT().ScheduleInstanceStepRetry(ctx, gomock.Any()).Return(nil)
+
+	err := uc.ProcessWorkflow(ctx, failedPaymentEvent(503))
+	assert.NoError(t, err)
+}
+
+func TestRetryUsecase_RetryDueInstanceSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	oc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+	rc := NewRetryUsecase(store, oc)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindDueInstanceStepRetries(ctx, int32(10)).Return([]sqlc.FindDueInstanceStepRetriesRow{
+		{EventID: "event-001", WorkflowInstanceID: "instance-001"},
+		{EventID: "event-002", WorkflowInstanceID: "instance-002"},
+	}, nil)
+	store.EXPECT().ClaimInstanceStepRetry(ctx, "event-001").Return(int64(0), nil)
+	store.EXPECT().ClaimInstanceStepRetry(ctx, "event-002").Return(int64(1), nil)
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
+		EventID:            "event-002",
+		WorkflowInstanceID: "instance-002",
+	}).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		WorkflowInstanceID: "instance-002",
+		Status:             "in_progress",
+		EventMessage:       sql.NullString{},
+	}, nil)
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-002").Return(sqlc.WorkflowInstance{ID: "instance-002", Status: "in_progress"}, nil)
+	store.EXPECT().UpdateWorkflowInstanceStepStatus(ctx, sqlc.UpdateWorkflowInstanceStepStatusParams{
+		Status:  "error",
+		EventID: "event-002",
+	}).Return(nil)
+
+	retried, err := rc.RetryDueInstanceSteps(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, retried)
+}
@@ -3,34 +3,51 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
-	"github.com/google/uuid"
+	"fmt"
+	"log"
 	"orchestra-svc/internal/dto"
 	"orchestra-svc/internal/dto/event"
 	"orchestra-svc/internal/repository/sqlc"
-	"orchestra-svc/pkg/producer"
+	"orchestra-svc/pkg/jsonpatch"
 	"time"
 )
 
+// ErrInstanceNotRetryable is returned when a step is resent for a saga that
+// already finished, rolled back or is being aborted.
+var ErrInstanceNotRetryable = errors.New("workflow instance is not in progress")
+
 type RetryUsecase struct {
-	queries  sqlc.Store
-	producer *producer.KafkaProducer
-	oc       *OrchestraUsecase
+	queries sqlc.Store
+	oc      *OrchestraUsecase
 }
 
 func NewRetryUsecase(
 	queries sqlc.Store,
-	producer *producer.KafkaProducer,
 	oc *OrchestraUsecase,
 ) *RetryUsecase {
 	return &RetryUsecase{
-		queries:  queries,
-		producer: producer,
-		oc:       oc,
+		queries: queries,
+		oc:      oc,
 	}
 }
 
-func (r *RetryUsecase) ProductQuantityRetry(ctx context.Context, req *dto.ProductQuantityRetryRequest) (*event.GlobalEvent[dto.ProductReserveRequest, any], error) {
+// ProductQuantityRetry resends a failed product reservation with the quantity
+// the operator chose, as a merge patch of its request.
+func (r *RetryUsecase) ProductQuantityRetry(ctx context.Context, req *dto.ProductQuantityRetryRequest) (*event.GlobalEvent[any, any], error) {
+	raw, err := json.Marshal(map[string]int{"quantity": req.Quantity})
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := jsonpatch.Parse(jsonpatch.Merge, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := r.oc.instances.Lock(req.InstanceID)
+	defer unlock()
 
 	insStep, err := r.queries.FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
 		EventID:            req.EventID,
@@ -45,65 +62,124 @@ func (r *RetryUsecase) ProductQuantityRetry(ctx context.Context, req *dto.Produc
 		return nil, errors.New("step is not in failed state")
 	}
 
-	if !insStep.EventMessage.Valid {
-		return nil, errors.New("event message is not valid")
+	// product-svc handles the retried reservation under its own state
+	return r.resend(ctx, insStep, &requestPatch{patch: parsed, raw: raw}, "product_retry")
+}
+
+// RetryFailedInstanceStep resends a failed or timed out step, with its request
+// patched when the request carries a patch.
+func (r *RetryUsecase) RetryFailedInstanceStep(ctx context.Context, req *dto.RetryRequest) (*event.GlobalEvent[any, any], error) {
+
+	var patch *requestPatch
+	if len(req.Patch) > 0 && string(req.Patch) != "null" {
+		parsed, err := jsonpatch.Parse(req.PatchType, req.Patch)
+		if err != nil {
+			return nil, err
+		}
+		patch = &requestPatch{patch: parsed, raw: req.Patch}
 	}
 
-	eventMsg, err := event.FromJSON[dto.ProductReserveRequest, any]([]byte(insStep.EventMessage.String))
+	unlock := r.oc.instances.Lock(req.InstanceID)
+	defer unlock()
+
+	insStep, err := r.queries.FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
+		EventID:            req.EventID,
+		WorkflowInstanceID: req.InstanceID,
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	gevent := event.NewGlobalEvent[dto.ProductReserveRequest, any](
-		"retry", "success", eventMsg.Payload)
+	if insStep.Status != dto.ERROR.String() && insStep.Status != dto.TIMEOUT.String() {
+		return nil, errors.New("step is not in failed state")
+	}
 
-	gevent.State = "product_retry"
-	gevent.StatusCode = 200
-	gevent.Payload.Request.Quantity = req.Quantity
+	return r.resend(ctx, insStep, patch, "")
+}
 
-	gevent.EventType = eventMsg.EventType
-	gevent.InstanceID = eventMsg.InstanceID
-	gevent.EventID = eventMsg.EventID
+// ListRetryAudits returns the patched retries of an instance, oldest first.
+func (r *RetryUsecase) ListRetryAudits(ctx context.Context, instanceID string) ([]sqlc.RetryAudit, error) {
+	return r.queries.FindRetryAuditsByInstanceID(ctx, instanceID)
+}
 
-	bytes, err := gevent.ToJSON()
+// RetryDueInstanceSteps resends the steps whose scheduled retry is due.
+func (r *RetryUsecase) RetryDueInstanceSteps(ctx context.Context, limit int) (int, error) {
+	due, err := r.queries.FindDueInstanceStepRetries(ctx, int32(limit))
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("find due retries: %w", err)
 	}
 
-	err = r.queries.UpdateWorkflowInstanceStep(ctx, sqlc.UpdateWorkflowInstanceStepParams{
-		Status:       dto.IN_PROGRESS.String(),
-		EventMessage: sql.NullString{String: string(bytes), Valid: true},
-		StartedAt:    sql.NullTime{Time: time.Now(), Valid: true},
-		EventID:      gevent.EventID,
-	})
+	var retried int
+	for _, step := range due {
+		ok, err := r.retryDueStep(ctx, step)
+		if err != nil {
+			log.Printf("Error retrying step %s: %v", step.EventID, err)
+			continue
+		}
+
+		if ok {
+			retried++
+		}
+	}
+
+	return retried, nil
+}
 
+func (r *RetryUsecase) retryDueStep(ctx context.Context, step sqlc.FindDueInstanceStepRetriesRow) (bool, error) {
+	unlock := r.oc.instances.Lock(step.WorkflowInstanceID)
+	defer unlock()
+
+	rows, err := r.queries.ClaimInstanceStepRetry(ctx, step.EventID)
 	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("claim retry: %w", err)
 	}
 
-	err = r.producer.SendMessage(insStep.Topic, uuid.New().String(), bytes)
+	if rows == 0 {
+		return false, nil
+	}
+
+	insStep, err := r.queries.FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
+		EventID:            step.EventID,
+		WorkflowInstanceID: step.WorkflowInstanceID,
+	})
+
+	if err == nil {
+		_, err = r.resend(ctx, insStep, nil, "")
+	}
 
 	if err != nil {
-		return nil, err
+		// leave it for an operator instead of stranding it in progress
+		markErr := r.queries.UpdateWorkflowInstanceStepStatus(ctx, sqlc.UpdateWorkflowInstanceStepStatusParams{
+			Status:  dto.ERROR.String(),
+			EventID: step.EventID,
+		})
+		if markErr != nil {
+			log.Printf("Error marking step %s as error: %v", step.EventID, markErr)
+		}
+		return false, err
 	}
 
-	return &gevent, nil
+	return true, nil
 }
 
-func (r *RetryUsecase) RetryFailedInstanceStep(ctx context.Context, req *dto.RetryRequest) (*event.GlobalEvent[any, any], error) {
-
-	insStep, err := r.queries.FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
-		EventID:            req.EventID,
-		WorkflowInstanceID: req.InstanceID,
-	})
+// requestPatch is an operator's change to the request of a retried step.
+type requestPatch struct {
+	patch *jsonpatch.Patch
+	raw   json.RawMessage
+}
 
+// resend sends the step's message again, with its request patched when patch
+// is set and under state instead of the original one when it is not empty.
+func (r *RetryUsecase) resend(ctx context.Context, insStep sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow, patch *requestPatch, state string) (*event.GlobalEvent[any, any], error) {
+	// a reply to a step of a saga that is no longer running would advance it again
+	instance, err := r.queries.FindWorkflowInstanceByID(ctx, insStep.WorkflowInstanceID)
 	if err != nil {
 		return nil, err
 	}
 
-	if insStep.Status != dto.ERROR.String() {
-		return nil, errors.New("step is not in failed state")
+	if instance.Status != dto.IN_PROGRESS.String() {
+		return nil, ErrInstanceNotRetryable
 	}
 
 	if !insStep.EventMessage.Valid {
@@ -120,7 +196,20 @@ func (r *RetryUsecase) RetryFailedInstanceStep(ctx context.Context, req *dto.Ret
 		"retry", "success", eventMsg.Payload)
 
 	gevent.Payload.Request = eventMsg.Payload.Request
+	if patch != nil {
+		request, err := patch.patch.Apply(eventMsg.Payload.Request)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := request.(map[string]any); !ok {
+			return nil, fmt.Errorf("%w: the patched request must be a JSON object", jsonpatch.ErrInvalidPatch)
+		}
+		gevent.Payload.Request = request
+	}
 	gevent.State = eventMsg.State
+	if state != "" {
+		gevent.State = state
+	}
 	gevent.EventType = eventMsg.EventType
 	gevent.InstanceID = eventMsg.InstanceID
 	gevent.EventID = eventMsg.EventID
@@ -131,22 +220,44 @@ func (r *RetryUsecase) RetryFailedInstanceStep(ctx context.Context, req *dto.Ret
 		return nil, err
 	}
 
-	err = r.queries.UpdateWorkflowInstanceStep(ctx, sqlc.UpdateWorkflowInstanceStepParams{
-		Status:       dto.IN_PROGRESS.String(),
-		EventMessage: sql.NullString{String: string(bytes), Valid: true},
-		StartedAt:    sql.NullTime{Time: time.Now(), Valid: true},
-		EventID:      gevent.EventID,
+	err = r.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		err := q.UpdateWorkflowInstanceStep(ctx, sqlc.UpdateWorkflowInstanceStepParams{
+			Status:       dto.IN_PROGRESS.String(),
+			EventMessage: sql.NullString{String: string(bytes), Valid: true},
+			StartedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+			EventID:      gevent.EventID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		// the reply to a resend reuses the event id, so let it through the inbox
+		if err := q.DeleteProcessedEvents(ctx, gevent.EventID); err != nil {
+			return err
+		}
+
+		// keep the original message next to the patched one
+		if patch != nil {
+			err := q.CreateRetryAudit(ctx, sqlc.CreateRetryAuditParams{
+				WorkflowInstanceID: gevent.InstanceID,
+				EventID:            gevent.EventID,
+				PatchType:          patch.patch.Type,
+				Patch:              patch.raw,
+				OriginalMessage:    insStep.EventMessage.String,
+				RetriedMessage:     string(bytes),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return enqueueMessage(ctx, q, insStep.Topic, gevent.InstanceID, bytes)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.producer.SendMessage(insStep.Topic, uuid.New().String(), bytes)
-
-	if err != nil {
-		return nil, err
-	}
-
 	return &gevent, nil
 }
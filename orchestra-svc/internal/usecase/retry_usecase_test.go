@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"orchestra-svc/pkg/jsonpatch"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const paymentEventMessage = `{"event_id":"event-001","instance_id":"instance-001","event_type":"order_process","state":"product_reservation_success","payload":{"request":{"ref_id":"R-1","amount":100,"account_bank_id":""}}}`
+
+func TestRetryUsecase_RetryFailedInstanceStep_Patch(t *testing.T) {
+	testCases := []struct {
+		name      string
+		patchType string
+		patch     string
+	}{
+		{name: "merge patch", patch: `{"account_bank_id":"ACC-1","amount":120}`},
+		{name: "json patch", patchType: jsonpatch.JSON, patch: `[{"op":"replace","path":"/account_bank_id","value":"ACC-1"},{"op":"replace","path":"/amount","value":120}]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+			passThroughTx(store)
+
+			ctx := context.Background()
+
+			store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+				EventID:            "event-001",
+				WorkflowInstanceID: "instance-001",
+				Status:             "error",
+				Topic:              "payment-topic",
+				EventMessage:       sql.NullString{String: paymentEventMessage, Valid: true},
+			}, nil)
+			store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "in_progress"}, nil)
+			store.EXPECT().UpdateWorkflowInstanceStep(ctx, gomock.Any()).Return(nil)
+			store.EXPECT().DeleteProcessedEvents(ctx, "event-001").Return(nil)
+			store.EXPECT().CreateRetryAudit(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, arg sqlc.CreateRetryAuditParams) error {
+					assert.Equal(t, "instance-001", arg.WorkflowInstanceID)
+					assert.Equal(t, "event-001", arg.EventID)
+					assert.NotEmpty(t, arg.PatchType)
+					assert.JSONEq(t, tc.patch, string(arg.Patch))
+					assert.Equal(t, paymentEventMessage, arg.OriginalMessage)
+					assert.Contains(t, arg.RetriedMessage, `"account_bank_id":"ACC-1"`)
+					return nil
+				})
+			store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+					assert.Equal(t, "payment-topic", arg.Topic)
+					assert.Equal(t, "instance-001", arg.MessageKey)
+					return nil
+				})
+
+			gevent, err := rc.RetryFailedInstanceStep(ctx, &dto.RetryRequest{
+				EventID:    "event-001",
+				InstanceID: "instance-001",
+				PatchType:  tc.patchType,
+				Patch:      json.RawMessage(tc.patch),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]any{"ref_id": "R-1", "amount": float64(120), "account_bank_id": "ACC-1"}, gevent.Payload.Request)
+		})
+	}
+}
+
+func TestRetryUsecase_RetryFailedInstanceStep_InvalidPatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+
+	ctx := context.Background()
+
+	_, err := rc.RetryFailedInstanceStep(ctx, &dto.RetryRequest{
+		EventID:    "event-001",
+		InstanceID: "instance-001",
+		Patch:      json.RawMessage(`[{"op":"rename","path":"/amount"}]`),
+	})
+	assert.ErrorIs(t, err, jsonpatch.ErrInvalidPatch)
+
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		EventID:            "event-001",
+		WorkflowInstanceID: "instance-001",
+		Status:             "error",
+		EventMessage:       sql.NullString{String: paymentEventMessage, Valid: true},
+	}, nil).Times(2)
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "in_progress"}, nil).Times(2)
+
+	_, err = rc.RetryFailedInstanceStep(ctx, &dto.RetryRequest{
+		EventID:    "event-001",
+		InstanceID: "instance-001",
+		Patch:      json.RawMessage(`[{"op":"test","path":"/amount","value":99}]`),
+	})
+	assert.ErrorIs(t, err, jsonpatch.ErrInvalidPatch)
+
+	_, err = rc.RetryFailedInstanceStep(ctx, &dto.RetryRequest{
+		EventID:    "event-001",
+		InstanceID: "instance-001",
+		Patch:      json.RawMessage(`"not an object"`),
+	})
+	assert.ErrorIs(t, err, jsonpatch.ErrInvalidPatch)
+}
+
+func TestRetryUsecase_ProductQuantityRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+	passThroughTx(store)
+
+	ctx := context.Background()
+	original := `{"event_id":"event-001","instance_id":"instance-001","event_type":"order_process","state":"user_validation_success","payload":{"request":{"product_id":"P-1","quantity":5}}}`
+
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		EventID:            "event-001",
+		WorkflowInstanceID: "instance-001",
+		Status:             "error",
+		Topic:              "product-topic",
+		EventMessage:       sql.NullString{String: original, Valid: true},
+	}, nil)
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "in_progress"}, nil)
+	store.EXPECT().UpdateWorkflowInstanceStep(ctx, gomock.Any()).Return(nil)
+	store.EXPECT().DeleteProcessedEvents(ctx, "event-001").Return(nil)
+	store.EXPECT().CreateRetryAudit(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateRetryAuditParams) error {
+			assert.Equal(t, jsonpatch.Merge, arg.PatchType)
+			assert.JSONEq(t, `{"quantity":2}`, string(arg.Patch))
+			return nil
+		})
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, "product-topic", arg.Topic)
+			return nil
+		})
+
+	gevent, err := rc.ProductQuantityRetry(ctx, &dto.ProductQuantityRetryRequest{
+		EventID:    "event-001",
+		InstanceID: "instance-001",
+		Quantity:   2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "product_retry", gevent.State)
+	assert.Equal(t, map[string]any{"product_id": "P-1", "quantity": float64(2)}, gevent.Payload.Request)
+}
+
+func TestRetryUsecase_RetryFailedInstanceStep_InstanceNotInProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+
+	ctx := context.Background()
+
+	for _, status := range []string{"aborted", "compensating", "compensated", "failed", "complete"} {
+		store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+			EventID:            "event-001",
+			WorkflowInstanceID: "instance-001",
+			Status:             "error",
+			EventMessage:       sql.NullString{String: paymentEventMessage, Valid: true},
+		}, nil)
+		store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: status}, nil)
+
+		_, err := rc.RetryFailedInstanceStep(ctx, &dto.RetryRequest{
+			EventID:    "event-001",
+			InstanceID: "instance-001",
+		})
+		assert.ErrorIs(t, err, ErrInstanceNotRetryable, status)
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/sqlc"
+	"strconv"
+	"time"
+)
+
+// defaultStatsWindow is used when a request does not set from.
+const defaultStatsWindow = 24 * time.Hour
+
+var ErrInvalidStatsWindow = errors.New("invalid stats window")
+
+type StatsUsecase struct {
+	queries sqlc.Store
+}
+
+func NewStatsUsecase(queries sqlc.Store) *StatsUsecase {
+	return &StatsUsecase{
+		queries: queries,
+	}
+}
+
+// StepStats returns the latency percentiles and outcomes of every step
+// started in the window, which defaults to the last 24 hours. Steps a guard
+// skipped are left out, and a step compensated after it succeeded counts as
+// a success. Latency only covers steps that were sent and answered, not the
+// ones the orchestrator failed or cancelled itself.
+func (s *StatsUsecase) StepStats(ctx context.Context, req *dto.StepStatsRequest) (*dto.StepStatsResponse, error) {
+	to := req.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	from := req.From
+	if from.IsZero() {
+		from = to.Add(-defaultStatsWindow)
+	}
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidStatsWindow)
+	}
+
+	params := sqlc.FindStepStatsParams{
+		StartedFrom: sql.NullTime{Time: from, Valid: true},
+		StartedTo:   sql.NullTime{Time: to, Valid: true},
+		Type:        sql.NullString{String: req.Type, Valid: req.Type != ""},
+		Service:     sql.NullString{String: req.Service, Valid: req.Service != ""},
+	}
+
+	rows, err := s.queries.FindStepStats(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("find step stats: %w", err)
+	}
+
+	codes, err := s.queries.FindStepStatusCodeCounts(ctx, sqlc.FindStepStatusCodeCountsParams(params))
+	if err != nil {
+		return nil, fmt.Errorf("find status codes: %w", err)
+	}
+
+	steps := make([]dto.StepStats, 0, len(rows))
+	index := make(map[[2]string]int, len(rows))
+	for _, row := range rows {
+		stats := dto.StepStats{
+			WorkflowType: row.WorkflowType,
+			Step:         row.StepName,
+			Service:      row.Service,
+			Total:        row.Total,
+			Success:      row.SuccessCount,
+			Error:        row.ErrorCount,
+			Timeout:      row.TimeoutCount,
+			LatencyMs: dto.StepLatency{
+				P50: row.P50Ms,
+				P95: row.P95Ms,
+				P99: row.P99Ms,
+			},
+			StatusCodes: map[string]int64{},
+		}
+
+		if row.Total > 0 {
+			stats.SuccessRate = float64(row.SuccessCount) / float64(row.Total)
+		}
+
+		index[[2]string{row.WorkflowType, row.StepName}] = len(steps)
+		steps = append(steps, stats)
+	}
+
+	for _, code := range codes {
+		i, ok := index[[2]string{code.WorkflowType, code.StepName}]
+		if !ok {
+			continue
+		}
+
+		key := "none"
+		if code.StatusCode.Valid {
+			key = strconv.Itoa(int(code.StatusCode.Int32))
+		}
+		steps[i].StatusCodes[key] += code.Count
+	}
+
+	return &dto.StepStatsResponse{
+		From:  from,
+		To:    to,
+		Steps: steps,
+	}, nil
+}
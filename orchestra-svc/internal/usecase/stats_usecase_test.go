@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatsUsecase_StepStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	sc := NewStatsUsecase(store)
+
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	params := sqlc.FindStepStatsParams{
+		StartedFrom: sql.NullTime{Time: from, Valid: true},
+		StartedTo:   sql.NullTime{Time: to, Valid: true},
+		Type:        sql.NullString{String: "order_process", Valid: true},
+	}
+
+	store.EXPECT().FindStepStats(ctx, params).Return([]sqlc.FindStepStatsRow{
+		{WorkflowType: "order_process", StepName: "payment", Service: "payment-svc", Total: 10, SuccessCount: 7, ErrorCount: 2, TimeoutCount: 1, P50Ms: 120, P95Ms: 900, P99Ms: 2400},
+		{WorkflowType: "order_process", StepName: "user_validation", Service: "user-svc", Total: 4, SuccessCount: 4, P50Ms: 15, P95Ms: 30, P99Ms: 31},
+	}, nil)
+	store.EXPECT().FindStepStatusCodeCounts(ctx, sqlc.FindStepStatusCodeCountsParams(params)).Return([]sqlc.FindStepStatusCodeCountsRow{
+		{WorkflowType: "order_process", StepName: "payment", StatusCode: sql.NullInt32{Int32: 200, Valid: true}, Count: 7},
+		{WorkflowType: "order_process", StepName: "payment", StatusCode: sql.NullInt32{Int32: 502, Valid: true}, Count: 2},
+		{WorkflowType: "order_process", StepName: "payment", Count: 1},
+		{WorkflowType: "order_process", StepName: "user_validation", StatusCode: sql.NullInt32{Int32: 200, Valid: true}, Count: 4},
+	}, nil)
+
+	response, err := sc.StepStats(ctx, &dto.StepStatsRequest{Type: "order_process", From: from, To: to})
+	assert.NoError(t, err)
+	assert.Len(t, response.Steps, 2)
+
+	payment := response.Steps[0]
+	assert.Equal(t, "payment", payment.Step)
+	assert.Equal(t, int64(10), payment.Total)
+	assert.Equal(t, int64(2), payment.Error)
+	assert.Equal(t, int64(1), payment.Timeout)
+	assert.InDelta(t, 0.7, payment.SuccessRate, 0.0001)
+	assert.Equal(t, dto.StepLatency{P50: 120, P95: 900, P99: 2400}, payment.LatencyMs)
+	assert.Equal(t, map[string]int64{"200": 7, "502": 2, "none": 1}, payment.StatusCodes)
+
+	assert.Equal(t, map[string]int64{"200": 4}, response.Steps[1].StatusCodes)
+	assert.Equal(t, 1.0, response.Steps[1].SuccessRate)
+}
+
+func TestStatsUsecase_StepStats_Window(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	sc := NewStatsUsecase(store)
+
+	ctx := context.Background()
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	_, err := sc.StepStats(ctx, &dto.StepStatsRequest{From: to, To: to})
+	assert.ErrorIs(t, err, ErrInvalidStatsWindow)
+
+	// without from the window covers the last day
+	store.EXPECT().FindStepStats(ctx, sqlc.FindStepStatsParams{
+		StartedFrom: sql.NullTime{Time: to.Add(-24 * time.Hour), Valid: true},
+		StartedTo:   sql.NullTime{Time: to, Valid: true},
+	}).Return([]sqlc.FindStepStatsRow{}, nil)
+	store.EXPECT().FindStepStatusCodeCounts(ctx, gomock.Any()).Return([]sqlc.FindStepStatusCodeCountsRow{}, nil)
+
+	response, err := sc.StepStats(ctx, &dto.StepStatsRequest{To: to})
+	assert.NoError(t, err)
+	assert.Equal(t, to.Add(-24*time.Hour), response.From)
+	assert.Empty(t, response.Steps)
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+	"slices"
+)
+
+// Actions recorded on the events an operator injects for a step.
+const (
+	actionSkip          = "skip"
+	actionForceComplete = "force_complete"
+)
+
+var (
+	ErrStepNotOverridable     = errors.New("step is not failed or in progress")
+	ErrInstanceNotOverridable = errors.New("workflow instance is not in progress")
+	ErrInvalidStepState       = errors.New("state is not emitted by the step")
+)
+
+func isOperatorAction(action string) bool {
+	return action == actionSkip || action == actionForceComplete
+}
+
+// SkipInstanceStep marks the step skipped and advances the saga as if the
+// service had replied with the step's success state.
+func (r *RetryUsecase) SkipInstanceStep(ctx context.Context, req *dto.StepOverrideRequest) (*event.GlobalEvent[any, any], error) {
+	return r.overrideInstanceStep(ctx, req, actionSkip, dto.SKIPPED.String(), nil)
+}
+
+// ForceCompleteInstanceStep marks the step succeeded with the operator's
+// response and advances the saga as if the service had sent it.
+func (r *RetryUsecase) ForceCompleteInstanceStep(ctx context.Context, req *dto.StepOverrideRequest) (*event.GlobalEvent[any, any], error) {
+	return r.overrideInstanceStep(ctx, req, actionForceComplete, dto.COMPLETE.String(), req.Response)
+}
+
+func (r *RetryUsecase) overrideInstanceStep(ctx context.Context, req *dto.StepOverrideRequest, action, status string, response any) (*event.GlobalEvent[any, any], error) {
+	unlock := r.oc.instances.Lock(req.InstanceID)
+	defer unlock()
+
+	insStep, err := r.queries.FindWorkflowInstanceStepsByEventIDAndInsID(ctx, sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDParams{
+		EventID:            req.EventID,
+		WorkflowInstanceID: req.InstanceID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !dto.IsFailureStatus(insStep.Status) && insStep.Status != dto.IN_PROGRESS.String() {
+		return nil, ErrStepNotOverridable
+	}
+
+	// a finished, compensating or aborting saga must not be advanced again
+	instance, err := r.queries.FindWorkflowInstanceByID(ctx, req.InstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if instance.Status != dto.IN_PROGRESS.String() {
+		return nil, ErrInstanceNotOverridable
+	}
+
+	if !insStep.EventMessage.Valid {
+		return nil, errors.New("event message is not valid")
+	}
+
+	eventMsg, err := event.FromJSON[any, any]([]byte(insStep.EventMessage.String))
+
+	if err != nil {
+		return nil, err
+	}
+
+	state := req.State
+	if state == "" && len(insStep.Emits) > 0 {
+		state = insStep.Emits[0]
+	}
+
+	if !slices.Contains(insStep.Emits, state) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStepState, state)
+	}
+
+	gevent := event.NewGlobalEvent[any, any](
+		action, status, event.BasePayload[any, any]{
+			Request:  eventMsg.Payload.Request,
+			Response: response,
+		})
+
+	// reply on behalf of the step's service so its payload key is filled
+	gevent.Source = insStep.Service
+	gevent.State = state
+	gevent.EventType = eventMsg.EventType
+	gevent.InstanceID = eventMsg.InstanceID
+	gevent.EventID = eventMsg.EventID
+	gevent.StatusCode = 200
+
+	// a reply the service still sends later would advance the saga twice.
+	// It is recorded in the transaction that advances the saga, so a failed
+	// override leaves the real reply to be processed.
+	err = r.queries.ExecTxStore(ctx, func(s sqlc.Store) error {
+		err := s.CreateProcessedEvent(ctx, sqlc.CreateProcessedEventParams{
+			EventID: gevent.EventID,
+			Source:  gevent.Source,
+			State:   gevent.State,
+		})
+		if err != nil {
+			return fmt.Errorf("record processed event: %w", err)
+		}
+
+		return r.oc.WithStore(s).processWorkflow(ctx, gevent)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &gevent, nil
+}
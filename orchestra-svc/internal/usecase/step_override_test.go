@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const overrideEventMessage = `{"event_id":"event-001","instance_id":"instance-001","event_type":"order_process","state":"product_reservation_success","payload":{"request":{"amount":100}}}`
+
+func TestRetryUsecase_SkipInstanceStep_NotOverridable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		EventID: "event-001",
+		Status:  "success",
+	}, nil)
+
+	_, err := rc.SkipInstanceStep(ctx, &dto.StepOverrideRequest{EventID: "event-001", InstanceID: "instance-001"})
+	assert.ErrorIs(t, err, ErrStepNotOverridable)
+}
+
+func TestRetryUsecase_SkipInstanceStep_FinishedInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+
+	ctx := context.Background()
+
+	for _, status := range []string{"failed", "compensating", "compensated", "completed", "aborted", "aborting"} {
+		store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+			EventID:      "event-001",
+			Status:       "error",
+			EventMessage: sql.NullString{String: overrideEventMessage, Valid: true},
+			Emits:        []string{"payment_success", "payment_failed"},
+		}, nil)
+		store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: status}, nil)
+
+		_, err := rc.SkipInstanceStep(ctx, &dto.StepOverrideRequest{EventID: "event-001", InstanceID: "instance-001"})
+		assert.ErrorIs(t, err, ErrInstanceNotOverridable, status)
+	}
+}
+
+func TestRetryUsecase_ForceCompleteInstanceStep_UnknownState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		EventID:      "event-001",
+		Status:       "error",
+		EventMessage: sql.NullString{String: overrideEventMessage, Valid: true},
+		Emits:        []string{"payment_success", "payment_failed"},
+	}, nil)
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "in_progress"}, nil)
+
+	_, err := rc.ForceCompleteInstanceStep(ctx, &dto.StepOverrideRequest{
+		EventID:    "event-001",
+		InstanceID: "instance-001",
+		State:      "refund_success",
+	})
+	assert.ErrorIs(t, err, ErrInvalidStepState)
+}
+
+func TestRetryUsecase_ForceCompleteInstanceStep_AdvancesSaga(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	passThroughTxStore(store)
+	rc := NewRetryUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+
+	ctx := context.Background()
+
+	store.EXPECT().FindWorkflowInstanceStepsByEventIDAndInsID(ctx, gomock.Any()).Return(sqlc.FindWorkflowInstanceStepsByEventIDAndInsIDRow{
+		EventID:      "event-001",
+		Status:       "timeout",
+		EventMessage: sql.NullString{String: overrideEventMessage, Valid: true},
+		Service:      "payment-svc",
+		Emits:        []string{"payment_success", "payment_failed"},
+	}, nil)
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "in_progress"}, nil).Times(2)
+	// the reply is recorded before the saga is advanced
+	gomock.InOrder(
+		store.EXPECT().CreateProcessedEvent(ctx, sqlc.CreateProcessedEventParams{
+			EventID: "event-001",
+			Source:  "payment-svc",
+			State:   "payment_success",
+		}).Return(nil),
+		store.EXPECT().CreateProcessLog(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg sqlc.CreateProcessLogParams) error {
+				assert.Equal(t, "payment_success", arg.State)
+				assert.Contains(t, arg.EventMessage, `"action":"force_complete"`)
+				return nil
+			}),
+	)
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{ID: 1, Type: "order_process"}, nil)
+	store.EXPECT().FindInstanceStepByEventID(ctx, "event-001").Return(sqlc.WorkflowInstanceStep{EventID: "event-001", Status: "timeout"}, nil)
+	store.EXPECT().UpdateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.UpdateWorkflowInstanceStepParams) error {
+			assert.Equal(t, "success", arg.Status)
+			assert.Equal(t, `{"transaction_id":"T-1"}`, arg.Response.String)
+			return nil
+		})
+	store.EXPECT().FindStepsByTypeAndState(ctx, gomock.Any()).Return([]sqlc.FindStepsByTypeAndStateRow{}, nil)
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "success", StepID: 4},
+	}, nil)
+	store.EXPECT().UpdateWorkflowInstance(ctx, sqlc.UpdateWorkflowInstanceParams{
+		Status: "completed",
+		ID:     "instance-001",
+	}).Return(nil)
+
+	gevent, err := rc.ForceCompleteInstanceStep(ctx, &dto.StepOverrideRequest{
+		EventID:    "event-001",
+		InstanceID: "instance-001",
+		Response:   map[string]any{"transaction_id": "T-1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "payment-svc", gevent.Source)
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+	"time"
+)
+
+var errStepTimedOut = errors.New("step timed out")
+
+type SweeperUsecase struct {
+	queries sqlc.Store
+	oc      *OrchestraUsecase
+	batch   int32
+}
+
+func NewSweeperUsecase(
+	queries sqlc.Store,
+	oc *OrchestraUsecase,
+	batch int,
+) *SweeperUsecase {
+	return &SweeperUsecase{
+		queries: queries,
+		oc:      oc,
+		batch:   int32(batch),
+	}
+}
+
+// SweepTimedOutSteps fails or retries every in progress step that has been
+// waiting for a reply longer than its step's timeout_seconds.
+func (s *SweeperUsecase) SweepTimedOutSteps(ctx context.Context) (int, error) {
+	steps, err := s.queries.FindTimedOutInstanceSteps(ctx, s.batch)
+	if err != nil {
+		return 0, fmt.Errorf("find timed out steps: %w", err)
+	}
+
+	var swept int
+	for _, step := range steps {
+		ok, err := s.timeoutStep(ctx, step)
+		if err != nil {
+			log.Printf("Error timing out step %s: %v", step.EventID, err)
+		}
+
+		if ok {
+			swept++
+		}
+	}
+
+	return swept, nil
+}
+
+func (s *SweeperUsecase) timeoutStep(ctx context.Context, step sqlc.FindTimedOutInstanceStepsRow) (bool, error) {
+	// the reply may have arrived between the select and now
+	rows, err := s.queries.TimeoutInstanceStep(ctx, step.EventID)
+	if err != nil {
+		return false, fmt.Errorf("mark step timeout: %w", err)
+	}
+
+	if rows == 0 {
+		return false, nil
+	}
+
+	gevent := s.timeoutEvent(step)
+
+	if step.TimeoutAction == dto.TimeoutRetry {
+		// a step that cannot be retried must not stay timed out unnoticed
+		scheduled, err := s.scheduleRetry(ctx, gevent)
+		if err != nil {
+			log.Printf("Error scheduling retry of step %s: %v", step.EventID, err)
+		}
+
+		if scheduled {
+			return true, nil
+		}
+	}
+
+	// ProcessWorkflow logs the event and runs the usual failure path
+	err = s.oc.ProcessWorkflow(ctx, gevent)
+	if err != nil {
+		return true, fmt.Errorf("process timeout: %w", err)
+	}
+
+	return true, nil
+}
+
+// scheduleRetry hands a timed out step to the due retries under its step's
+// retry policy. Once its attempts are used up, or the instance stopped
+// running, it is left to the failure path.
+func (s *SweeperUsecase) scheduleRetry(ctx context.Context, gevent event.GlobalEvent[any, any]) (bool, error) {
+	policy, err := s.queries.FindInstanceStepRetryPolicy(ctx, gevent.EventID)
+	if err != nil {
+		return false, fmt.Errorf("find retry policy: %w", err)
+	}
+
+	if policy.InstanceStatus != dto.IN_PROGRESS.String() {
+		return false, nil
+	}
+
+	if policy.Attempts >= policy.MaxAttempts {
+		log.Printf("Step %s timed out after %d attempts", gevent.EventID, policy.Attempts)
+		return false, nil
+	}
+
+	err = s.oc.logDB(ctx, gevent)
+	if err != nil {
+		log.Println("Error logging to db: ", err)
+	}
+
+	delay := retryDelay(time.Duration(policy.RetryBaseDelayMs)*time.Millisecond, policy.Attempts)
+
+	err = s.queries.ScheduleInstanceStepRetry(ctx, sqlc.ScheduleInstanceStepRetryParams{
+		StatusCode:  sql.NullInt32{Int32: int32(gevent.StatusCode), Valid: true},
+		NextRetryAt: sql.NullTime{Time: time.Now().Add(delay), Valid: true},
+		EventID:     gevent.EventID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("schedule retry: %w", err)
+	}
+
+	log.Printf("Retry %d/%d of timed out step %s scheduled in %s", policy.Attempts+1, policy.MaxAttempts, gevent.EventID, delay)
+
+	return true, nil
+}
+
+func (s *SweeperUsecase) timeoutEvent(step sqlc.FindTimedOutInstanceStepsRow) event.GlobalEvent[any, any] {
+	var payload event.BasePayload[any, any]
+
+	if step.EventMessage.Valid {
+		original, err := event.FromJSON[any, any]([]byte(step.EventMessage.String))
+		if err == nil {
+			payload.Request = original.Payload.Request
+		}
+	}
+
+	gevent := event.NewGlobalEvent[any, any]("timeout", dto.TIMEOUT.String(), payload)
+
+	gevent.EventID = step.EventID
+	gevent.InstanceID = step.WorkflowInstanceID
+	gevent.EventType = step.WorkflowType
+	gevent.State = step.StepName + "_timeout"
+	gevent.StatusCode = http.StatusGatewayTimeout
+
+	return gevent
+}
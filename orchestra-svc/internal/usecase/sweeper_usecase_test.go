@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestSweeper(store *mockdb.MockStore) *SweeperUsecase {
+	oc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+	return NewSweeperUsecase(store, oc, 10)
+}
+
+func timedOutStep(action string) sqlc.FindTimedOutInstanceStepsRow {
+	return sqlc.FindTimedOutInstanceStepsRow{
+		EventID:            "event-001",
+		WorkflowInstanceID: "instance-001",
+		StepID:             4,
+		EventMessage:       sql.NullString{String: `{"payload":{"request":{"order_id":"O-1"}}}`, Valid: true},
+		StepName:           "payment",
+		TimeoutAction:      action,
+		WorkflowType:       "order_process",
+	}
+}
+
+func TestSweeperUsecase_SweepTimedOutSteps_ErrorFindingSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	sc := newTestSweeper(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindTimedOutInstanceSteps(ctx, int32(10)).Return(nil, fmt.Errorf("error"))
+
+	_, err := sc.SweepTimedOutSteps(ctx)
+	assert.Error(t, err)
+}
+
+func TestSweeperUsecase_SweepTimedOutSteps_AlreadyReplied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	sc := newTestSweeper(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindTimedOutInstanceSteps(ctx, int32(10)).Return([]sqlc.FindTimedOutInstanceStepsRow{timedOutStep("fail")}, nil)
+	store.EXPECT().TimeoutInstanceStep(ctx, "event-001").Return(int64(0), nil)
+
+	swept, err := sc.SweepTimedOutSteps(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, swept)
+}
+
+func TestSweeperUsecase_SweepTimedOutSteps_Fail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	sc := newTestSweeper(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindTimedOutInstanceSteps(ctx, int32(10)).Return([]sqlc.FindTimedOutInstanceStepsRow{timedOutStep("fail")}, nil)
+	store.EXPECT().TimeoutInstanceStep(ctx, "event-001").Return(int64(1), nil)
+	store.EXPECT().CreateProcessLog(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateProcessLogParams) error {
+			assert.Equal(t, "event-001", arg.EventID)
+			assert.Equal(t, "payment_timeout", arg.State)
+			assert.Equal(t, "timeout", arg.Status)
+			assert.Equal(t, int32(504), arg.StatusCode.Int32)
+			assert.Contains(t, arg.EventMessage, `"order_id":"O-1"`)
+			return nil
+		})
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{}, fmt.Errorf("error"))
+
+	swept, err := sc.SweepTimedOutSteps(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, swept)
+}
+
+func TestSweeperUsecase_SweepTimedOutSteps_Retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	sc := newTestSweeper(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindTimedOutInstanceSteps(ctx, int32(10)).Return([]sqlc.FindTimedOutInstanceStepsRow{timedOutStep("retry")}, nil)
+	store.EXPECT().TimeoutInstanceStep(ctx, "event-001").Return(int64(1), nil)
+	store.EXPECT().FindInstanceStepRetryPolicy(ctx, "event-001").Return(sqlc.FindInstanceStepRetryPolicyRow{
+		Status:           "timeout",
+		Attempts:         1,
+		MaxAttempts:      3,
+		RetryBaseDelayMs: 1000,
+		InstanceStatus:   "in_progress",
+	}, nil)
+	store.EXPECT().CreateProcessLog(ctx, gomock.Any()).Return(nil)
+	store.EXPECT().ScheduleInstanceStepRetry(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.ScheduleInstanceStepRetryParams) error {
+			assert.Equal(t, "event-001", arg.EventID)
+			assert.Equal(t, int32(504), arg.StatusCode.Int32)
+			assert.WithinDuration(t, time.Now().Add(time.Second), arg.NextRetryAt.Time, time.Second)
+			return nil
+		})
+
+	swept, err := sc.SweepTimedOutSteps(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, swept)
+}
+
+func TestSweeperUsecase_SweepTimedOutSteps_RetryExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	payloads := cache.NewPayloadCache()
+	sc := NewSweeperUsecase(store, NewOrchestraUsecase(store, payloads, OrchestraOptions{}), 10)
+
+	ctx := context.Background()
+
+	store.EXPECT().FindTimedOutInstanceSteps(ctx, int32(10)).Return([]sqlc.FindTimedOutInstanceStepsRow{timedOutStep("retry")}, nil)
+	store.EXPECT().TimeoutInstanceStep(ctx, "event-001").Return(int64(1), nil)
+	store.EXPECT().FindInstanceStepRetryPolicy(ctx, "event-001").Return(sqlc.FindInstanceStepRetryPolicyRow{
+		Status:         "timeout",
+		Attempts:       3,
+		MaxAttempts:    3,
+		InstanceStatus: "in_progress",
+	}, nil)
+	// the attempts are used up, so the timeout takes the failure path
+	store.EXPECT().CreateProcessLog(ctx, gomock.Any()).Return(nil)
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{}, fmt.Errorf("error"))
+
+	swept, err := sc.SweepTimedOutSteps(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, swept)
+
+	// the timeout has no response to keep for later steps
+	payload, err := payloads.Load(ctx, "instance-001")
+	assert.NoError(t, err)
+	assert.NotContains(t, payload, "orchestra-svc")
+}
+
+func TestOrchestraUsecase_ProcessWorkflow_IgnoresLateReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventID:    "event-001",
+		InstanceID: "instance-001",
+		EventType:  "order_process",
+		State:      "payment_success",
+		Status:     "success",
+		Source:     "payment-svc",
+	}
+
+	store.EXPECT().CreateProcessLog(ctx, gomock.Any()).Return(nil)
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{ID: 1, Type: "order_process"}, nil)
+	store.EXPECT().FindInstanceStepByEventID(ctx, "event-001").Return(sqlc.WorkflowInstanceStep{Status: "timeout"}, nil)
+
+	err := uc.ProcessWorkflow(ctx, eventMsg)
+	assert.NoError(t, err)
+}
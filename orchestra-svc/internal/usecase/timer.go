@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orchestra-svc/internal/dto"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/sqlc"
+	"time"
+)
+
+// actionTimer is recorded on the events the orchestrator emits for a timer.
+const actionTimer = "timer"
+
+const (
+	// maxTimerAttempts caps how often a timer is fired before its step is
+	// left for an operator.
+	maxTimerAttempts = 5
+	// timerRetryDelay is the backoff after the first failed firing, doubled
+	// after every further one.
+	timerRetryDelay = 10 * time.Second
+)
+
+// dispatchStep hands a started step to whoever runs it: its service through
+// the outbox, or the orchestrator itself for the other step types.
+func (o *OrchestraUsecase) dispatchStep(ctx context.Context, q sqlc.Querier, gevent event.GlobalEvent[any, any], step sqlc.FindStepsByTypeAndStateRow, eventMessage []byte) error {
+	switch step.StepType {
+	case dto.StepDelay:
+		return scheduleTimer(ctx, q, gevent, step, time.Duration(step.DelaySeconds)*time.Second)
+	case dto.StepApproval:
+		return requestApproval(ctx, q, gevent, step)
+	case dto.StepWorkflow:
+		return o.startChildWorkflow(ctx, q, gevent, step)
+	}
+
+	return enqueueMessage(ctx, q, step.StepTopic, gevent.InstanceID, eventMessage)
+}
+
+// scheduleTimer persists a timer that emits the step's first state once
+// delay has passed, so it survives a restart of the orchestrator.
+func scheduleTimer(ctx context.Context, q sqlc.Querier, gevent event.GlobalEvent[any, any], step sqlc.FindStepsByTypeAndStateRow, delay time.Duration) error {
+	if len(step.Emits) == 0 {
+		return fmt.Errorf("step %s emits no state", step.StepName)
+	}
+
+	return q.CreateTimer(ctx, sqlc.CreateTimerParams{
+		WorkflowInstanceID: gevent.InstanceID,
+		EventID:            gevent.EventID,
+		EventType:          gevent.EventType,
+		Source:             step.Service,
+		State:              step.Emits[0],
+		FireAt:             time.Now().Add(delay),
+	})
+}
+
+type TimerUsecase struct {
+	queries sqlc.Store
+	oc      *OrchestraUsecase
+}
+
+func NewTimerUsecase(queries sqlc.Store, oc *OrchestraUsecase) *TimerUsecase {
+	return &TimerUsecase{
+		queries: queries,
+		oc:      oc,
+	}
+}
+
+// FireDueTimers completes the steps whose timer is due by emitting the
+// timer's state into ProcessWorkflow.
+func (t *TimerUsecase) FireDueTimers(ctx context.Context, limit int) (int, error) {
+	due, err := t.queries.FindDueTimers(ctx, int32(limit))
+	if err != nil {
+		return 0, fmt.Errorf("find due timers: %w", err)
+	}
+
+	var fired int
+	for _, timer := range due {
+		claimed, err := t.fireTimer(ctx, timer)
+		if err != nil {
+			log.Printf("Error firing timer %d: %v", timer.ID, err)
+			t.retryTimer(ctx, timer)
+			continue
+		}
+
+		if claimed {
+			fired++
+		}
+	}
+
+	return fired, nil
+}
+
+// fireTimer claims the timer and advances its instance in one transaction,
+// so a firing that fails or is interrupted leaves the timer pending instead
+// of fired with its step never completed.
+func (t *TimerUsecase) fireTimer(ctx context.Context, timer sqlc.Timer) (bool, error) {
+	var claimed bool
+	err := t.queries.ExecTxStore(ctx, func(s sqlc.Store) error {
+		rows, err := s.ClaimTimer(ctx, timer.ID)
+		if err != nil {
+			return fmt.Errorf("claim timer: %w", err)
+		}
+
+		if rows == 0 {
+			return nil
+		}
+
+		claimed = true
+		return t.oc.WithStore(s).ProcessWorkflow(ctx, timerEvent(timer))
+	})
+
+	return claimed, err
+}
+
+// retryTimer fires a timer again after a backoff. Once its attempts are used
+// up the delay step is marked as error so an operator can skip or complete
+// it, the sweeper only times out steps sent to a service.
+func (t *TimerUsecase) retryTimer(ctx context.Context, timer sqlc.Timer) {
+	attempt := timer.Attempts + 1
+
+	if attempt < maxTimerAttempts {
+		err := t.queries.RescheduleTimer(ctx, sqlc.RescheduleTimerParams{
+			ID:     timer.ID,
+			FireAt: time.Now().Add(retryDelay(timerRetryDelay, attempt)),
+		})
+		if err != nil {
+			log.Printf("Error rescheduling timer %d: %v", timer.ID, err)
+		}
+		return
+	}
+
+	err := t.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		err := q.UpdateTimerStatus(ctx, sqlc.UpdateTimerStatusParams{
+			Status: dto.FAILED.String(),
+			ID:     timer.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.UpdateWorkflowInstanceStepStatus(ctx, sqlc.UpdateWorkflowInstanceStepStatusParams{
+			Status:  dto.ERROR.String(),
+			EventID: timer.EventID,
+		})
+	})
+	if err != nil {
+		log.Printf("Error marking timer %d as failed: %v", timer.ID, err)
+	}
+}
+
+func timerEvent(timer sqlc.Timer) event.GlobalEvent[any, any] {
+	gevent := event.NewGlobalEvent[any, any](
+		actionTimer, dto.COMPLETE.String(), event.BasePayload[any, any]{})
+
+	// reply as the step so the instance step is completed like any other
+	gevent.Source = timer.Source
+	gevent.State = timer.State
+	gevent.EventType = timer.EventType
+	gevent.InstanceID = timer.WorkflowInstanceID
+	gevent.EventID = timer.EventID
+	gevent.StatusCode = 200
+
+	return gevent
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"orchestra-svc/internal/dto/event"
+	"orchestra-svc/internal/repository/cache"
+	mockdb "orchestra-svc/internal/repository/mock"
+	"orchestra-svc/internal/repository/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrchestraUsecase_processStep_DelaySchedulesTimer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	uc := NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{})
+
+	ctx := context.Background()
+	eventMsg := event.GlobalEvent[any, any]{
+		EventType:  "order_process",
+		State:      "product_reservation_success",
+		Status:     "success",
+		InstanceID: "instance-001",
+	}
+	step := sqlc.FindStepsByTypeAndStateRow{
+		StepID:       9,
+		StepName:     "hold_reservation",
+		Service:      "orchestra-svc",
+		StepType:     "delay",
+		DelaySeconds: 900,
+		Emits:        []string{"reservation_held"},
+	}
+
+	store.EXPECT().FindStepDependenciesByStepID(ctx, int32(9)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadKeysByStepID(ctx, int32(9)).Return([]string{}, nil)
+	store.EXPECT().FindPayloadMappingsByStepID(ctx, int32(9)).Return([]sqlc.PayloadMapping{}, nil)
+
+	var stepEventID string
+	store.EXPECT().CreateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateWorkflowInstanceStepParams) (sqlc.WorkflowInstanceStep, error) {
+			assert.Equal(t, "in_progress", arg.Status)
+			stepEventID = arg.EventID
+			return sqlc.WorkflowInstanceStep{}, nil
+		})
+	store.EXPECT().CreateTimer(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateTimerParams) error {
+			assert.Equal(t, stepEventID, arg.EventID)
+			assert.Equal(t, "instance-001", arg.WorkflowInstanceID)
+			assert.Equal(t, "order_process", arg.EventType)
+			assert.Equal(t, "orchestra-svc", arg.Source)
+			assert.Equal(t, "reservation_held", arg.State)
+			assert.WithinDuration(t, time.Now().Add(15*time.Minute), arg.FireAt, time.Minute)
+			return nil
+		})
+
+	err := uc.processStep(ctx, eventMsg, sqlc.WorkflowInstance{ID: "instance-001"}, step, map[string]any{})
+	assert.NoError(t, err)
+}
+
+func TestTimerUsecase_FireDueTimers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	passThroughTxStore(store)
+	tc := NewTimerUsecase(store, NewOrchestraUsecase(store, cache.NewPayloadCache(), OrchestraOptions{}))
+
+	ctx := context.Background()
+
+	store.EXPECT().FindDueTimers(ctx, int32(10)).Return([]sqlc.Timer{
+		{ID: 1, WorkflowInstanceID: "instance-001", EventID: "event-001", EventType: "order_process", Source: "orchestra-svc", State: "reservation_held"},
+		{ID: 2, WorkflowInstanceID: "instance-002", EventID: "event-002", EventType: "order_process", Source: "orchestra-svc", State: "reservation_held"},
+		{ID: 3, WorkflowInstanceID: "instance-003", EventID: "event-003", EventType: "missing", Source: "orchestra-svc", State: "reservation_held"},
+		{ID: 4, WorkflowInstanceID: "instance-004", EventID: "event-004", EventType: "missing", Source: "orchestra-svc", State: "reservation_held", Attempts: 4},
+	}, nil)
+
+	store.EXPECT().ClaimTimer(ctx, int32(1)).Return(int64(1), nil)
+	// another orchestrator fired it first
+	store.EXPECT().ClaimTimer(ctx, int32(2)).Return(int64(0), nil)
+	store.EXPECT().ClaimTimer(ctx, int32(3)).Return(int64(1), nil)
+	store.EXPECT().ClaimTimer(ctx, int32(4)).Return(int64(1), nil)
+
+	store.EXPECT().CreateProcessLog(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateProcessLogParams) error {
+			assert.Equal(t, "reservation_held", arg.State)
+			assert.Contains(t, arg.EventMessage, `"action":"timer"`)
+			return nil
+		}).Times(3)
+	store.EXPECT().FindWorkflowByType(ctx, "order_process").Return(sqlc.Workflow{ID: 1, Type: "order_process"}, nil)
+	store.EXPECT().FindWorkflowByType(ctx, "missing").Return(sqlc.Workflow{}, errors.New("no rows")).Times(2)
+
+	store.EXPECT().FindInstanceStepByEventID(ctx, "event-001").Return(sqlc.WorkflowInstanceStep{EventID: "event-001", Status: "in_progress"}, nil)
+	store.EXPECT().UpdateWorkflowInstanceStep(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.UpdateWorkflowInstanceStepParams) error {
+			assert.Equal(t, "event-001", arg.EventID)
+			assert.Equal(t, "success", arg.Status)
+			return nil
+		})
+	store.EXPECT().FindWorkflowInstanceByID(ctx, "instance-001").Return(sqlc.WorkflowInstance{ID: "instance-001", Status: "in_progress"}, nil)
+	store.EXPECT().FindStepsByTypeAndState(ctx, sqlc.FindStepsByTypeAndStateParams{
+		Type:  "order_process",
+		State: "reservation_held",
+	}).Return([]sqlc.FindStepsByTypeAndStateRow{}, nil)
+	store.EXPECT().FindWorkflowInstanceByTypeAndID(ctx, gomock.Any()).Return([]sqlc.FindWorkflowInstanceByTypeAndIDRow{
+		{InstanceStepStatus: "in_progress", StepID: 4},
+	}, nil)
+
+	// a failed firing is retried after a backoff
+	store.EXPECT().RescheduleTimer(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.RescheduleTimerParams) error {
+			assert.Equal(t, int32(3), arg.ID)
+			assert.WithinDuration(t, time.Now().Add(timerRetryDelay), arg.FireAt, time.Second)
+			return nil
+		})
+
+	// until its attempts are used up and the step is left for an operator
+	store.EXPECT().UpdateTimerStatus(ctx, sqlc.UpdateTimerStatusParams{Status: "failed", ID: 4}).Return(nil)
+	store.EXPECT().UpdateWorkflowInstanceStepStatus(ctx, sqlc.UpdateWorkflowInstanceStepStatusParams{Status: "error", EventID: "event-004"}).Return(nil)
+
+	fired, err := tc.FireDueTimers(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fired)
+}
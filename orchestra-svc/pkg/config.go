@@ -3,6 +3,9 @@ package pkg
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,11 +16,32 @@ type Config struct {
 	DBSource         string
 	KafkaBroker      string
 	OrchestraTopic   string
+	EventsTopic      string
 	OrderTopic       string
 	UserTopic        string
 	ProductTopic     string
 	UserProductTopic string
 	GroupID          string
+	ConsumerWorkers  int
+	PayloadStore     string
+	PayloadTTL       time.Duration
+	PayloadCleanup   time.Duration
+	WorkflowTopics   []string
+	SweepInterval    time.Duration
+	SweepBatchSize   int
+	RetryInterval    time.Duration
+	RetryBatchSize   int
+	RetryJobInterval time.Duration
+	RetryJobRate     int
+	TimerInterval    time.Duration
+	TimerBatchSize   int
+	ApprovalInterval time.Duration
+	ApprovalBatch    int
+	OutboxInterval   time.Duration
+	OutboxBatchSize  int
+	DeadLetterTopic  string
+	DeadLetterTopics []string
+	DeadLetterTries  int
 }
 
 func LoadConfig() *Config {
@@ -33,10 +57,71 @@ func LoadConfig() *Config {
 		DBSource:         os.Getenv("DB_SOURCE"),
 		KafkaBroker:      os.Getenv("KAFKA_BROKER"),
 		OrchestraTopic:   os.Getenv("ORCHESTRA_TOPIC"),
+		EventsTopic:      os.Getenv("WORKFLOW_EVENTS_TOPIC"),
 		OrderTopic:       os.Getenv("ORDER_TOPIC"),
 		UserTopic:        os.Getenv("USER_TOPIC"),
 		ProductTopic:     os.Getenv("PRODUCT_TOPIC"),
 		UserProductTopic: os.Getenv("USER_PRODUCT_TOPIC"),
 		GroupID:          os.Getenv("GROUP_ID"),
+		ConsumerWorkers:  getInt("CONSUMER_WORKERS", 1),
+		PayloadStore:     os.Getenv("PAYLOAD_STORE"),
+		PayloadTTL:       getDuration("PAYLOAD_TTL", 24*time.Hour),
+		PayloadCleanup:   getDuration("PAYLOAD_CLEANUP_INTERVAL", 10*time.Minute),
+		WorkflowTopics:   getList("WORKFLOW_TOPICS"),
+		SweepInterval:    getDuration("SWEEP_INTERVAL", 30*time.Second),
+		SweepBatchSize:   getInt("SWEEP_BATCH_SIZE", 100),
+		RetryInterval:    getDuration("RETRY_POLL_INTERVAL", time.Second),
+		RetryBatchSize:   getInt("RETRY_BATCH_SIZE", 100),
+		RetryJobInterval: getDuration("RETRY_JOB_INTERVAL", time.Second),
+		RetryJobRate:     getInt("RETRY_JOB_RATE", 10),
+		TimerInterval:    getDuration("TIMER_POLL_INTERVAL", time.Second),
+		TimerBatchSize:   getInt("TIMER_BATCH_SIZE", 100),
+		ApprovalInterval: getDuration("APPROVAL_EXPIRY_INTERVAL", 10*time.Second),
+		ApprovalBatch:    getInt("APPROVAL_EXPIRY_BATCH_SIZE", 100),
+		OutboxInterval:   getDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:  getInt("OUTBOX_BATCH_SIZE", 100),
+		DeadLetterTopic:  os.Getenv("DEAD_LETTER_TOPIC"),
+		DeadLetterTopics: getList("DEAD_LETTER_TOPICS"),
+		DeadLetterTries:  getInt("DEAD_LETTER_MAX_ATTEMPTS", 3),
 	}
 }
+
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid int for %s: %v, using %d", key, err, fallback)
+		return fallback
+	}
+
+	return n
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("invalid duration for %s: %v, using %s", key, err, fallback)
+		return fallback
+	}
+
+	return d
+}
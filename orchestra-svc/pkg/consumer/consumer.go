@@ -2,7 +2,6 @@ package consumer
 
 import (
 	"context"
-	"orchestra-svc/internal/delivery/messaging"
 
 	"github.com/IBM/sarama"
 )
@@ -16,7 +15,7 @@ type KafkaConsumer struct {
 func NewKafkaConsumer(
 	brokers []string, groupID string,
 	topics []string,
-	messageHandler *messaging.MessageHandler,
+	handler sarama.ConsumerGroupHandler,
 ) (*KafkaConsumer, error) {
 	config := sarama.NewConfig()
 	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
@@ -30,7 +29,7 @@ func NewKafkaConsumer(
 	return &KafkaConsumer{
 		consumer: consumer,
 		topics:   topics,
-		handler:  messageHandler,
+		handler:  handler,
 	}, nil
 }
 
@@ -0,0 +1,125 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+type sentMessage struct {
+	topic string
+	key   string
+	value []byte
+}
+
+type fakeSender struct {
+	sent []sentMessage
+}
+
+func (f *fakeSender) SendMessageTo(topic, key string, value []byte) error {
+	f.sent = append(f.sent, sentMessage{topic: topic, key: key, value: value})
+	return nil
+}
+
+func testMessage() *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Topic:     "orchestra-topic",
+		Partition: 2,
+		Offset:    42,
+		Key:       []byte("key-1"),
+		Value:     []byte("not json"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("trace"), Value: []byte("abc")}},
+	}
+}
+
+func TestPublisher_Handle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		topic         string
+		expectedCalls int
+		expectedSent  int
+	}{
+		{
+			name:          "Success is not dead-lettered",
+			expectedCalls: 1,
+		},
+		{
+			name:          "Handler error is retried before dead-lettering",
+			err:           errors.New("db down"),
+			expectedCalls: 3,
+			expectedSent:  1,
+		},
+		{
+			name:          "Unprocessable message is dead-lettered at once",
+			err:           Unprocessable(errors.New("invalid character")),
+			expectedCalls: 1,
+			expectedSent:  1,
+		},
+		{
+			name:          "Unknown event on a shared topic is dropped",
+			err:           UnknownEvent("order_process", "payment_success"),
+			topic:         "shared-topic",
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sender := &fakeSender{}
+			p := NewPublisher(sender, "orchestra-svc-dlq", "orchestra-svc", 3, "shared-topic")
+			p.backoff = 0
+
+			msg := testMessage()
+			if tc.topic != "" {
+				msg.Topic = tc.topic
+			}
+
+			calls := 0
+			p.Handle(msg, func() error {
+				calls++
+				return tc.err
+			})
+
+			assert.Equal(t, tc.expectedCalls, calls)
+			assert.Len(t, sender.sent, tc.expectedSent)
+		})
+	}
+}
+
+func TestPublisher_Publish_KeepsOriginalMessage(t *testing.T) {
+	sender := &fakeSender{}
+	p := NewPublisher(sender, "orchestra-svc-dlq", "orchestra-svc", 3)
+
+	err := p.Publish(testMessage(), 3, errors.New("db down"))
+	assert.NoError(t, err)
+	assert.Len(t, sender.sent, 1)
+	assert.Equal(t, "orchestra-svc-dlq", sender.sent[0].topic)
+	assert.Equal(t, "key-1", sender.sent[0].key)
+
+	var record Record
+	assert.NoError(t, json.Unmarshal(sender.sent[0].value, &record))
+	assert.Equal(t, "orchestra-svc", record.Service)
+	assert.Equal(t, "orchestra-topic", record.Topic)
+	assert.Equal(t, int32(2), record.Partition)
+	assert.Equal(t, int64(42), record.Offset)
+	assert.Equal(t, []byte("not json"), record.Value)
+	assert.Equal(t, map[string]string{"trace": "abc"}, record.Headers)
+	assert.Equal(t, "db down", record.Error)
+	assert.Equal(t, 3, record.Attempts)
+}
+
+func TestPublisher_Handle_Nil(t *testing.T) {
+	var p *Publisher
+
+	calls := 0
+	p.Handle(testMessage(), func() error {
+		calls++
+		return errors.New("error")
+	})
+
+	assert.Equal(t, 1, calls)
+}
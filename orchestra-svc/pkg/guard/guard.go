@@ -0,0 +1,405 @@
+package guard
+
+import (
+	"errors"
+	"fmt"
+	"orchestra-svc/pkg/jsonpath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidGuard is returned for expressions Parse cannot read.
+var ErrInvalidGuard = errors.New("invalid guard")
+
+// Guard is a parsed boolean expression over a JSON document, for example
+//
+//	$.order-svc.amount > 1000 && $['product-svc'].type != "digital"
+//
+// Operands are JSON paths, numbers, quoted strings, true, false and null.
+// Comparisons use == != > >= < <=, and can be combined with && || ! and
+// parentheses. An operand on its own is true unless it is missing, null,
+// false, zero or an empty string.
+type Guard struct {
+	root node
+}
+
+func Parse(expr string) (*Guard, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidGuard, p.tokens[p.pos].text)
+	}
+
+	return &Guard{root: root}, nil
+}
+
+// Eval evaluates the guard against a decoded JSON document.
+func (g *Guard) Eval(doc any) bool {
+	return truthy(g.root.eval(doc))
+}
+
+type node interface {
+	eval(doc any) any
+}
+
+type literal struct{ value any }
+
+func (n literal) eval(any) any { return n.value }
+
+type pathNode struct{ path jsonpath.Path }
+
+func (n pathNode) eval(doc any) any {
+	value, _ := n.path.Get(doc)
+	return value
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(doc any) any { return !truthy(n.operand.eval(doc)) }
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n logicalNode) eval(doc any) any {
+	left := truthy(n.left.eval(doc))
+	if n.op == "&&" {
+		return left && truthy(n.right.eval(doc))
+	}
+	return left || truthy(n.right.eval(doc))
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n compareNode) eval(doc any) any {
+	left, right := n.left.eval(doc), n.right.eval(doc)
+
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+
+	if l, ok := toNumber(left); ok {
+		r, ok := toNumber(right)
+		return ok && ordered(n.op, compareFloat(l, r))
+	}
+
+	if l, ok := left.(string); ok {
+		r, ok := right.(string)
+		return ok && ordered(n.op, strings.Compare(l, r))
+	}
+
+	return false
+}
+
+func ordered(op string, cmp int) bool {
+	switch op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+func compareFloat(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+func equal(left, right any) bool {
+	if l, ok := toNumber(left); ok {
+		r, ok := toNumber(right)
+		return ok && l == r
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+
+	if n, ok := toNumber(value); ok {
+		return n != 0
+	}
+
+	return true
+}
+
+type tokenKind int
+
+const (
+	tokenOperand tokenKind = iota
+	tokenOperator
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var operators = []string{"&&", "||", "==", "!=", ">=", "<=", ">", "<", "!"}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			continue
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			i++
+			continue
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			i++
+			continue
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string in %q", ErrInvalidGuard, expr)
+			}
+			tokens = append(tokens, token{kind: tokenOperand, text: expr[i : i+end+2]})
+			i += end + 2
+			continue
+		}
+
+		if op := matchOperator(expr[i:]); op != "" {
+			tokens = append(tokens, token{kind: tokenOperator, text: op})
+			i += len(op)
+			continue
+		}
+
+		// a path may hold quoted keys, so brackets are skipped as a whole
+		start := i
+		for i < len(expr) && !isDelimiter(expr[i]) {
+			if expr[i] == '[' {
+				end := strings.IndexByte(expr[i:], ']')
+				if end < 0 {
+					return nil, fmt.Errorf("%w: unclosed bracket in %q", ErrInvalidGuard, expr)
+				}
+				i += end
+			}
+			i++
+		}
+		if i == start {
+			return nil, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidGuard, c, expr)
+		}
+		tokens = append(tokens, token{kind: tokenOperand, text: expr[start:i]})
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidGuard)
+	}
+
+	return tokens, nil
+}
+
+func matchOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isDelimiter(c byte) bool {
+	return unicode.IsSpace(rune(c)) || strings.IndexByte("()!=<>&|\"'", c) >= 0
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.text != "||" {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.text != "&&" {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok, ok := p.peek()
+	if ok && tok.text == "!" {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	tok, ok := p.peek()
+	if !ok || tok.kind != tokenOperator {
+		return left, nil
+	}
+
+	switch tok.text {
+	case "==", "!=", ">", ">=", "<", "<=":
+	default:
+		return left, nil
+	}
+	p.pos++
+
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	return compareNode{op: tok.text, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected end of expression", ErrInvalidGuard)
+	}
+	p.pos++
+
+	switch tok.kind {
+	case tokenOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next, ok := p.peek(); !ok || next.kind != tokenClose {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalidGuard)
+		}
+		p.pos++
+		return inner, nil
+	case tokenOperand:
+		return parseOperand(tok.text)
+	}
+
+	return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidGuard, tok.text)
+}
+
+func parseOperand(text string) (node, error) {
+	switch text {
+	case "true":
+		return literal{value: true}, nil
+	case "false":
+		return literal{value: false}, nil
+	case "null":
+		return literal{value: nil}, nil
+	}
+
+	if text[0] == '"' || text[0] == '\'' {
+		return literal{value: text[1 : len(text)-1]}, nil
+	}
+
+	if n, err := strconv.ParseFloat(text, 64); err == nil {
+		return literal{value: n}, nil
+	}
+
+	if text[0] != '$' {
+		return nil, fmt.Errorf("%w: unknown operand %q, paths start with $", ErrInvalidGuard, text)
+	}
+
+	path, err := jsonpath.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGuard, err)
+	}
+
+	return pathNode{path: path}, nil
+}
@@ -0,0 +1,49 @@
+package guard
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard_Eval(t *testing.T) {
+	var doc any
+	err := json.Unmarshal([]byte(`{
+		"order-svc": {"ref_id": "R-1", "amount": 1500, "express": true},
+		"product-svc": {"type": "digital", "stock": 0}
+	}`), &doc)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		expr     string
+		expected bool
+	}{
+		{expr: "$.order-svc.amount > 1000", expected: true},
+		{expr: "$.order-svc.amount <= 1000", expected: false},
+		{expr: `$['product-svc'].type == "digital"`, expected: true},
+		{expr: `$.product-svc.type != 'digital'`, expected: false},
+		{expr: "$.order-svc.express && $.order-svc.amount >= 1500", expected: true},
+		{expr: "$.product-svc.stock || $.order-svc.missing", expected: false},
+		{expr: "!($.order-svc.amount > 1000 && $.product-svc.type == \"digital\")", expected: false},
+		{expr: "$.user-svc.account_bank_id == null", expected: true},
+		{expr: "$.order-svc.ref_id > 10", expected: false},
+		{expr: "$.order-svc.ref_id", expected: true},
+		{expr: "true", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			g, err := Parse(tc.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, g.Eval(doc))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "$.a >", "($.a > 1", "$.a = 1", "amount > 1", "$.a > 1 )", `$.a == "x`, "$.a..b"} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidGuard, expr)
+	}
+}
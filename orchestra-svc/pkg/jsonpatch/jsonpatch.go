@@ -0,0 +1,367 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Merge is a JSON Merge Patch (RFC 7396) document.
+	Merge = "merge"
+	// JSON is a JSON Patch (RFC 6902) list of operations.
+	JSON = "json"
+)
+
+// ErrInvalidPatch is returned for patches that cannot be read or applied.
+var ErrInvalidPatch = errors.New("invalid patch")
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+
+	path, from []string
+	value      any
+}
+
+// Patch is a validated merge patch or JSON Patch.
+type Patch struct {
+	Type  string
+	merge any
+	ops   []operation
+}
+
+// Parse validates raw as a patch of the given type. An empty type is
+// inferred from the document: an array is a JSON Patch, anything else a
+// merge patch.
+func Parse(patchType string, raw []byte) (*Patch, error) {
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+
+	if patchType == "" {
+		patchType = Merge
+		if _, ok := doc.([]any); ok {
+			patchType = JSON
+		}
+	}
+
+	switch patchType {
+	case Merge:
+		return &Patch{Type: Merge, merge: doc}, nil
+	case JSON:
+		ops, err := parseOperations(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &Patch{Type: JSON, ops: ops}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown type %q, use merge or json", ErrInvalidPatch, patchType)
+}
+
+func parseOperations(raw []byte) ([]operation, error) {
+	var ops []operation
+	if err := json.Unmarshal(raw, &ops); err != nil {
+		return nil, fmt.Errorf("%w: a json patch is a list of operations: %w", ErrInvalidPatch, err)
+	}
+
+	for i := range ops {
+		op := &ops[i]
+
+		path, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: operation %d: %w", ErrInvalidPatch, i, err)
+		}
+		op.path = path
+
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%w: operation %d: %s needs a value", ErrInvalidPatch, i, op.Op)
+			}
+			if err := json.Unmarshal(op.Value, &op.value); err != nil {
+				return nil, fmt.Errorf("%w: operation %d: %w", ErrInvalidPatch, i, err)
+			}
+		case "move", "copy":
+			from, err := parsePointer(op.From)
+			if err != nil {
+				return nil, fmt.Errorf("%w: operation %d: %w", ErrInvalidPatch, i, err)
+			}
+			op.from = from
+		case "remove":
+			if len(op.path) == 0 {
+				return nil, fmt.Errorf("%w: operation %d: cannot remove the whole document", ErrInvalidPatch, i)
+			}
+		default:
+			return nil, fmt.Errorf("%w: operation %d: unknown op %q", ErrInvalidPatch, i, op.Op)
+		}
+	}
+
+	return ops, nil
+}
+
+// Apply patches a decoded JSON document. The document is left untouched and
+// the patched copy is returned.
+func (p *Patch) Apply(doc any) (any, error) {
+	doc, err := deepCopy(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Type == Merge {
+		return mergePatch(doc, p.merge), nil
+	}
+
+	for i, op := range p.ops {
+		doc, err = applyOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("%w: operation %d (%s %s): %w", ErrInvalidPatch, i, op.Op, op.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func mergePatch(target, patch any) any {
+	fields, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = make(map[string]any, len(fields))
+	}
+
+	for key, value := range fields {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = mergePatch(object[key], value)
+	}
+
+	return object
+}
+
+func applyOperation(doc any, op operation) (any, error) {
+	switch op.Op {
+	case "add":
+		value, err := deepCopy(op.value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, value)
+	case "replace":
+		value, err := deepCopy(op.value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := get(doc, op.path); err != nil {
+			return nil, err
+		}
+		if len(op.path) == 0 {
+			return value, nil
+		}
+		return update(doc, op.path, func(parent any, key string) (any, error) {
+			return set(parent, key, value)
+		})
+	case "remove":
+		return remove(doc, op.path)
+	case "test":
+		value, err := get(doc, op.path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, op.value) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	case "move":
+		value, err := get(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		if isPrefix(op.from, op.path) && len(op.from) < len(op.path) {
+			return nil, errors.New("cannot move a value into itself")
+		}
+		if doc, err = remove(doc, op.from); err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, value)
+	default: // copy
+		value, err := get(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = deepCopy(value); err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, value)
+	}
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[key] = value
+			return p, nil
+		case []any:
+			if key == "-" {
+				return append(p, value), nil
+			}
+			i, err := index(key, len(p)+1)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		}
+		return nil, fmt.Errorf("cannot add %q to a %T", key, parent)
+	})
+}
+
+func remove(doc any, path []string) (any, error) {
+	return update(doc, path, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			if _, ok := p[key]; !ok {
+				return nil, fmt.Errorf("%q does not exist", key)
+			}
+			delete(p, key)
+			return p, nil
+		case []any:
+			i, err := index(key, len(p))
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:i], p[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove %q from a %T", key, parent)
+	})
+}
+
+func set(parent any, key string, value any) (any, error) {
+	switch p := parent.(type) {
+	case map[string]any:
+		p[key] = value
+		return p, nil
+	case []any:
+		i, err := index(key, len(p))
+		if err != nil {
+			return nil, err
+		}
+		p[i] = value
+		return p, nil
+	}
+	return nil, fmt.Errorf("cannot set %q on a %T", key, parent)
+}
+
+// update walks to the parent of the last token and lets fn change it,
+// rebuilding the containers on the way back since arrays may grow or shrink.
+func update(node any, path []string, fn func(parent any, key string) (any, error)) (any, error) {
+	switch len(path) {
+	case 0:
+		return nil, errors.New("the whole document cannot be changed this way")
+	case 1:
+		return fn(node, path[0])
+	}
+
+	child, err := get(node, path[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := update(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return set(node, path[0], updated)
+}
+
+func get(node any, path []string) (any, error) {
+	for _, key := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			value, ok := n[key]
+			if !ok {
+				return nil, fmt.Errorf("%q does not exist", key)
+			}
+			node = value
+		case []any:
+			i, err := index(key, len(n))
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("cannot read %q from a %T", key, node)
+		}
+	}
+	return node, nil
+}
+
+func index(key string, size int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || (key != "0" && key[0] == '0') {
+		return 0, fmt.Errorf("%q is not an array index", key)
+	}
+	if i >= size {
+		return 0, fmt.Errorf("index %d is out of range", i)
+	}
+	return i, nil
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) such as /items/0/price.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("path %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func deepCopy(value any) (any, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied any
+	err = json.Unmarshal(bytes, &copied)
+	return copied, err
+}
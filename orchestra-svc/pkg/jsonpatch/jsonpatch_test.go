@@ -0,0 +1,103 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decode(t *testing.T, raw string) any {
+	t.Helper()
+	var doc any
+	assert.NoError(t, json.Unmarshal([]byte(raw), &doc))
+	return doc
+}
+
+func TestPatch_Apply(t *testing.T) {
+	original := `{"ref_id": "R-1", "amount": 1500, "account_bank_id": "", "tags": ["a", "b"], "meta": {"note": "x"}}`
+
+	testCases := []struct {
+		name      string
+		patchType string
+		patch     string
+		expected  string
+	}{
+		{
+			name:     "merge patch infers the type",
+			patch:    `{"account_bank_id": "ACC-1", "meta": {"note": null, "by": "ops"}}`,
+			expected: `{"ref_id": "R-1", "amount": 1500, "account_bank_id": "ACC-1", "tags": ["a", "b"], "meta": {"by": "ops"}}`,
+		},
+		{
+			name:      "json patch",
+			patchType: JSON,
+			patch: `[
+				{"op": "test", "path": "/amount", "value": 1500},
+				{"op": "replace", "path": "/amount", "value": 1200},
+				{"op": "add", "path": "/tags/-", "value": "c"},
+				{"op": "add", "path": "/tags/0", "value": "z"},
+				{"op": "remove", "path": "/meta/note"},
+				{"op": "copy", "from": "/ref_id", "path": "/meta/ref"},
+				{"op": "move", "from": "/account_bank_id", "path": "/bank"}
+			]`,
+			expected: `{"ref_id": "R-1", "amount": 1200, "bank": "", "tags": ["z", "a", "b", "c"], "meta": {"ref": "R-1"}}`,
+		},
+		{
+			name:     "escaped pointer",
+			patch:    `[{"op": "add", "path": "/a~1b", "value": 1}]`,
+			expected: `{"ref_id": "R-1", "amount": 1500, "account_bank_id": "", "tags": ["a", "b"], "meta": {"note": "x"}, "a/b": 1}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := decode(t, original)
+
+			p, err := Parse(tc.patchType, []byte(tc.patch))
+			assert.NoError(t, err)
+
+			patched, err := p.Apply(doc)
+			assert.NoError(t, err)
+			assert.Equal(t, decode(t, tc.expected), patched)
+			assert.Equal(t, decode(t, original), doc)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	testCases := []struct {
+		patchType string
+		patch     string
+	}{
+		{patch: `{"amount": `},
+		{patchType: "xml", patch: `{}`},
+		{patchType: JSON, patch: `{"op": "add"}`},
+		{patch: `[{"op": "add", "path": "/amount"}]`},
+		{patch: `[{"op": "remove", "path": ""}]`},
+		{patch: `[{"op": "rename", "path": "/amount"}]`},
+		{patch: `[{"op": "replace", "path": "amount", "value": 1}]`},
+	}
+
+	for _, tc := range testCases {
+		_, err := Parse(tc.patchType, []byte(tc.patch))
+		assert.ErrorIs(t, err, ErrInvalidPatch, tc.patch)
+	}
+}
+
+func TestPatch_ApplyFails(t *testing.T) {
+	doc := decode(t, `{"amount": 1500, "tags": ["a"]}`)
+
+	for _, raw := range []string{
+		`[{"op": "test", "path": "/amount", "value": 1}]`,
+		`[{"op": "replace", "path": "/missing", "value": 1}]`,
+		`[{"op": "remove", "path": "/tags/3"}]`,
+		`[{"op": "add", "path": "/amount/x", "value": 1}]`,
+		`[{"op": "move", "from": "/tags", "path": "/tags/0"}]`,
+	} {
+		p, err := Parse("", []byte(raw))
+		assert.NoError(t, err)
+
+		_, err = p.Apply(doc)
+		assert.ErrorIs(t, err, ErrInvalidPatch, raw)
+	}
+}
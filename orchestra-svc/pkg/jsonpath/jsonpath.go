@@ -0,0 +1,139 @@
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPath is returned for expressions Parse cannot read.
+var ErrInvalidPath = errors.New("invalid json path")
+
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Path is a parsed expression such as $.order-svc.items[0].price or
+// $['user-svc'].account_bank_id. The leading $ is optional.
+type Path []segment
+
+func Parse(expr string) (Path, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+
+	var path Path
+	for rest != "" {
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unclosed bracket in %q", ErrInvalidPath, expr)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path = append(path, segment{key: inner[1 : len(inner)-1]})
+				continue
+			}
+
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("%w: bad index %q in %q", ErrInvalidPath, inner, expr)
+			}
+			path = append(path, segment{index: index, isIndex: true})
+			continue
+		case '.':
+			rest = rest[1:]
+		default:
+			// a bare field is only allowed at the start, e.g. "amount"
+			if len(path) > 0 {
+				return nil, fmt.Errorf("%w: expected . or [ in %q", ErrInvalidPath, expr)
+			}
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("%w: empty field in %q", ErrInvalidPath, expr)
+		}
+		path = append(path, segment{key: rest[:end]})
+		rest = rest[end:]
+	}
+
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: %q selects nothing", ErrInvalidPath, expr)
+	}
+
+	return path, nil
+}
+
+// HasIndex reports whether any segment selects a list element.
+func (p Path) HasIndex() bool {
+	for _, seg := range p {
+		if seg.isIndex {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get walks a decoded JSON document and reports whether the path exists.
+func (p Path) Get(doc any) (any, bool) {
+	current := doc
+	for _, seg := range p {
+		if seg.isIndex {
+			list, ok := current.([]any)
+			if !ok || seg.index >= len(list) {
+				return nil, false
+			}
+			current = list[seg.index]
+			continue
+		}
+
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		current, ok = object[seg.key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// Set stores value at the path, creating the objects on the way. Indexes are
+// not supported since there is no sensible length to grow a list to.
+func (p Path) Set(doc map[string]any, value any) error {
+	object := doc
+	for i, seg := range p {
+		if seg.isIndex {
+			return fmt.Errorf("%w: cannot set an index", ErrInvalidPath)
+		}
+
+		if i == len(p)-1 {
+			object[seg.key] = value
+			return nil
+		}
+
+		next, ok := object[seg.key].(map[string]any)
+		if !ok {
+			if _, exists := object[seg.key]; exists {
+				return fmt.Errorf("%w: %s is not an object", ErrInvalidPath, seg.key)
+			}
+			next = make(map[string]any)
+			object[seg.key] = next
+		}
+		object = next
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath_Get(t *testing.T) {
+	var doc any
+	err := json.Unmarshal([]byte(`{
+		"order-svc": {"ref_id": "R-1", "amount": {"Float64": 10, "Valid": true}},
+		"product-svc": {"amount": 25.5, "items": [{"sku": "A"}, {"sku": "B"}]}
+	}`), &doc)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		expr     string
+		expected any
+		found    bool
+	}{
+		{name: "Dot notation", expr: "$.product-svc.amount", expected: 25.5, found: true},
+		{name: "Bracket notation", expr: "$['order-svc'].ref_id", expected: "R-1", found: true},
+		{name: "Without root", expr: "order-svc.amount.Float64", expected: float64(10), found: true},
+		{name: "Index", expr: "$.product-svc.items[1].sku", expected: "B", found: true},
+		{name: "Index out of range", expr: "$.product-svc.items[2].sku"},
+		{name: "Missing field", expr: "$.user-svc.account_bank_id"},
+		{name: "Field of a scalar", expr: "$.product-svc.amount.value"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := Parse(tc.expr)
+			assert.NoError(t, err)
+
+			value, found := path.Get(doc)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "$", "$.", "$.a..b", "$.a[", "$.a[x]", "$.a[-1]", "$.a[0]b"} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidPath, expr)
+	}
+}
+
+func TestPath_Set(t *testing.T) {
+	doc := map[string]any{"ref_id": "R-1", "user": "alice"}
+
+	path, err := Parse("$.payment.amount")
+	assert.NoError(t, err)
+	assert.NoError(t, path.Set(doc, 25.5))
+	assert.Equal(t, map[string]any{"amount": 25.5}, doc["payment"])
+
+	path, err = Parse("ref_id")
+	assert.NoError(t, err)
+	assert.NoError(t, path.Set(doc, "R-2"))
+	assert.Equal(t, "R-2", doc["ref_id"])
+
+	path, err = Parse("$.user.name")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, path.Set(doc, "bob"), ErrInvalidPath)
+
+	path, err = Parse("$.items[0]")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, path.Set(doc, "A"), ErrInvalidPath)
+}
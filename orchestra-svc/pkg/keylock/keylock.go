@@ -0,0 +1,55 @@
+package keylock
+
+import "sync"
+
+// Locker hands out one mutex per key, so work on different keys runs in
+// parallel while work on the same key is serialised. Entries are dropped
+// once nobody holds or waits for them.
+type Locker struct {
+	mutex sync.Mutex
+	locks map[string]*entry
+}
+
+type entry struct {
+	mutex sync.Mutex
+	refs  int
+}
+
+func New() *Locker {
+	return &Locker{
+		locks: make(map[string]*entry),
+	}
+}
+
+// Lock blocks until key is free and returns the function that releases it.
+func (l *Locker) Lock(key string) func() {
+	l.mutex.Lock()
+	e, ok := l.locks[key]
+	if !ok {
+		e = &entry{}
+		l.locks[key] = e
+	}
+	e.refs++
+	l.mutex.Unlock()
+
+	e.mutex.Lock()
+
+	return func() {
+		e.mutex.Unlock()
+
+		l.mutex.Lock()
+		e.refs--
+		if e.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mutex.Unlock()
+	}
+}
+
+// Len reports how many keys are currently held or waited for.
+func (l *Locker) Len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return len(l.locks)
+}
@@ -0,0 +1,60 @@
+package keylock
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocker_SerialisesSameKey(t *testing.T) {
+	locker := New()
+
+	var wg sync.WaitGroup
+	var holders, overlaps atomic.Int32
+	counter := 0
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			unlock := locker.Lock("instance-001")
+			defer unlock()
+
+			if holders.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			counter++
+			holders.Add(-1)
+		}()
+	}
+
+	wg.Wait()
+	assert.Zero(t, overlaps.Load())
+	assert.Equal(t, 50, counter)
+	assert.Zero(t, locker.Len())
+}
+
+func TestLocker_DifferentKeysDoNotBlock(t *testing.T) {
+	locker := New()
+
+	unlock := locker.Lock("instance-001")
+	defer unlock()
+
+	done := make(chan struct{})
+	go func() {
+		locker.Lock("instance-002")()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lock on another key was blocked")
+	}
+
+	assert.Equal(t, 1, locker.Len())
+}
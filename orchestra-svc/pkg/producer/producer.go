@@ -31,12 +31,20 @@ func NewKafkaProducer(brokers []string, topic string) (*KafkaProducer, error) {
 }
 
 func (kp *KafkaProducer) SendMessage(topic, key string, value []byte) error {
+	return kp.SendMessageWithHeaders(topic, key, value, nil)
+}
+
+func (kp *KafkaProducer) SendMessageWithHeaders(topic, key string, value []byte, headers map[string]string) error {
 	msg := &sarama.ProducerMessage{
 		Topic: topic,
 		Key:   sarama.StringEncoder(key),
 		Value: sarama.ByteEncoder(value),
 	}
 
+	for k, v := range headers {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+
 	_, _, err := kp.producer.SendMessage(msg)
 	return err
 }
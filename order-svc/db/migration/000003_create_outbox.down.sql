@@ -0,0 +1,2 @@
+-- Drop table `outbox`
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,2 @@
+-- Drop table `processed_events`
+DROP TABLE IF EXISTS processed_events;
@@ -0,0 +1,8 @@
+-- Create table `processed_events`
+CREATE TABLE processed_events (
+    event_id VARCHAR(255) NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    state VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, source)
+);
@@ -0,0 +1,4 @@
+-- name: ClaimProcessedEvent :execrows
+INSERT INTO processed_events (event_id, source, state)
+VALUES ($1, $2, $3)
+ON CONFLICT (event_id, source) DO NOTHING;
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"order-svc/internal/delivery/messaging"
+	"order-svc/internal/usecase"
 	"order-svc/pkg"
 	"order-svc/pkg/consumer"
 	"order-svc/pkg/producer"
@@ -24,6 +25,7 @@ type App struct {
 	gin    *gin.Engine
 	config *pkg.Config
 	msg    *messaging.MessageHandler
+	outbox *usecase.OutboxUsecase
 }
 
 func NewApp(db *sql.DB, gin *gin.Engine, config *pkg.Config) *App {
@@ -67,6 +69,8 @@ func (app *App) Run() {
 		}
 	}()
 
+	go app.runOutboxRelay(ctxCancel)
+
 	go func() {
 		log.Println("Starting server...")
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+func (app *App) runOutboxRelay(ctx context.Context) {
+	ticker := time.NewTicker(app.config.OutboxInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := app.outbox.RelayPending(ctx, app.config.OutboxBatch)
+			if err != nil {
+				log.Println("Error relay outbox messages: ", err)
+				continue
+			}
+
+			if sent > 0 {
+				log.Printf("Relayed %d outbox messages", sent)
+			}
+		}
+	}
+}
@@ -1,27 +1,35 @@
 package app
 
 import (
+	"expvar"
 	"order-svc/internal/delivery/http"
 	"order-svc/internal/delivery/messaging"
 	"order-svc/internal/middleware"
 	"order-svc/internal/repository/sqlc"
 	"order-svc/internal/usecase"
+	"order-svc/pkg/deadletter"
 	"order-svc/pkg/producer"
+
+	"github.com/gin-gonic/gin"
 )
 
 func (app *App) startService(orchestraProducer *producer.KafkaProducer) error {
 
 	sqlc := sqlc.NewStore(app.db)
 
-	orderUsecase := usecase.NewOrderUsecase(sqlc, orchestraProducer)
-	bankRegisUsecase := usecase.NewBankRegistrationUsecase(sqlc, orchestraProducer)
+	orderUsecase := usecase.NewOrderUsecase(sqlc, app.config.OrchestraTopic)
+	bankRegisUsecase := usecase.NewBankRegistrationUsecase(sqlc, app.config.OrchestraTopic)
+	app.outbox = usecase.NewOutboxUsecase(sqlc, orchestraProducer)
 
-	app.msg = messaging.NewMessageHandler(orderUsecase, bankRegisUsecase)
+	dlq := deadletter.NewPublisher(orchestraProducer, app.config.DLQTopic, "order-svc", app.config.DLQAttempts)
+	app.msg = messaging.NewMessageHandler(orderUsecase, bankRegisUsecase, usecase.NewInboxUsecase(sqlc), dlq)
 
 	authHandler := http.NewAuthHandler()
 	orderHandler := http.NewOrderHandler(orderUsecase)
 	bankRegisHandler := http.NewBankRegisHandler(bankRegisUsecase)
 
+	app.gin.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+
 	apiV1 := app.gin.Group("/api/v1")
 
 	authV1 := apiV1.Group("/auth")
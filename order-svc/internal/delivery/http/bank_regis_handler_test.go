@@ -12,14 +12,9 @@ import (
 	mockdb "order-svc/internal/repository/mock"
 	"order-svc/internal/repository/sqlc"
 	"order-svc/internal/usecase"
-	"order-svc/pkg/producer"
 	"testing"
 )
 
-func init() {
-	orchestraProducer, _ = producer.NewKafkaProducer([]string{"localhost:29092"}, "orchestra-topic-test")
-}
-
 func TestBankRegisHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -27,8 +22,9 @@ func TestBankRegisHandler(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		store := mockdb.NewMockStore(ctrl)                                 // Mock the store directly
-		uc := usecase.NewBankRegistrationUsecase(store, orchestraProducer) // Pass the mocked store to the usecase
+		store := mockdb.NewMockStore(ctrl)                              // Mock the store directly
+		uc := usecase.NewBankRegistrationUsecase(store, orchestraTopic) // Pass the mocked store to the usecase
+		passThroughTx(store)
 		handler := NewBankRegisHandler(uc)
 
 		testCases := []struct {
@@ -49,6 +45,7 @@ func TestBankRegisHandler(t *testing.T) {
 						Status:     "complete",
 					}
 					store.EXPECT().CreateBankAccountRegistration(gomock.Any(), gomock.Any()).Return(expectedResponse, nil)
+					store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 				},
 				setupRequest: func() *http.Request {
 					req := &dto.BankRegistrationRequest{
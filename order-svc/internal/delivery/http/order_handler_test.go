@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -14,17 +15,19 @@ import (
 	"order-svc/internal/repository/sqlc"
 	"order-svc/internal/usecase"
 	"order-svc/pkg"
-	"order-svc/pkg/producer"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
-var orchestraProducer *producer.KafkaProducer
+const orchestraTopic = "orchestra-topic-test"
 
-func init() {
-	orchestraProducer, _ = producer.NewKafkaProducer([]string{"localhost:29092"}, "orchestra-topic-test")
+func passThroughTx(store *mockdb.MockStore) {
+	store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(sqlc.Querier) error) error {
+			return fn(store)
+		}).AnyTimes()
 }
 
 func TestOrderHandler(t *testing.T) {
@@ -35,7 +38,8 @@ func TestOrderHandler(t *testing.T) {
 		defer ctrl.Finish()
 
 		store := mockdb.NewMockStore(ctrl)
-		uc := usecase.NewOrderUsecase(store, orchestraProducer)
+		uc := usecase.NewOrderUsecase(store, orchestraTopic)
+		passThroughTx(store)
 		handler := NewOrderHandler(uc)
 
 		testCases := []struct {
@@ -57,6 +61,7 @@ func TestOrderHandler(t *testing.T) {
 						Status:     dto.PROCESSING.String(),
 					}
 					store.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(*expectedOrder, nil)
+					store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 				},
 				setupRequest: func() (*http.Request, *pkg.UserInfo) {
 					req := &dto.OrderRequest{
@@ -152,7 +157,8 @@ func TestOrderHandler(t *testing.T) {
 		defer ctrl.Finish()
 
 		store := mockdb.NewMockStore(ctrl)
-		uc := usecase.NewOrderUsecase(store, orchestraProducer)
+		uc := usecase.NewOrderUsecase(store, orchestraTopic)
+		passThroughTx(store)
 		handler := NewOrderHandler(uc)
 
 		testCases := []struct {
@@ -189,6 +195,7 @@ func TestOrderHandler(t *testing.T) {
 						Amount:     sql.NullFloat64{Float64: 100, Valid: true},
 					}
 					store.EXPECT().UpdateOrder(gomock.Any(), gomock.Any()).Return(updatedOrder, nil)
+					store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 
 				},
 				setupRequest: func() (*http.Request, *pkg.UserInfo) {
@@ -288,7 +295,8 @@ func TestOrderHandler(t *testing.T) {
 		defer ctrl.Finish()
 
 		store := mockdb.NewMockStore(ctrl)
-		uc := usecase.NewOrderUsecase(store, orchestraProducer)
+		uc := usecase.NewOrderUsecase(store, orchestraTopic)
+		passThroughTx(store)
 		handler := NewOrderHandler(uc)
 
 		testCases := []struct {
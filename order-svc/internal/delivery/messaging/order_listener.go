@@ -1,23 +1,29 @@
 package messaging
 
 import (
-	"log"
+	"context"
 	"order-svc/internal/dto"
 	"order-svc/internal/dto/event"
+	"order-svc/internal/repository/sqlc"
 	"order-svc/internal/usecase"
+	"order-svc/pkg/deadletter"
 
 	"github.com/IBM/sarama"
 )
 
 type MessageHandler struct {
-	oc  *usecase.OrderUsecase
-	brc *usecase.BankRegistrationUsecase
+	oc    *usecase.OrderUsecase
+	brc   *usecase.BankRegistrationUsecase
+	inbox *usecase.InboxUsecase
+	dlq   *deadletter.Publisher
 }
 
-func NewMessageHandler(oc *usecase.OrderUsecase, brc *usecase.BankRegistrationUsecase) *MessageHandler {
+func NewMessageHandler(oc *usecase.OrderUsecase, brc *usecase.BankRegistrationUsecase, inbox *usecase.InboxUsecase, dlq *deadletter.Publisher) *MessageHandler {
 	return &MessageHandler{
-		oc:  oc,
-		brc: brc,
+		oc:    oc,
+		brc:   brc,
+		inbox: inbox,
+		dlq:   dlq,
 	}
 }
 
@@ -28,64 +34,88 @@ func (h MessageHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sar
 
 	for msg := range claim.Messages() {
 
-		eventMsg, err := event.FromJSON[dto.OrderUpdateRequest, any](msg.Value)
+		h.dlq.Handle(msg, func() error {
+			return h.consume(sess.Context(), msg.Value)
+		})
 
-		if err != nil {
-			log.Println("failed parse event: ", err.Error())
-		}
+		sess.MarkMessage(msg, "")
+	}
+	return nil
+}
 
-		switch eventMsg.EventType {
-		case event.ORDER_PROCESS.String():
-			if eventMsg.State == event.PAYMENT_SUCCESS.String() {
-				eventMsg.Payload.Request.Status = dto.COMPLETE.String()
-				err = h.oc.UpdateOrderMessaging(sess.Context(), eventMsg)
-			}
+func (h MessageHandler) consume(ctx context.Context, value []byte) error {
+	eventMsg, err := event.FromJSON[dto.OrderUpdateRequest, any](value)
 
-			if eventMsg.State == event.USER_VALIDATION_FAILED.String() {
-				eventMsg.Payload.Request.Status = dto.CANCELLED.String()
-				err = h.oc.UpdateOrderMessaging(sess.Context(), eventMsg)
-			}
+	if err != nil {
+		return deadletter.Unprocessable(err)
+	}
 
-			if eventMsg.State == event.PRODUCT_RESERVATION_FAILED.String() {
-				eventMsg.Payload.Request.Status = dto.CANCELLED.String()
-				err = h.oc.UpdateOrderMessaging(sess.Context(), eventMsg)
-			}
+	return h.inbox.Handle(ctx, eventMsg.EventID, eventMsg.Source, eventMsg.State, func(ctx context.Context, q sqlc.Querier) error {
+		return h.handle(ctx, q, value, eventMsg)
+	})
+}
 
-			if eventMsg.State == event.PRODUCT_RELEASE_SUCCESS.String() {
-				eventMsg.Payload.Request.Status = dto.CANCELLED.String()
-				err = h.oc.UpdateOrderMessaging(sess.Context(), eventMsg)
-			}
+func (h MessageHandler) handle(ctx context.Context, q sqlc.Querier, value []byte, eventMsg event.GlobalEvent[dto.OrderUpdateRequest, any]) error {
+	switch eventMsg.EventType {
+	case event.ORDER_PROCESS.String():
+		if eventMsg.State == event.PAYMENT_SUCCESS.String() {
+			eventMsg.Payload.Request.Status = dto.COMPLETE.String()
+			return h.oc.UpdateOrderMessaging(ctx, q, eventMsg)
+		}
 
-		case event.ORDER_CANCEL_PROCESS.String():
+		if eventMsg.State == event.USER_VALIDATION_FAILED.String() {
+			eventMsg.Payload.Request.Status = dto.CANCELLED.String()
+			return h.oc.UpdateOrderMessaging(ctx, q, eventMsg)
+		}
 
-			if eventMsg.State == event.USER_VALIDATION_FAILED.String() {
-				eventMsg.Payload.Request.Status = dto.COMPLETE.String()
-				err = h.oc.UpdateOrderMessaging(sess.Context(), eventMsg)
-			}
+		if eventMsg.State == event.PRODUCT_RESERVATION_FAILED.String() {
+			eventMsg.Payload.Request.Status = dto.CANCELLED.String()
+			return h.oc.UpdateOrderMessaging(ctx, q, eventMsg)
+		}
 
-			if eventMsg.State == event.REFUND_FAILED.String() {
-				eventMsg.Payload.Request.Status = dto.COMPLETE.String()
-				err = h.oc.UpdateOrderMessaging(sess.Context(), eventMsg)
-			}
+		if eventMsg.State == event.PRODUCT_RELEASE_SUCCESS.String() {
+			eventMsg.Payload.Request.Status = dto.CANCELLED.String()
+			return h.oc.UpdateOrderMessaging(ctx, q, eventMsg)
+		}
 
-			if eventMsg.State == event.REFUND_SUCCESS.String() {
-				eventMsg.Payload.Request.Status = dto.CANCELLED.String()
-				err = h.oc.UpdateOrderMessaging(sess.Context(), eventMsg)
-			}
+		if eventMsg.State == event.WORKFLOW_ABORTED.String() {
+			eventMsg.Payload.Request.Status = dto.CANCELLED.String()
+			return h.oc.UpdateOrderMessaging(ctx, q, eventMsg)
+		}
 
-		case event.BANK_ACCOUNT_REGISTRATION.String():
-			if eventMsg.State == event.USER_BANKID_UPDATED.String() {
-				eventMsg, _ := event.FromJSON[dto.BankRegistrationUpdate, any](msg.Value)
-				eventMsg.Payload.Request.Status = dto.COMPLETE.String()
-				err = h.brc.UpdateBankRegistrationMessaging(sess.Context(), eventMsg)
-			}
+	case event.ORDER_CANCEL_PROCESS.String():
+
+		if eventMsg.State == event.USER_VALIDATION_FAILED.String() {
+			eventMsg.Payload.Request.Status = dto.COMPLETE.String()
+			return h.oc.UpdateOrderMessaging(ctx, q, eventMsg)
 		}
 
-		if err != nil {
-			log.Println("failed process event: ", err.Error())
+		if eventMsg.State == event.REFUND_FAILED.String() {
+			eventMsg.Payload.Request.Status = dto.COMPLETE.String()
+			return h.oc.UpdateOrderMessaging(ctx, q, eventMsg)
 		}
 
-		sess.MarkMessage(msg, "")
+		if eventMsg.State == event.REFUND_SUCCESS.String() {
+			eventMsg.Payload.Request.Status = dto.CANCELLED.String()
+			return h.oc.UpdateOrderMessaging(ctx, q, eventMsg)
+		}
+
+	case event.BANK_ACCOUNT_REGISTRATION.String():
+		if eventMsg.State == event.USER_BANKID_UPDATED.String() {
+			eventMsg, err := event.FromJSON[dto.BankRegistrationUpdate, any](value)
+			if err != nil {
+				return deadletter.Unprocessable(err)
+			}
+			eventMsg.Payload.Request.Status = dto.COMPLETE.String()
+			return h.brc.UpdateBankRegistrationMessaging(ctx, q, eventMsg)
+		}
 	}
-	return nil
+
+	// a known state the event type does not act on, such as workflow_aborted
+	// for a cancellation, needs nothing from this service
+	if event.IsKnownEventType(eventMsg.EventType) && event.IsKnownState(eventMsg.State) {
+		return nil
+	}
+
+	return deadletter.UnknownEvent(eventMsg.EventType, eventMsg.State)
 }
@@ -20,10 +20,22 @@ const (
 	REFUND_SUCCESS
 	REFUND_FAILED
 	USER_BANKID_UPDATED
+	WORKFLOW_ABORTED
 )
 
 func (s State) String() string {
-	return [...]string{"payment_success", "user_validation_success", "product_release_success", "product_reservation_failed", "user_validation_failed", "refund_success", "refund_failed", "user_bankid_updated"}[s]
+	return [...]string{"payment_success", "user_validation_success", "product_release_success", "product_reservation_failed", "user_validation_failed", "refund_success", "refund_failed", "user_bankid_updated", "workflow_aborted"}[s]
+}
+
+// IsKnownState reports whether state is one the service knows, whether or not
+// a given event type acts on it.
+func IsKnownState(state string) bool {
+	for s := PAYMENT_SUCCESS; s <= WORKFLOW_ABORTED; s++ {
+		if s.String() == state {
+			return true
+		}
+	}
+	return false
 }
 
 type EventType int
@@ -38,6 +50,15 @@ func (e EventType) String() string {
 	return [...]string{"order_process", "order_cancel_process", "bank_account_registration"}[e]
 }
 
+func IsKnownEventType(eventType string) bool {
+	for e := ORDER_PROCESS; e <= BANK_ACCOUNT_REGISTRATION; e++ {
+		if e.String() == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 type BasePayload[R any, S any] struct {
 	Request  R `json:"request"`
 	Response S `json:"response"`
@@ -81,3 +102,16 @@ func FromJSON[R any, S any](data []byte) (GlobalEvent[R, S], error) {
 func (ge GlobalEvent[R, S]) ToJSON() ([]byte, error) {
 	return json.Marshal(ge)
 }
+
+// MessageKey partitions messages by instance so a saga's events stay in
+// order. Events sent before the orchestrator assigns an instance fall back
+// to the given ref ID, then to the event ID.
+func (ge GlobalEvent[R, S]) MessageKey(refID string) string {
+	switch {
+	case ge.InstanceID != "":
+		return ge.InstanceID
+	case refID != "":
+		return refID
+	}
+	return ge.EventID
+}
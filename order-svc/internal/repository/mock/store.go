@@ -40,6 +40,36 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcquireOutboxLease mocks base method.
+func (m *MockStore) AcquireOutboxLease(ctx context.Context, arg sqlc.AcquireOutboxLeaseParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireOutboxLease", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireOutboxLease indicates an expected call of AcquireOutboxLease.
+func (mr *MockStoreMockRecorder) AcquireOutboxLease(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireOutboxLease", reflect.TypeOf((*MockStore)(nil).AcquireOutboxLease), ctx, arg)
+}
+
+// ClaimProcessedEvent mocks base method.
+func (m *MockStore) ClaimProcessedEvent(ctx context.Context, arg sqlc.ClaimProcessedEventParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimProcessedEvent", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimProcessedEvent indicates an expected call of ClaimProcessedEvent.
+func (mr *MockStoreMockRecorder) ClaimProcessedEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimProcessedEvent", reflect.TypeOf((*MockStore)(nil).ClaimProcessedEvent), ctx, arg)
+}
+
 // CountByID mocks base method.
 func (m *MockStore) CountByID(ctx context.Context, refID string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +115,49 @@ func (mr *MockStoreMockRecorder) CreateOrder(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockStore)(nil).CreateOrder), ctx, arg)
 }
 
+// CreateOutboxMessage mocks base method.
+func (m *MockStore) CreateOutboxMessage(ctx context.Context, arg sqlc.CreateOutboxMessageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxMessage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOutboxMessage indicates an expected call of CreateOutboxMessage.
+func (mr *MockStoreMockRecorder) CreateOutboxMessage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxMessage", reflect.TypeOf((*MockStore)(nil).CreateOutboxMessage), ctx, arg)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(ctx context.Context, name string) (sqlc.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, name)
+	ret0, _ := ret[0].(sqlc.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), ctx, name)
+}
+
+// ExecTx mocks base method.
+func (m *MockStore) ExecTx(ctx context.Context, fn func(sqlc.Querier) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockStoreMockRecorder) ExecTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStore)(nil).ExecTx), ctx, fn)
+}
+
 // FindBankAccountRegistrationByUsernameOrEmail mocks base method.
 func (m *MockStore) FindBankAccountRegistrationByUsernameOrEmail(ctx context.Context, arg sqlc.FindBankAccountRegistrationByUsernameOrEmailParams) (sqlc.BankAccountRegistration, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +218,49 @@ func (mr *MockStoreMockRecorder) FindOrdersByUsername(ctx, username any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrdersByUsername", reflect.TypeOf((*MockStore)(nil).FindOrdersByUsername), ctx, username)
 }
 
+// FindPendingOutboxMessages mocks base method.
+func (m *MockStore) FindPendingOutboxMessages(ctx context.Context, limit int32) ([]sqlc.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingOutboxMessages", ctx, limit)
+	ret0, _ := ret[0].([]sqlc.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingOutboxMessages indicates an expected call of FindPendingOutboxMessages.
+func (mr *MockStoreMockRecorder) FindPendingOutboxMessages(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingOutboxMessages", reflect.TypeOf((*MockStore)(nil).FindPendingOutboxMessages), ctx, limit)
+}
+
+// MarkOutboxMessageFailed mocks base method.
+func (m *MockStore) MarkOutboxMessageFailed(ctx context.Context, arg sqlc.MarkOutboxMessageFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxMessageFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxMessageFailed indicates an expected call of MarkOutboxMessageFailed.
+func (mr *MockStoreMockRecorder) MarkOutboxMessageFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxMessageFailed", reflect.TypeOf((*MockStore)(nil).MarkOutboxMessageFailed), ctx, arg)
+}
+
+// MarkOutboxMessageSent mocks base method.
+func (m *MockStore) MarkOutboxMessageSent(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxMessageSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxMessageSent indicates an expected call of MarkOutboxMessageSent.
+func (mr *MockStoreMockRecorder) MarkOutboxMessageSent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxMessageSent", reflect.TypeOf((*MockStore)(nil).MarkOutboxMessageSent), ctx, id)
+}
+
 // UpdateBankAccountRegistration mocks base method.
 func (m *MockStore) UpdateBankAccountRegistration(ctx context.Context, arg sqlc.UpdateBankAccountRegistrationParams) (sqlc.BankAccountRegistration, error) {
 	m.ctrl.T.Helper()
@@ -34,6 +34,31 @@ type Order struct {
 	UpdatedAt  time.Time       `json:"updated_at"`
 }
 
+type Outbox struct {
+	ID         int32          `json:"id"`
+	Topic      string         `json:"topic"`
+	MessageKey string         `json:"message_key"`
+	Payload    string         `json:"payload"`
+	Status     string         `json:"status"`
+	Attempts   int32          `json:"attempts"`
+	LastError  sql.NullString `json:"last_error"`
+	CreatedAt  time.Time      `json:"created_at"`
+	SentAt     sql.NullTime   `json:"sent_at"`
+}
+
+type OutboxLease struct {
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ProcessedEvent struct {
+	EventID     string    `json:"event_id"`
+	Source      string    `json:"source"`
+	State       string    `json:"state"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
 type User struct {
 	ID   int32  `json:"id"`
 	Name string `json:"name"`
@@ -9,14 +9,20 @@ import (
 )
 
 type Querier interface {
+	AcquireOutboxLease(ctx context.Context, arg AcquireOutboxLeaseParams) (int64, error)
+	ClaimProcessedEvent(ctx context.Context, arg ClaimProcessedEventParams) (int64, error)
 	CountByID(ctx context.Context, refID string) (int64, error)
 	CreateBankAccountRegistration(ctx context.Context, arg CreateBankAccountRegistrationParams) (BankAccountRegistration, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) error
 	CreateUser(ctx context.Context, name string) (User, error)
 	FindBankAccountRegistrationByUsernameOrEmail(ctx context.Context, arg FindBankAccountRegistrationByUsernameOrEmailParams) (BankAccountRegistration, error)
 	FindOrderByID(ctx context.Context, id int32) (Order, error)
 	FindOrderByRefID(ctx context.Context, refID string) (Order, error)
 	FindOrdersByUsername(ctx context.Context, username string) ([]Order, error)
+	FindPendingOutboxMessages(ctx context.Context, limit int32) ([]Outbox, error)
+	MarkOutboxMessageFailed(ctx context.Context, arg MarkOutboxMessageFailedParams) error
+	MarkOutboxMessageSent(ctx context.Context, id int32) error
 	UpdateBankAccountRegistration(ctx context.Context, arg UpdateBankAccountRegistrationParams) (BankAccountRegistration, error)
 	UpdateOrder(ctx context.Context, arg UpdateOrderParams) (Order, error)
 }
@@ -1,9 +1,14 @@
 package sqlc
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
 
 type Store interface {
 	Querier
+	ExecTx(ctx context.Context, fn func(Querier) error) error
 }
 
 type SQLStore struct {
@@ -17,3 +22,22 @@ func NewStore(db *sql.DB) Store {
 		Queries: New(db),
 	}
 }
+
+// ExecTx runs fn inside a single database transaction, rolling back when fn
+// returns an error.
+func (store *SQLStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = fn(New(tx))
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
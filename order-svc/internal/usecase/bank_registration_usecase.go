@@ -6,66 +6,72 @@ import (
 	"order-svc/internal/dto"
 	"order-svc/internal/dto/event"
 	"order-svc/internal/repository/sqlc"
-	"order-svc/pkg/producer"
 )
 
 type BankRegistrationUsecase struct {
-	queries           sqlc.Store
-	orchestraProducer *producer.KafkaProducer
+	queries        sqlc.Store
+	orchestraTopic string
 }
 
-func NewBankRegistrationUsecase(queries sqlc.Store, producer *producer.KafkaProducer) *BankRegistrationUsecase {
+func NewBankRegistrationUsecase(queries sqlc.Store, orchestraTopic string) *BankRegistrationUsecase {
 	return &BankRegistrationUsecase{
-		queries:           queries,
-		orchestraProducer: producer,
+		queries:        queries,
+		orchestraTopic: orchestraTopic,
 	}
 }
 
 func (b *BankRegistrationUsecase) RegisterBankAccount(ctx context.Context, req *dto.BankRegistrationRequest) (*sqlc.BankAccountRegistration, error) {
 
-	bankAccount, err := b.queries.CreateBankAccountRegistration(ctx, sqlc.CreateBankAccountRegistrationParams{
-		CustomerID: uuid.New().String(),
-		Username:   req.Username,
-		Email:      req.Email,
-		Status:     dto.PROCESSING.String(),
-		Deposit:    req.Deposit,
+	var bankAccount sqlc.BankAccountRegistration
+
+	err := b.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		var err error
+
+		bankAccount, err = q.CreateBankAccountRegistration(ctx, sqlc.CreateBankAccountRegistrationParams{
+			CustomerID: uuid.New().String(),
+			Username:   req.Username,
+			Email:      req.Email,
+			Status:     dto.PROCESSING.String(),
+			Deposit:    req.Deposit,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		basePayload := event.BasePayload[dto.BankRegistrationRequest, sqlc.BankAccountRegistration]{
+			Request:  *req,
+			Response: bankAccount,
+		}
+
+		orderEvent := event.NewGlobalEvent(
+			"create",
+			"success",
+			"bank_regis_created",
+			event.BANK_ACCOUNT_REGISTRATION.String(),
+			basePayload,
+		)
+		orderEvent.StatusCode = 201
+		bytes, err := orderEvent.ToJSON()
+
+		if err != nil {
+			return err
+		}
+
+		return enqueueMessage(ctx, q, b.orchestraTopic, orderEvent.MessageKey(bankAccount.CustomerID), bytes)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	basePayload := event.BasePayload[dto.BankRegistrationRequest, sqlc.BankAccountRegistration]{
-		Request:  *req,
-		Response: bankAccount,
-	}
-
-	orderEvent := event.NewGlobalEvent(
-		"create",
-		"success",
-		"bank_regis_created",
-		event.BANK_ACCOUNT_REGISTRATION.String(),
-		basePayload,
-	)
-	orderEvent.StatusCode = 201
-	bytes, err := orderEvent.ToJSON()
-
-	if err != nil {
-		return nil, err
-	}
-
-	err = b.orchestraProducer.SendMessage(uuid.New().String(), bytes)
-
-	if err != nil {
-		return nil, err
-	}
-
 	return &bankAccount, nil
 }
 
-func (b *BankRegistrationUsecase) UpdateBankRegistrationMessaging(ctx context.Context, req event.GlobalEvent[dto.BankRegistrationUpdate, any]) error {
-
-	updatedRegis, err := b.queries.UpdateBankAccountRegistration(ctx, sqlc.UpdateBankAccountRegistrationParams{
+// UpdateBankRegistrationMessaging applies an orchestrator event to its
+// registration through q, the transaction the inbox recorded the event in.
+func (b *BankRegistrationUsecase) UpdateBankRegistrationMessaging(ctx context.Context, q sqlc.Querier, req event.GlobalEvent[dto.BankRegistrationUpdate, any]) error {
+	updatedRegis, err := q.UpdateBankAccountRegistration(ctx, sqlc.UpdateBankAccountRegistrationParams{
 		CustomerID: req.Payload.Request.CustomerID,
 		Username:   req.Payload.Request.Username,
 		Email:      req.Payload.Request.Email,
@@ -100,11 +106,5 @@ func (b *BankRegistrationUsecase) UpdateBankRegistrationMessaging(ctx context.Co
 		return err
 	}
 
-	err = b.orchestraProducer.SendMessage(uuid.New().String(), bytes)
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return enqueueMessage(ctx, q, b.orchestraTopic, registEvent.MessageKey(req.Payload.Request.CustomerID), bytes)
 }
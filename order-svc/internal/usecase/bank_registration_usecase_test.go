@@ -9,22 +9,16 @@ import (
 	"order-svc/internal/dto/event"
 	mockdb "order-svc/internal/repository/mock"
 	"order-svc/internal/repository/sqlc"
-	"order-svc/pkg/producer"
 	"testing"
 )
 
-var bankRegisProducer *producer.KafkaProducer
-
-func init() {
-	bankRegisProducer, _ = producer.NewKafkaProducer([]string{"localhost:29092"}, "bank-regis-topic-test")
-}
-
 func TestBankRegistrationUsecase_RegisterBankAccount(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	store := mockdb.NewMockStore(ctrl)
-	uc := NewBankRegistrationUsecase(store, bankRegisProducer)
+	uc := NewBankRegistrationUsecase(store, orchestraTopic)
+	passThroughTx(store)
 
 	ctx := context.Background()
 
@@ -45,6 +39,7 @@ func TestBankRegistrationUsecase_RegisterBankAccount(t *testing.T) {
 					Status:     dto.PROCESSING.String(),
 					Deposit:    1000,
 				}, nil)
+				store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			input: &dto.BankRegistrationRequest{
 				Username: "testuser",
@@ -102,7 +97,8 @@ func TestBankRegistrationUsecase_UpdateBankRegistrationMessaging(t *testing.T) {
 	defer ctrl.Finish()
 
 	store := mockdb.NewMockStore(ctrl)
-	uc := NewBankRegistrationUsecase(store, bankRegisProducer)
+	uc := NewBankRegistrationUsecase(store, orchestraTopic)
+	passThroughTx(store)
 
 	ctx := context.Background()
 
@@ -121,6 +117,7 @@ func TestBankRegistrationUsecase_UpdateBankRegistrationMessaging(t *testing.T) {
 					Email:      "test@example.com",
 					Status:     "COMPLETED",
 				}, nil)
+				store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			input: event.GlobalEvent[dto.BankRegistrationUpdate, any]{
 				EventType: event.BANK_ACCOUNT_REGISTRATION.String(),
@@ -159,7 +156,7 @@ func TestBankRegistrationUsecase_UpdateBankRegistrationMessaging(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := uc.UpdateBankRegistrationMessaging(ctx, tc.input)
+			err := uc.UpdateBankRegistrationMessaging(ctx, store, tc.input)
 
 			if tc.expectedErr != nil {
 				assert.Error(t, err)
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"order-svc/internal/repository/sqlc"
+)
+
+var (
+	eventsProcessed  = expvar.NewInt("events_processed")
+	eventsDuplicated = expvar.NewInt("events_duplicate_skipped")
+)
+
+type InboxUsecase struct {
+	queries sqlc.Store
+}
+
+func NewInboxUsecase(queries sqlc.Store) *InboxUsecase {
+	return &InboxUsecase{queries: queries}
+}
+
+// Handle runs fn unless the event was already processed successfully from
+// the same source. Events without an id are always handled.
+//
+// The event is claimed in the transaction fn writes through, so it is
+// recorded exactly when fn's changes commit. A second delivery of the same
+// event blocks on the claim until the first one finishes and is then skipped,
+// or runs fn itself when the first one failed and rolled back.
+func (i *InboxUsecase) Handle(ctx context.Context, eventID, source, state string, fn func(context.Context, sqlc.Querier) error) error {
+	return i.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		if eventID == "" {
+			return fn(ctx, q)
+		}
+
+		claimed, err := q.ClaimProcessedEvent(ctx, sqlc.ClaimProcessedEventParams{
+			EventID: eventID,
+			Source:  source,
+			State:   state,
+		})
+		if err != nil {
+			return err
+		}
+
+		if claimed == 0 {
+			eventsDuplicated.Add(1)
+			log.Printf("Skipping duplicate event %s from %s (%s)", eventID, source, state)
+			return nil
+		}
+
+		if err := fn(ctx, q); err != nil {
+			return err
+		}
+
+		eventsProcessed.Add(1)
+
+		return nil
+	})
+}
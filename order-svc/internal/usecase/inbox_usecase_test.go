@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	mockdb "order-svc/internal/repository/mock"
+	"order-svc/internal/repository/sqlc"
+	"testing"
+)
+
+func TestInboxUsecase_Handle(t *testing.T) {
+	ctx := context.Background()
+	key := sqlc.ClaimProcessedEventParams{EventID: "event-001", Source: "payment-svc", State: "payment_success"}
+
+	testCases := []struct {
+		name        string
+		setupMocks  func(store *mockdb.MockStore)
+		fnErr       error
+		expectCall  bool
+		expectedErr error
+	}{
+		{
+			name: "New event is handled and recorded",
+			setupMocks: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimProcessedEvent(ctx, key).Return(int64(1), nil)
+			},
+			expectCall: true,
+		},
+		{
+			name: "Duplicate event is skipped",
+			setupMocks: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimProcessedEvent(ctx, key).Return(int64(0), nil)
+			},
+		},
+		{
+			name: "Failed event is not recorded",
+			setupMocks: func(store *mockdb.MockStore) {
+				// the claim is rolled back with the error fn returned
+				store.EXPECT().ClaimProcessedEvent(ctx, key).Return(int64(1), nil)
+			},
+			fnErr:       errors.New("error"),
+			expectCall:  true,
+			expectedErr: errors.New("error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			passThroughTx(store)
+			tc.setupMocks(store)
+			ic := NewInboxUsecase(store)
+
+			called := false
+			err := ic.Handle(ctx, "event-001", "payment-svc", "payment_success", func(context.Context, sqlc.Querier) error {
+				called = true
+				return tc.fnErr
+			})
+
+			assert.Equal(t, tc.expectCall, called)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestInboxUsecase_Handle_WithoutEventID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	passThroughTx(store)
+	ic := NewInboxUsecase(store)
+
+	called := false
+	err := ic.Handle(context.Background(), "", "payment-svc", "payment_success", func(context.Context, sqlc.Querier) error {
+		called = true
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -9,7 +9,6 @@ import (
 	"order-svc/internal/dto"
 	"order-svc/internal/dto/event"
 	"order-svc/internal/repository/sqlc"
-	"order-svc/pkg/producer"
 
 	"github.com/google/uuid"
 )
@@ -18,52 +17,58 @@ var ErrUnauthorizeCancelOrder = errors.New("user unauthorize to cancel order")
 var ErrCannotCancelOrder = errors.New("cannot cancel uncomplete order")
 
 type OrderUsecase struct {
-	queries           sqlc.Store
-	orchestraProducer *producer.KafkaProducer
+	queries        sqlc.Store
+	orchestraTopic string
 }
 
-func NewOrderUsecase(queries sqlc.Store, producer *producer.KafkaProducer) *OrderUsecase {
+func NewOrderUsecase(queries sqlc.Store, orchestraTopic string) *OrderUsecase {
 	return &OrderUsecase{
-		queries:           queries,
-		orchestraProducer: producer,
+		queries:        queries,
+		orchestraTopic: orchestraTopic,
 	}
 }
 
 func (oc *OrderUsecase) CreateOrder(ctx context.Context, req *dto.OrderRequest) (*sqlc.Order, error) {
 
-	orderCreated, err := oc.queries.CreateOrder(ctx, sqlc.CreateOrderParams{
-		RefID:      fmt.Sprintf("%s-%s", "TOKPED", uuid.New()),
-		CustomerID: req.CustomerID,
-		Username:   req.Username,
-		ProductID:  req.ProductID,
-		Quantity:   req.Quantity,
-		Status:     dto.PROCESSING.String(),
-	})
+	var orderCreated sqlc.Order
 
-	if err != nil {
-		return nil, err
-	}
+	err := oc.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		var err error
 
-	basePayload := event.BasePayload[dto.OrderRequest, sqlc.Order]{
-		Request:  *req,
-		Response: orderCreated,
-	}
+		orderCreated, err = q.CreateOrder(ctx, sqlc.CreateOrderParams{
+			RefID:      fmt.Sprintf("%s-%s", "TOKPED", uuid.New()),
+			CustomerID: req.CustomerID,
+			Username:   req.Username,
+			ProductID:  req.ProductID,
+			Quantity:   req.Quantity,
+			Status:     dto.PROCESSING.String(),
+		})
 
-	orderEvent := event.NewGlobalEvent(
-		"create",
-		"success",
-		"order_created",
-		event.ORDER_PROCESS.String(),
-		basePayload,
-	)
-	orderEvent.StatusCode = 201
-	bytes, err := orderEvent.ToJSON()
+		if err != nil {
+			return err
+		}
 
-	if err != nil {
-		return nil, err
-	}
+		basePayload := event.BasePayload[dto.OrderRequest, sqlc.Order]{
+			Request:  *req,
+			Response: orderCreated,
+		}
 
-	err = oc.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+		orderEvent := event.NewGlobalEvent(
+			"create",
+			"success",
+			"order_created",
+			event.ORDER_PROCESS.String(),
+			basePayload,
+		)
+		orderEvent.StatusCode = 201
+		bytes, err := orderEvent.ToJSON()
+
+		if err != nil {
+			return err
+		}
+
+		return enqueueMessage(ctx, q, oc.orchestraTopic, orderEvent.MessageKey(orderCreated.RefID), bytes)
+	})
 
 	if err != nil {
 		return nil, err
@@ -94,32 +99,39 @@ func (oc *OrderUsecase) CancelOrder(ctx context.Context, req *dto.OrderCancelReq
 		EventType: event.ORDER_CANCEL_PROCESS.String(),
 	}
 
-	updatedOrder, err := oc.UpdateOrder(ctx, reqUpdate)
-	if err != nil {
-		return nil, err
-	}
+	var updatedOrder *sqlc.Order
 
-	basePayload := event.BasePayload[dto.OrderUpdateRequest, sqlc.Order]{
-		Request:  *reqUpdate,
-		Response: *updatedOrder,
-	}
+	err = oc.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		var err error
 
-	orderEvent := event.NewGlobalEvent(
-		"update",
-		"success",
-		"order_cancel",
-		event.ORDER_CANCEL_PROCESS.String(),
-		basePayload,
-	)
+		updatedOrder, err = updateOrder(ctx, q, reqUpdate)
+		if err != nil {
+			return err
+		}
 
-	orderEvent.StatusCode = 200
+		basePayload := event.BasePayload[dto.OrderUpdateRequest, sqlc.Order]{
+			Request:  *reqUpdate,
+			Response: *updatedOrder,
+		}
 
-	bytes, err := orderEvent.ToJSON()
-	if err != nil {
-		return nil, err
-	}
+		orderEvent := event.NewGlobalEvent(
+			"update",
+			"success",
+			"order_cancel",
+			event.ORDER_CANCEL_PROCESS.String(),
+			basePayload,
+		)
+
+		orderEvent.StatusCode = 200
+
+		bytes, err := orderEvent.ToJSON()
+		if err != nil {
+			return err
+		}
+
+		return enqueueMessage(ctx, q, oc.orchestraTopic, orderEvent.MessageKey(updatedOrder.RefID), bytes)
+	})
 
-	err = oc.orchestraProducer.SendMessage(uuid.New().String(), bytes)
 	if err != nil {
 		return nil, err
 	}
@@ -127,9 +139,11 @@ func (oc *OrderUsecase) CancelOrder(ctx context.Context, req *dto.OrderCancelReq
 	return updatedOrder, nil
 }
 
-func (oc *OrderUsecase) UpdateOrderMessaging(ctx context.Context, req event.GlobalEvent[dto.OrderUpdateRequest, any]) error {
+// UpdateOrderMessaging applies an orchestrator event to its order through q,
+// the transaction the inbox recorded the event in.
+func (oc *OrderUsecase) UpdateOrderMessaging(ctx context.Context, q sqlc.Querier, req event.GlobalEvent[dto.OrderUpdateRequest, any]) error {
 
-	order, err := oc.queries.FindOrderByRefID(ctx, req.Payload.Request.RefID)
+	order, err := q.FindOrderByRefID(ctx, req.Payload.Request.RefID)
 
 	if err != nil {
 		log.Println("error find order by refID: ", err)
@@ -156,7 +170,7 @@ func (oc *OrderUsecase) UpdateOrderMessaging(ctx context.Context, req event.Glob
 		}
 	}
 
-	updatedOrder, err := oc.UpdateOrder(ctx, &updateReq)
+	updatedOrder, err := updateOrder(ctx, q, &updateReq)
 
 	if err != nil {
 		return err
@@ -184,19 +198,18 @@ func (oc *OrderUsecase) UpdateOrderMessaging(ctx context.Context, req event.Glob
 		return err
 	}
 
-	err = oc.orchestraProducer.SendMessage(uuid.New().String(), bytes)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return enqueueMessage(ctx, q, oc.orchestraTopic, orderEvent.MessageKey(order.RefID), bytes)
 }
 
 func (oc *OrderUsecase) UpdateOrder(ctx context.Context, req *dto.OrderUpdateRequest) (*sqlc.Order, error) {
+	return updateOrder(ctx, oc.queries, req)
+}
+
+func updateOrder(ctx context.Context, q sqlc.Querier, req *dto.OrderUpdateRequest) (*sqlc.Order, error) {
 
 	log.Println("req updateOrder: ", req)
 
-	updatedOrder, err := oc.queries.UpdateOrder(ctx, sqlc.UpdateOrderParams{
+	updatedOrder, err := q.UpdateOrder(ctx, sqlc.UpdateOrderParams{
 		Status: req.Status,
 		Amount: sql.NullFloat64{
 			Float64: req.Amount,
@@ -10,14 +10,16 @@ import (
 	"order-svc/internal/dto/event"
 	mockdb "order-svc/internal/repository/mock"
 	"order-svc/internal/repository/sqlc"
-	"order-svc/pkg/producer"
 	"testing"
 )
 
-var orchestraProducer *producer.KafkaProducer
+const orchestraTopic = "orchestra-topic-test"
 
-func init() {
-	orchestraProducer, _ = producer.NewKafkaProducer([]string{"localhost:29092"}, "orchestra-topic-test")
+func passThroughTx(store *mockdb.MockStore) {
+	store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(sqlc.Querier) error) error {
+			return fn(store)
+		}).AnyTimes()
 }
 
 func TestOrderUsecase_CreateOrder(t *testing.T) {
@@ -26,7 +28,8 @@ func TestOrderUsecase_CreateOrder(t *testing.T) {
 
 	store := mockdb.NewMockStore(ctrl)
 
-	uc := NewOrderUsecase(store, orchestraProducer)
+	uc := NewOrderUsecase(store, orchestraTopic)
+	passThroughTx(store)
 
 	ctx := context.Background()
 
@@ -49,6 +52,7 @@ func TestOrderUsecase_CreateOrder(t *testing.T) {
 					Quantity:   2,
 					Status:     dto.PROCESSING.String(),
 				}, nil)
+				store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			input: &dto.OrderRequest{
 				CustomerID: "customer-001",
@@ -111,7 +115,8 @@ func TestOrderUsecase_CancelOrder(t *testing.T) {
 	defer ctrl.Finish()
 
 	store := mockdb.NewMockStore(ctrl)
-	uc := NewOrderUsecase(store, orchestraProducer)
+	uc := NewOrderUsecase(store, orchestraTopic)
+	passThroughTx(store)
 
 	ctx := context.Background()
 
@@ -145,6 +150,7 @@ func TestOrderUsecase_CancelOrder(t *testing.T) {
 					Status:     dto.CANCEL_PROCESSING.String(),
 					Amount:     sql.NullFloat64{Float64: 100, Valid: true},
 				}, nil)
+				store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			input: &dto.OrderCancelRequest{
 				OrderID:  1,
@@ -268,7 +274,8 @@ func TestOrderUsecase_UpdateOrderMessaging(t *testing.T) {
 	defer ctrl.Finish()
 
 	store := mockdb.NewMockStore(ctrl)
-	uc := NewOrderUsecase(store, orchestraProducer)
+	uc := NewOrderUsecase(store, orchestraTopic)
+	passThroughTx(store)
 
 	ctx := context.Background()
 
@@ -292,6 +299,7 @@ func TestOrderUsecase_UpdateOrderMessaging(t *testing.T) {
 					Quantity: 3,
 					Status:   "PROCESSING",
 				}, nil)
+				store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			input: event.GlobalEvent[dto.OrderUpdateRequest, any]{
 				EventType: event.ORDER_PROCESS.String(),
@@ -320,6 +328,7 @@ func TestOrderUsecase_UpdateOrderMessaging(t *testing.T) {
 					Quantity: 4,
 					Status:   "COMPLETED",
 				}, nil)
+				store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			input: event.GlobalEvent[dto.OrderUpdateRequest, any]{
 				EventType: "OTHER_EVENT",
@@ -376,7 +385,7 @@ func TestOrderUsecase_UpdateOrderMessaging(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := uc.UpdateOrderMessaging(ctx, tc.input)
+			err := uc.UpdateOrderMessaging(ctx, store, tc.input)
 
 			if tc.expectedErr != nil {
 				assert.Error(t, err)
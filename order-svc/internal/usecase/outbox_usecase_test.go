@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"order-svc/internal/dto"
+	mockdb "order-svc/internal/repository/mock"
+	"order-svc/internal/repository/sqlc"
+	"testing"
+)
+
+func TestOrderUsecase_CreateOrder_OutboxError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOrderUsecase(store, orchestraTopic)
+	passThroughTx(store)
+
+	ctx := context.Background()
+
+	store.EXPECT().CreateOrder(ctx, gomock.Any()).Return(sqlc.Order{ID: 1, Status: dto.PROCESSING.String()}, nil)
+	store.EXPECT().CreateOutboxMessage(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.CreateOutboxMessageParams) error {
+			assert.Equal(t, orchestraTopic, arg.Topic)
+			assert.Contains(t, arg.Payload, `"state":"order_created"`)
+			return errors.New("database error")
+		})
+
+	result, err := uc.CreateOrder(ctx, &dto.OrderRequest{Username: "testuser", ProductID: "product-001", Quantity: 1})
+	assert.EqualError(t, err, "database error")
+	assert.Nil(t, result)
+}
+
+func TestOutboxUsecase_RelayPending_NoMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOutboxUsecase(store, nil)
+
+	ctx := context.Background()
+
+	store.EXPECT().AcquireOutboxLease(ctx, gomock.Any()).Return(int64(1), nil)
+	store.EXPECT().FindPendingOutboxMessages(ctx, int32(100)).Return([]sqlc.Outbox{}, nil)
+
+	sent, err := uc.RelayPending(ctx, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestOutboxUsecase_RelayPending_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOutboxUsecase(store, nil)
+
+	ctx := context.Background()
+
+	store.EXPECT().AcquireOutboxLease(ctx, gomock.Any()).Return(int64(1), nil)
+	store.EXPECT().FindPendingOutboxMessages(ctx, int32(100)).Return(nil, errors.New("database error"))
+
+	_, err := uc.RelayPending(ctx, 100)
+	assert.Error(t, err)
+}
+
+func TestOutboxUsecase_RelayPending_LeaseHeldElsewhere(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	uc := NewOutboxUsecase(store, nil)
+
+	ctx := context.Background()
+
+	// another replica is relaying, so nothing is read or sent here
+	store.EXPECT().AcquireOutboxLease(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg sqlc.AcquireOutboxLeaseParams) (int64, error) {
+			assert.NotEmpty(t, arg.Owner)
+			assert.Equal(t, int32(30), arg.TtlSeconds)
+			return 0, nil
+		})
+
+	sent, err := uc.RelayPending(ctx, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
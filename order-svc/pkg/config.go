@@ -4,6 +4,8 @@ import (
 	"crypto/rsa"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/joho/godotenv"
@@ -27,6 +29,10 @@ type Config struct {
 	OrchestraTopic string
 	OrderTopic     string
 	GroupID        string
+	OutboxInterval time.Duration
+	OutboxBatch    int
+	DLQTopic       string
+	DLQAttempts    int
 }
 
 func LoadConfig() *Config {
@@ -44,9 +50,43 @@ func LoadConfig() *Config {
 		OrchestraTopic: os.Getenv("ORCHESTRA_TOPIC"),
 		OrderTopic:     os.Getenv("ORDER_TOPIC"),
 		GroupID:        os.Getenv("GROUP_ID"),
+		OutboxInterval: getDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatch:    getInt("OUTBOX_BATCH_SIZE", 100),
+		DLQTopic:       os.Getenv("DEAD_LETTER_TOPIC"),
+		DLQAttempts:    getInt("DEAD_LETTER_MAX_ATTEMPTS", 3),
 	}
 }
 
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid int for %s: %v, using %d", key, err, fallback)
+		return fallback
+	}
+
+	return n
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("invalid duration for %s: %v, using %s", key, err, fallback)
+		return fallback
+	}
+
+	return d
+}
+
 func InitializeKeys() error {
 	// Load private key
 	privateKeyPEM, err := os.ReadFile(privKeyPath)
@@ -4,6 +4,7 @@ import (
 	"payment-svc/internal/delivery/messaging"
 	"payment-svc/internal/provider"
 	"payment-svc/internal/usecase"
+	"payment-svc/pkg/deadletter"
 	"payment-svc/pkg/http_client"
 	"payment-svc/pkg/producer"
 	"time"
@@ -20,7 +21,8 @@ func (app *App) startService(orchestraProducer *producer.KafkaProducer) error {
 
 	uc := usecase.NewUsecase(paymentProvider, orchestraProducer)
 
-	app.msg = messaging.NewMessageHandler(uc)
+	dlq := deadletter.NewPublisher(orchestraProducer, app.config.DLQTopic, "payment-svc", app.config.DLQAttempts)
+	app.msg = messaging.NewMessageHandler(uc, dlq)
 
 	return nil
 }
@@ -1,20 +1,23 @@
 package messaging
 
 import (
+	"context"
 	"log"
 	"payment-svc/internal/dto"
 	"payment-svc/internal/dto/event"
 	"payment-svc/internal/usecase"
+	"payment-svc/pkg/deadletter"
 
 	"github.com/IBM/sarama"
 )
 
 type MessageHandler struct {
-	u *usecase.Usecase
+	u   *usecase.Usecase
+	dlq *deadletter.Publisher
 }
 
-func NewMessageHandler(u *usecase.Usecase) *MessageHandler {
-	return &MessageHandler{u: u}
+func NewMessageHandler(u *usecase.Usecase, dlq *deadletter.Publisher) *MessageHandler {
+	return &MessageHandler{u: u, dlq: dlq}
 }
 
 func (h MessageHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
@@ -23,34 +26,41 @@ func (h MessageHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return ni
 func (h MessageHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for msg := range claim.Messages() {
 
-		eventMsg, err := event.FromJSON[dto.PaymentRequest, any](msg.Value)
+		h.dlq.Handle(msg, func() error {
+			return h.handle(sess.Context(), msg.Value)
+		})
 
-		if err != nil {
-			log.Println("Error parsing message: ", err)
-		}
+		sess.MarkMessage(msg, "")
+	}
+	return nil
+}
 
-		log.Println("Event type: ", eventMsg.EventType)
-		log.Println("Event state: ", eventMsg.State)
-
-		switch eventMsg.EventType {
-		case event.ORDER_PROCESS.String():
-			if eventMsg.State == event.PRODUCT_RESERVATION_SUCCESS.String() {
-				err = h.u.ProcessPaymentMessaging(sess.Context(), eventMsg)
-			}
-		case event.ORDER_CANCEL_PROCESS.String():
-			if eventMsg.State == event.PRODUCT_RELEASE_SUCCESS.String() {
-				err = h.u.RefundPaymentMessaging(sess.Context(), eventMsg)
-			}
-		case event.BANK_ACCOUNT_REGISTRATION.String():
-			eventMsg, _ := event.FromJSON[dto.AccountBalanceRequest, any](msg.Value)
-			err = h.u.CreateAccountBalanceMessaging(sess.Context(), eventMsg)
-		}
+func (h MessageHandler) handle(ctx context.Context, value []byte) error {
+	eventMsg, err := event.FromJSON[dto.PaymentRequest, any](value)
+
+	if err != nil {
+		return deadletter.Unprocessable(err)
+	}
+
+	log.Println("Event type: ", eventMsg.EventType)
+	log.Println("Event state: ", eventMsg.State)
 
+	switch eventMsg.EventType {
+	case event.ORDER_PROCESS.String():
+		if eventMsg.State == event.PRODUCT_RESERVATION_SUCCESS.String() {
+			return h.u.ProcessPaymentMessaging(ctx, eventMsg)
+		}
+	case event.ORDER_CANCEL_PROCESS.String():
+		if eventMsg.State == event.PRODUCT_RELEASE_SUCCESS.String() {
+			return h.u.RefundPaymentMessaging(ctx, eventMsg)
+		}
+	case event.BANK_ACCOUNT_REGISTRATION.String():
+		eventMsg, err := event.FromJSON[dto.AccountBalanceRequest, any](value)
 		if err != nil {
-			log.Println("Error processing message: ", err)
+			return deadletter.Unprocessable(err)
 		}
-
-		sess.MarkMessage(msg, "")
+		return h.u.CreateAccountBalanceMessaging(ctx, eventMsg)
 	}
-	return nil
+
+	return deadletter.UnknownEvent(eventMsg.EventType, eventMsg.State)
 }
@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"github.com/google/uuid"
 	"log"
 	"payment-svc/internal/dto"
 	"payment-svc/internal/dto/event"
@@ -73,7 +72,7 @@ func (u *Usecase) CreateAccountBalanceMessaging(ctx context.Context, ge event.Gl
 		return fmt.Errorf("failed to convert event to JSON: %w", jsonErr)
 	}
 
-	sendErr := u.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+	sendErr := u.orchestraProducer.SendMessage(gevent.MessageKey(""), bytes)
 
 	if sendErr != nil {
 		return fmt.Errorf("failed to send message: %w", sendErr)
@@ -138,7 +137,7 @@ func (u *Usecase) ProcessPaymentMessaging(ctx context.Context, ge event.GlobalEv
 		return fmt.Errorf("failed to convert event to JSON: %w", jsonErr)
 	}
 
-	sendErr := u.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+	sendErr := u.orchestraProducer.SendMessage(gevent.MessageKey(ge.Payload.Request.RefId), bytes)
 	if sendErr != nil {
 		return fmt.Errorf("failed to send message: %w", sendErr)
 	}
@@ -197,7 +196,7 @@ func (u *Usecase) RefundPaymentMessaging(ctx context.Context, ge event.GlobalEve
 		return fmt.Errorf("failed to convert event to JSON: %w", jsonErr)
 	}
 
-	sendErr := u.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+	sendErr := u.orchestraProducer.SendMessage(gevent.MessageKey(ge.Payload.Request.RefId), bytes)
 	if sendErr != nil {
 		return fmt.Errorf("failed to send message: %w", sendErr)
 	}
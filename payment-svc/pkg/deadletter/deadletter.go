@@ -0,0 +1,159 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// ErrUnprocessable marks failures a retry cannot fix, such as a payload that
+// does not parse. Such messages are dead-lettered on the first attempt.
+var ErrUnprocessable = errors.New("unprocessable message")
+
+// ErrUnknownEvent marks an event type or state the service does not handle.
+var ErrUnknownEvent = fmt.Errorf("%w: unknown event", ErrUnprocessable)
+
+const retryBackoff = 200 * time.Millisecond
+
+// Record is the value published on a dead-letter topic.
+type Record struct {
+	Service   string            `json:"service"`
+	Topic     string            `json:"topic"`
+	Partition int32             `json:"partition"`
+	Offset    int64             `json:"offset"`
+	Key       string            `json:"key"`
+	Value     []byte            `json:"value"`
+	Headers   map[string]string `json:"headers"`
+	Error     string            `json:"error"`
+	Attempts  int               `json:"attempts"`
+	FailedAt  time.Time         `json:"failed_at"`
+}
+
+// Sender is the producer a Publisher dead-letters through. Every service's
+// producer implements it, whatever topic the producer is bound to.
+type Sender interface {
+	SendMessageTo(topic, key string, value []byte) error
+}
+
+// SenderFunc adapts a function to a Sender, for producers that send to a
+// topic under another method name.
+type SenderFunc func(topic, key string, value []byte) error
+
+func (f SenderFunc) SendMessageTo(topic, key string, value []byte) error {
+	return f(topic, key, value)
+}
+
+type Publisher struct {
+	sender      Sender
+	topic       string
+	service     string
+	maxAttempts int
+	backoff     time.Duration
+	shared      map[string]bool
+}
+
+// NewPublisher dead-letters to topic. Unknown events read from sharedTopics
+// are dropped instead, since another service consumes the same topic.
+func NewPublisher(sender Sender, topic, service string, maxAttempts int, sharedTopics ...string) *Publisher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	shared := make(map[string]bool, len(sharedTopics))
+	for _, t := range sharedTopics {
+		shared[t] = true
+	}
+
+	return &Publisher{
+		sender:      sender,
+		topic:       topic,
+		service:     service,
+		maxAttempts: maxAttempts,
+		backoff:     retryBackoff,
+		shared:      shared,
+	}
+}
+
+func Unprocessable(err error) error {
+	return fmt.Errorf("%w: %v", ErrUnprocessable, err)
+}
+
+func UnknownEvent(eventType, state string) error {
+	return fmt.Errorf("%w: %s/%s", ErrUnknownEvent, eventType, state)
+}
+
+// Handle runs fn until it succeeds or the attempts run out, then publishes
+// the message to the dead-letter topic. A nil Publisher runs fn once.
+func (p *Publisher) Handle(msg *sarama.ConsumerMessage, fn func() error) {
+	if p == nil {
+		if err := fn(); err != nil {
+			log.Println("Error processing message: ", err)
+		}
+		return
+	}
+
+	var err error
+	attempts := 0
+	for attempts < p.maxAttempts {
+		attempts++
+
+		if err = fn(); err == nil {
+			return
+		}
+
+		if errors.Is(err, ErrUnprocessable) {
+			break
+		}
+
+		log.Printf("Error processing message %s/%d/%d (attempt %d): %v", msg.Topic, msg.Partition, msg.Offset, attempts, err)
+
+		if attempts < p.maxAttempts {
+			time.Sleep(p.backoff * time.Duration(attempts))
+		}
+	}
+
+	if errors.Is(err, ErrUnknownEvent) && p.shared[msg.Topic] {
+		return
+	}
+
+	log.Printf("Dead-lettering message %s/%d/%d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+
+	if err := p.Publish(msg, attempts, err); err != nil {
+		log.Printf("Error dead-lettering message %s/%d/%d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+	}
+}
+
+func (p *Publisher) Publish(msg *sarama.ConsumerMessage, attempts int, cause error) error {
+	value, err := json.Marshal(NewRecord(p.service, msg, attempts, cause))
+	if err != nil {
+		return err
+	}
+
+	return p.sender.SendMessageTo(p.topic, string(msg.Key), value)
+}
+
+func NewRecord(service string, msg *sarama.ConsumerMessage, attempts int, cause error) Record {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		if h != nil {
+			headers[string(h.Key)] = string(h.Value)
+		}
+	}
+
+	return Record{
+		Service:   service,
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Value:     msg.Value,
+		Headers:   headers,
+		Error:     cause.Error(),
+		Attempts:  attempts,
+		FailedAt:  time.Now(),
+	}
+}
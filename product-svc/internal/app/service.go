@@ -4,6 +4,7 @@ import (
 	"product-svc/internal/delivery/messaging"
 	"product-svc/internal/provider"
 	"product-svc/internal/usecase"
+	"product-svc/pkg/deadletter"
 	"product-svc/pkg/http_client"
 	"product-svc/pkg/producer"
 	"time"
@@ -20,7 +21,8 @@ func (app *App) startService(orchestraProducer *producer.KafkaProducer) error {
 
 	u := usecase.NewUsecase(productProvider, orchestraProducer)
 
-	app.msg = messaging.NewMessageHandler(u)
+	dlq := deadletter.NewPublisher(orchestraProducer, app.config.DLQTopic, "product-svc", app.config.DLQAttempts, app.config.UserProductTopic)
+	app.msg = messaging.NewMessageHandler(u, dlq)
 
 	return nil
 }
@@ -1,19 +1,21 @@
 package messaging
 
 import (
+	"context"
 	"github.com/IBM/sarama"
-	"log"
 	"product-svc/internal/dto"
 	"product-svc/internal/dto/event"
 	"product-svc/internal/usecase"
+	"product-svc/pkg/deadletter"
 )
 
 type MessageHandler struct {
-	u *usecase.Usecase
+	u   *usecase.Usecase
+	dlq *deadletter.Publisher
 }
 
-func NewMessageHandler(u *usecase.Usecase) *MessageHandler {
-	return &MessageHandler{u: u}
+func NewMessageHandler(u *usecase.Usecase, dlq *deadletter.Publisher) *MessageHandler {
+	return &MessageHandler{u: u, dlq: dlq}
 }
 
 func (h MessageHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
@@ -22,45 +24,49 @@ func (h MessageHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return ni
 func (h MessageHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for msg := range claim.Messages() {
 
-		eventMsg, err := event.FromJSON[dto.ProductRequest, any](msg.Value)
+		h.dlq.Handle(msg, func() error {
+			return h.handle(sess.Context(), msg.Value)
+		})
 
-		if err != nil {
-			log.Println("Error parsing message: ", err)
-		}
+		sess.MarkMessage(msg, "")
+	}
+	return nil
+}
 
-		switch eventMsg.EventType {
-		case event.ORDER_PROCESS.String():
-			if eventMsg.State == event.USER_VALIDATION_SUCCESS.String() {
-				err = h.u.ReserveProductMessaging(sess.Context(), eventMsg)
-			}
+func (h MessageHandler) handle(ctx context.Context, value []byte) error {
+	eventMsg, err := event.FromJSON[dto.ProductRequest, any](value)
 
-			if eventMsg.State == event.PAYMENT_FAILED.String() {
-				err = h.u.ReleaseProductMessaging(sess.Context(), eventMsg)
-			}
+	if err != nil {
+		return deadletter.Unprocessable(err)
+	}
 
-			if eventMsg.State == event.PRODUCT_RETRY.String() {
-				err = h.u.ReserveProductMessaging(sess.Context(), eventMsg)
-			}
+	switch eventMsg.EventType {
+	case event.ORDER_PROCESS.String():
+		if eventMsg.State == event.USER_VALIDATION_SUCCESS.String() {
+			return h.u.ReserveProductMessaging(ctx, eventMsg)
+		}
 
-			if err != nil {
-				log.Println("Error processing message: ", err)
-			}
+		if eventMsg.State == event.PAYMENT_FAILED.String() {
+			return h.u.ReleaseProductMessaging(ctx, eventMsg)
+		}
 
-		case event.ORDER_CANCEL_PROCESS.String():
-			if eventMsg.State == event.USER_VALIDATION_SUCCESS.String() {
-				err = h.u.ReleaseProductMessaging(sess.Context(), eventMsg)
-			}
+		if eventMsg.State == event.PRODUCT_RETRY.String() {
+			return h.u.ReserveProductMessaging(ctx, eventMsg)
+		}
 
-			if eventMsg.State == event.REFUND_FAILED.String() {
-				err = h.u.ReserveProductMessaging(sess.Context(), eventMsg)
-			}
+		if eventMsg.State == event.WORKFLOW_ABORTED.String() {
+			return h.u.ReleaseProductMessaging(ctx, eventMsg)
+		}
 
-			if err != nil {
-				log.Println("Error processing message: ", err)
-			}
+	case event.ORDER_CANCEL_PROCESS.String():
+		if eventMsg.State == event.USER_VALIDATION_SUCCESS.String() {
+			return h.u.ReleaseProductMessaging(ctx, eventMsg)
 		}
 
-		sess.MarkMessage(msg, "")
+		if eventMsg.State == event.REFUND_FAILED.String() {
+			return h.u.ReserveProductMessaging(ctx, eventMsg)
+		}
 	}
-	return nil
+
+	return deadletter.UnknownEvent(eventMsg.EventType, eventMsg.State)
 }
@@ -15,10 +15,11 @@ const (
 	ORDER_CANCEL
 	PRODUCT_RETRY
 	REFUND_FAILED
+	WORKFLOW_ABORTED
 )
 
 func (s State) String() string {
-	return [...]string{"user_validation_success", "product_release_success", "payment_failed", "order_cancel", "product_retry", "refund_failed"}[s]
+	return [...]string{"user_validation_success", "product_release_success", "payment_failed", "order_cancel", "product_retry", "refund_failed", "workflow_aborted"}[s]
 }
 
 type EventType int
@@ -73,3 +74,16 @@ func FromJSON[R any, S any](data []byte) (GlobalEvent[R, S], error) {
 func (ge GlobalEvent[R, S]) ToJSON() ([]byte, error) {
 	return json.Marshal(ge)
 }
+
+// MessageKey partitions messages by instance so a saga's events stay in
+// order. Events sent before the orchestrator assigns an instance fall back
+// to the given ref ID, then to the event ID.
+func (ge GlobalEvent[R, S]) MessageKey(refID string) string {
+	switch {
+	case ge.InstanceID != "":
+		return ge.InstanceID
+	case refID != "":
+		return refID
+	}
+	return ge.EventID
+}
@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"github.com/google/uuid"
 	"log"
 	"product-svc/internal/dto"
 	"product-svc/internal/dto/event"
@@ -75,7 +74,7 @@ func (u *Usecase) ReserveProductMessaging(ctx context.Context, ge event.GlobalEv
 		return fmt.Errorf("failed to convert event to JSON: %w", jsonErr)
 	}
 
-	sendErr := u.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+	sendErr := u.orchestraProducer.SendMessage(gevent.MessageKey(""), bytes)
 	if sendErr != nil {
 		return fmt.Errorf("failed to send message: %w", sendErr)
 	}
@@ -139,7 +138,7 @@ func (u *Usecase) ReleaseProductMessaging(ctx context.Context, ge event.GlobalEv
 		return fmt.Errorf("failed to convert event to JSON: %w", jsonErr)
 	}
 
-	sendErr := u.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+	sendErr := u.orchestraProducer.SendMessage(gevent.MessageKey(""), bytes)
 	if sendErr != nil {
 		return fmt.Errorf("failed to send message: %w", sendErr)
 	}
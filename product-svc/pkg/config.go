@@ -3,6 +3,7 @@ package pkg
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -18,6 +19,8 @@ type Config struct {
 	ProductTopic     string
 	GroupID          string
 	ClientUrl        string
+	DLQTopic         string
+	DLQAttempts      int
 }
 
 func LoadConfig() *Config {
@@ -38,5 +41,22 @@ func LoadConfig() *Config {
 		ProductTopic:     os.Getenv("PRODUCT_TOPIC"),
 		GroupID:          os.Getenv("GROUP_ID"),
 		ClientUrl:        os.Getenv("CLIENT_URL"),
+		DLQTopic:         os.Getenv("DEAD_LETTER_TOPIC"),
+		DLQAttempts:      getInt("DEAD_LETTER_MAX_ATTEMPTS", 3),
 	}
 }
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid int for %s: %v, using %d", key, err, fallback)
+		return fallback
+	}
+
+	return n
+}
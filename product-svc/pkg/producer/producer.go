@@ -31,8 +31,12 @@ func NewKafkaProducer(brokers []string, topic string) (*KafkaProducer, error) {
 }
 
 func (kp *KafkaProducer) SendMessage(key string, value []byte) error {
+	return kp.SendMessageTo(kp.topic, key, value)
+}
+
+func (kp *KafkaProducer) SendMessageTo(topic, key string, value []byte) error {
 	msg := &sarama.ProducerMessage{
-		Topic: kp.topic,
+		Topic: topic,
 		Key:   sarama.StringEncoder(key),
 		Value: sarama.ByteEncoder(value),
 	}
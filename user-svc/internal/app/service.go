@@ -5,6 +5,7 @@ import (
 	"user-svc/internal/delivery/messaging"
 	"user-svc/internal/provider"
 	"user-svc/internal/usecase"
+	"user-svc/pkg/deadletter"
 	"user-svc/pkg/http_client"
 	"user-svc/pkg/producer"
 )
@@ -20,7 +21,8 @@ func (app *App) startService(orchestraProducer *producer.KafkaProducer) error {
 
 	uc := usecase.NewUsecase(userClient, orchestraProducer, userProvider)
 
-	app.msg = messaging.NewMessageHandler(uc)
+	dlq := deadletter.NewPublisher(orchestraProducer, app.config.DLQTopic, "user-svc", app.config.DLQAttempts, app.config.UserProductTopic)
+	app.msg = messaging.NewMessageHandler(uc, dlq)
 
 	return nil
 }
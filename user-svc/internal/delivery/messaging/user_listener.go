@@ -1,20 +1,23 @@
 package messaging
 
 import (
+	"context"
 	"log"
 	"user-svc/internal/dto"
 	"user-svc/internal/dto/event"
 	"user-svc/internal/usecase"
+	"user-svc/pkg/deadletter"
 
 	"github.com/IBM/sarama"
 )
 
 type MessageHandler struct {
-	u *usecase.Usecase
+	u   *usecase.Usecase
+	dlq *deadletter.Publisher
 }
 
-func NewMessageHandler(u *usecase.Usecase) *MessageHandler {
-	return &MessageHandler{u: u}
+func NewMessageHandler(u *usecase.Usecase, dlq *deadletter.Publisher) *MessageHandler {
+	return &MessageHandler{u: u, dlq: dlq}
 }
 
 func (h MessageHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
@@ -23,38 +26,47 @@ func (h MessageHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return ni
 func (h MessageHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for msg := range claim.Messages() {
 
-		eventMsg, err := event.FromJSON[any, any](msg.Value)
-		if err != nil {
-			log.Println("Error when parse message: ", err.Error())
-		}
+		h.dlq.Handle(msg, func() error {
+			return h.handle(sess.Context(), msg.Value)
+		})
 
-		log.Println("Event type: ", eventMsg.EventType)
-		log.Println("Event state: ", eventMsg.State)
-
-		switch eventMsg.EventType {
-		case event.BANK_ACCOUNT_REGISTRATION.String():
-			if eventMsg.State == event.BANK_BALANCE_CREATED.String() {
-				eventMsg, _ := event.FromJSON[dto.UpdateBankIDRequest, any](msg.Value)
-				err = h.u.UpdateUserMessaging(sess.Context(), eventMsg)
-			} else {
-				eventMsg, _ := event.FromJSON[dto.UserCreateRequest, any](msg.Value)
-				err = h.u.CreateUserMessaging(sess.Context(), eventMsg)
-			}
+		sess.MarkMessage(msg, "")
+	}
+	return nil
+}
 
-		case event.ORDER_PROCESS.String():
-			eventMsg, _ := event.FromJSON[dto.UserValidateRequest, any](msg.Value)
-			err = h.u.UserDetailMessaging(sess.Context(), eventMsg)
+func (h MessageHandler) handle(ctx context.Context, value []byte) error {
+	eventMsg, err := event.FromJSON[any, any](value)
+	if err != nil {
+		return deadletter.Unprocessable(err)
+	}
 
-		case event.ORDER_CANCEL_PROCESS.String():
-			eventMsg, _ := event.FromJSON[dto.UserValidateRequest, any](msg.Value)
-			err = h.u.UserDetailMessaging(sess.Context(), eventMsg)
+	log.Println("Event type: ", eventMsg.EventType)
+	log.Println("Event state: ", eventMsg.State)
+
+	switch eventMsg.EventType {
+	case event.BANK_ACCOUNT_REGISTRATION.String():
+		if eventMsg.State == event.BANK_BALANCE_CREATED.String() {
+			eventMsg, err := event.FromJSON[dto.UpdateBankIDRequest, any](value)
+			if err != nil {
+				return deadletter.Unprocessable(err)
+			}
+			return h.u.UpdateUserMessaging(ctx, eventMsg)
 		}
 
+		eventMsg, err := event.FromJSON[dto.UserCreateRequest, any](value)
 		if err != nil {
-			log.Println("Error when validate user: ", err.Error())
+			return deadletter.Unprocessable(err)
 		}
+		return h.u.CreateUserMessaging(ctx, eventMsg)
 
-		sess.MarkMessage(msg, "")
+	case event.ORDER_PROCESS.String(), event.ORDER_CANCEL_PROCESS.String():
+		eventMsg, err := event.FromJSON[dto.UserValidateRequest, any](value)
+		if err != nil {
+			return deadletter.Unprocessable(err)
+		}
+		return h.u.UserDetailMessaging(ctx, eventMsg)
 	}
-	return nil
+
+	return deadletter.UnknownEvent(eventMsg.EventType, eventMsg.State)
 }
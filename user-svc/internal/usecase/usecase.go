@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"github.com/google/uuid"
 	"log"
 	"user-svc/internal/dto"
 	"user-svc/internal/dto/event"
@@ -76,7 +75,7 @@ func (u *Usecase) CreateUserMessaging(ctx context.Context, ge event.GlobalEvent[
 		return fmt.Errorf("failed to convert event to JSON: %w", jsonErr)
 	}
 
-	sendErr := u.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+	sendErr := u.orchestraProducer.SendMessage(gevent.MessageKey(""), bytes)
 	if sendErr != nil {
 		return fmt.Errorf("failed to send message: %w", sendErr)
 	}
@@ -138,7 +137,7 @@ func (u *Usecase) UpdateUserMessaging(ctx context.Context, ge event.GlobalEvent[
 		return fmt.Errorf("failed to convert event to JSON: %w", jsonErr)
 	}
 
-	sendErr := u.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+	sendErr := u.orchestraProducer.SendMessage(gevent.MessageKey(""), bytes)
 
 	if sendErr != nil {
 		return fmt.Errorf("failed to send message: %w", sendErr)
@@ -200,7 +199,7 @@ func (u *Usecase) UserDetailMessaging(ctx context.Context, ge event.GlobalEvent[
 		return fmt.Errorf("failed to convert event to JSON: %w", jsonErr)
 	}
 
-	sendErr := u.orchestraProducer.SendMessage(uuid.New().String(), bytes)
+	sendErr := u.orchestraProducer.SendMessage(gevent.MessageKey(""), bytes)
 	if sendErr != nil {
 		return fmt.Errorf("failed to send message: %w", sendErr)
 	}